@@ -14,13 +14,14 @@ var (
 )
 
 func main() {
-	// If there is a config file in ~/.local/devxp/config.yml, use that.
-	// Otherwise, use the default .devxp file in the current directory.
-	// If neither is found, use default settings.
+	// Start from the default settings, then apply the user config file in ~/.local/devxp/config.yml, and finally
+	// the .devxp file in the current directory, so that repository settings take precedence. The Jira connection
+	// settings are only read from the user config file.
 	settings := config.DefaultSettings()
 	if userSettings, err := config.ReadConfig("~/.local/devxp/config.yml"); err == nil {
 		settings = config.MergeSettings(settings, userSettings)
-	} else if localSettings, err := config.ReadConfig(".devxp"); err == nil {
+	}
+	if localSettings, err := config.ReadRepoConfig(".devxp"); err == nil {
 		settings = config.MergeSettings(settings, localSettings)
 	}
 
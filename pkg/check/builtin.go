@@ -0,0 +1,138 @@
+// Package check provides the pre-PR check pipeline for gh-dxp.
+package check
+
+import (
+	"github.com/elhub/gh-dxp/pkg/config"
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/lint"
+	"github.com/elhub/gh-dxp/pkg/renovate"
+	"github.com/elhub/gh-dxp/pkg/secrets"
+	"github.com/elhub/gh-dxp/pkg/test"
+	"github.com/pkg/errors"
+)
+
+// FileExists checks to see whether a file exists in the file system.
+var FileExists = ghutil.FileExists //nolint: gochecknoglobals // Exported to allow mocking during tests.
+
+// builtin describes a check that is implemented by gh-dxp itself.
+type builtin struct {
+	// run executes the check and returns its status and a short summary.
+	run func(exe ghutil.Executor, settings *config.Settings, opts *Options) (Status, string, error)
+	// line renders the default checklist line for a result of this check.
+	line func(r Result) string
+}
+
+// builtins maps the names that can be used in the builtin field of a check to their implementation.
+var builtins = map[string]builtin{ //nolint: gochecknoglobals // Registry of built-in checks.
+	"lint":     {run: runLint, line: lintLine},
+	"test":     {run: runTest, line: testLine},
+	"renovate": {run: runRenovate, line: renovateLine},
+	"secrets":  {run: runSecrets, line: secretsLine},
+	"license":  {run: runLicense, line: licenseLine},
+}
+
+var errNoLicense = errors.New("no license file found in the repository root")
+
+// licenseFiles are the file names accepted as a license file in the repository root.
+var licenseFiles = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "COPYING"} //nolint: gochecknoglobals // Constant list.
+
+func runLint(exe ghutil.Executor, settings *config.Settings, opts *Options) (Status, string, error) {
+	if opts.NoLint {
+		return StatusDisabled, "The --nolint option was used", nil
+	}
+	if err := lint.Run(exe, settings, &lint.Options{}); err != nil {
+		return StatusFailed, "Lint checks failed", err
+	}
+	return StatusPassed, "Lint checks passed", nil
+}
+
+func lintLine(r Result) string {
+	switch r.Status {
+	case StatusPassed:
+		return "* ✅ Lint checks passed on local machine."
+	case StatusDisabled:
+		return "* ⛔ **This PR has not been linted! The --nolint option was used.**"
+	case StatusFailed:
+		return "* ⛔ **Lint checks failed on local machine.**"
+	case StatusSkipped:
+		return ""
+	default:
+		return "* ⛔ **This PR has not been linted! Unspecified lint error!** ⚠️"
+	}
+}
+
+func runTest(exe ghutil.Executor, _ *config.Settings, opts *Options) (Status, string, error) {
+	if opts.NoUnit {
+		return StatusDisabled, "The --nounit option was used", nil
+	}
+	tested, err := test.RunTest(exe)
+	if err != nil {
+		return StatusFailed, "Unit tests failed", err
+	}
+	if !tested {
+		return StatusUnavailable, "No test command could be detected", nil
+	}
+	return StatusPassed, "Unit tests passed", nil
+}
+
+func testLine(r Result) string {
+	switch r.Status {
+	case StatusPassed:
+		return "* ✅ Unit tests passed on local machine."
+	case StatusDisabled:
+		return "* ⛔ **This PR has not been unit tested! The --notest option was used.**"
+	case StatusFailed:
+		return "* ⛔ **Unit tests failed on local machine.**"
+	case StatusSkipped:
+		return ""
+	default:
+		return "* ⚠️ **No tests could be run for this PR.**"
+	}
+}
+
+func runRenovate(exe ghutil.Executor, settings *config.Settings, opts *Options) (Status, string, error) {
+	if opts.NoLint {
+		return StatusDisabled, "The --nolint option was used", nil
+	}
+	if err := renovate.Run(exe, settings, &renovate.Options{}); err != nil {
+		return StatusFailed, "Renovate config validation failed", err
+	}
+	return StatusPassed, "Renovate config is valid", nil
+}
+
+func renovateLine(r Result) string {
+	if r.Status == StatusFailed {
+		return "* ⛔ **Renovate config validation failed on local machine.**"
+	}
+	return ""
+}
+
+func runSecrets(exe ghutil.Executor, _ *config.Settings, opts *Options) (Status, string, error) {
+	if err := secrets.Run(exe, &secrets.Options{NonInteractive: opts.TestRun, BaseBranch: opts.BaseBranch}); err != nil {
+		return StatusFailed, "Potential secrets found in the changes", err
+	}
+	return StatusPassed, "No secrets found in the changes", nil
+}
+
+func secretsLine(r Result) string {
+	if r.Status == StatusFailed {
+		return "* ⛔ **Potential secrets were found in this PR by the local secret scan.**"
+	}
+	return ""
+}
+
+func runLicense(_ ghutil.Executor, _ *config.Settings, _ *Options) (Status, string, error) {
+	for _, file := range licenseFiles {
+		if FileExists(file) {
+			return StatusPassed, "Found license file " + file, nil
+		}
+	}
+	return StatusFailed, "No license file found in the repository root", errNoLicense
+}
+
+func licenseLine(r Result) string {
+	if r.Status == StatusFailed {
+		return "* ⛔ **No license file found in the repository.**"
+	}
+	return ""
+}
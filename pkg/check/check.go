@@ -0,0 +1,189 @@
+// Package check provides the pre-PR check pipeline for gh-dxp.
+package check
+
+import (
+	"context"
+	"strings"
+	"text/template"
+
+	"github.com/elhub/gh-dxp/pkg/config"
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+// DefaultChecks returns the checks that are run when no checks are configured in .devxp.
+func DefaultChecks() []config.Check {
+	return []config.Check{
+		{Builtin: "lint"},
+		{Builtin: "renovate"},
+		{Builtin: "secrets"},
+		{Builtin: "test"},
+	}
+}
+
+// Run runs the check pipeline defined in the settings (or the default pipeline if none is defined) and returns the
+// result of each check. If a required check fails, the pipeline stops and the error of that check is returned.
+func Run(exe ghutil.Executor, settings *config.Settings, opts *Options) ([]Result, error) {
+	checks := settings.Checks
+	if len(checks) == 0 {
+		checks = DefaultChecks()
+	}
+
+	if err := Validate(checks); err != nil {
+		return nil, err
+	}
+
+	var changedFiles []string
+	changedFilesLoaded := false
+
+	results := []Result{}
+	for _, c := range checks {
+		if len(c.When) > 0 {
+			if !changedFilesLoaded {
+				var err error
+				changedFiles, err = ghutil.GetChangedFiles(exe)
+				if err != nil {
+					return results, err
+				}
+				changedFilesLoaded = true
+			}
+
+			if !ghutil.CheckFilesMatchGlobs(changedFiles, c.When) {
+				logger.Infof("Skipping check %s as no changed files match its conditions", Name(c))
+				results = append(results, newResult(c, StatusSkipped, "No matching changes"))
+				continue
+			}
+		}
+
+		result, err := runCheck(exe, settings, c, opts)
+		results = append(results, result)
+		if err != nil {
+			if result.Required {
+				return results, err
+			}
+			logger.Warnf("Optional check %s failed: %s", result.Name, err.Error())
+		}
+	}
+
+	return results, nil
+}
+
+// Validate checks that a list of checks is well-formed.
+func Validate(checks []config.Check) error {
+	for i, c := range checks {
+		switch {
+		case c.Builtin == "" && c.Run == "":
+			return errors.Errorf("check #%d must define either builtin or run", i+1)
+		case c.Builtin != "" && c.Run != "":
+			return errors.Errorf("check %s cannot define both builtin and run", Name(c))
+		case c.Run != "" && c.Name == "":
+			return errors.Errorf("check #%d runs a custom command and must have a name", i+1)
+		}
+
+		if c.Builtin != "" {
+			if _, ok := builtins[c.Builtin]; !ok {
+				return errors.Errorf("check %s uses unknown builtin %q", Name(c), c.Builtin)
+			}
+		}
+
+		if c.Severity != "" && c.Severity != SeverityRequired && c.Severity != SeverityOptional {
+			return errors.Errorf("check %s has invalid severity %q (expected %s or %s)", Name(c), c.Severity,
+				SeverityRequired, SeverityOptional)
+		}
+
+		if c.Checklist != "" {
+			if _, err := template.New(Name(c)).Parse(c.Checklist); err != nil {
+				return errors.Wrapf(err, "check %s has an invalid checklist template", Name(c))
+			}
+		}
+	}
+	return nil
+}
+
+// Name returns the display name of a check. Built-in checks are named after the built-in if no name is given.
+func Name(c config.Check) string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Builtin
+}
+
+func runCheck(exe ghutil.Executor, settings *config.Settings, c config.Check, opts *Options) (Result, error) {
+	var status Status
+	var summary string
+	var err error
+
+	if b, ok := builtins[c.Builtin]; ok {
+		status, summary, err = b.run(exe, settings, opts)
+	} else {
+		status, summary, err = runCommand(exe, c)
+	}
+
+	return newResult(c, status, summary), err
+}
+
+func runCommand(exe ghutil.Executor, c config.Check) (Status, string, error) {
+	logger.Infof("Running check %s: %s", c.Name, c.Run)
+	err := exe.CommandContext(context.Background(), "sh", "-c", c.Run)
+	if err != nil {
+		return StatusFailed, c.Name + " failed: " + err.Error(), err
+	}
+	return StatusPassed, c.Name + " passed", nil
+}
+
+func newResult(c config.Check, status Status, summary string) Result {
+	result := Result{
+		Name:     Name(c),
+		Builtin:  c.Builtin,
+		Status:   status,
+		Required: c.Severity != SeverityOptional,
+		Summary:  summary,
+	}
+	result.Checklist = checklistLine(c, result)
+	return result
+}
+
+// checklistLine renders the checklist line for a result, using the check's template if one is configured.
+func checklistLine(c config.Check, r Result) string {
+	if c.Checklist != "" {
+		line, err := renderTemplate(c.Checklist, r)
+		if err == nil {
+			return line
+		}
+		logger.Warnf("Could not render checklist template for check %s: %s", r.Name, err.Error())
+	}
+
+	if b, ok := builtins[c.Builtin]; ok {
+		return b.line(r)
+	}
+	return defaultLine(r)
+}
+
+func renderTemplate(text string, r Result) (string, error) {
+	tmpl, err := template.New(r.Name).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, r); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func defaultLine(r Result) string {
+	switch r.Status {
+	case StatusPassed:
+		return "* ✅ " + r.Name + " passed on local machine."
+	case StatusFailed:
+		return "* ⛔ **" + r.Name + " failed on local machine.**"
+	case StatusDisabled:
+		return "* ⛔ **" + r.Name + " was not run.**"
+	case StatusSkipped:
+		return ""
+	default:
+		return "* ⚠️ **" + r.Name + " could not be run.**"
+	}
+}
@@ -0,0 +1,236 @@
+package check_test
+
+import (
+	"testing"
+
+	"github.com/elhub/gh-dxp/pkg/check"
+	"github.com/elhub/gh-dxp/pkg/config"
+	"github.com/elhub/gh-dxp/pkg/testutils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func mockChangedFiles(mockExe *testutils.MockExecutor, files string) {
+	mockExe.On("Command", "git", []string{"branch"}).Return("main\ndifferentBranch\n", nil)
+	mockExe.On("Command", "git", []string{"fetch", "origin", "main"}).Return("", nil)
+	mockExe.On("Command", "git", []string{"remote", "set-head", "origin", "--auto"}).Return("", nil)
+	mockExe.On("Command", "git", []string{"symbolic-ref", "--short", "refs/remotes/origin/HEAD"}).Return("origin/main", nil)
+	mockExe.On("Command", "git", []string{"diff", "--name-only", "origin/main", "--relative"}).Return(files, nil)
+}
+
+func TestRun_DefaultChecksDisabled(t *testing.T) {
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("Command", "git", []string{"symbolic-ref", "--short", "refs/remotes/origin/HEAD"}).Return("origin/main", nil)
+	mockExe.On("Command", "git", []string{"merge-base", "HEAD", "origin/main"}).Return("abc123\n", nil)
+	mockExe.On("Command", "git", []string{"diff", "--unified=0", "--no-color", "--no-ext-diff", "abc123"}).Return("", nil)
+
+	results, err := check.Run(mockExe, &config.Settings{}, &check.Options{TestRun: true, NoLint: true, NoUnit: true})
+
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.Equal(t, "lint", results[0].Name)
+	assert.Equal(t, check.StatusDisabled, results[0].Status)
+	assert.Equal(t, "* ⛔ **This PR has not been linted! The --nolint option was used.**", results[0].Checklist)
+	assert.Equal(t, "renovate", results[1].Name)
+	assert.Empty(t, results[1].Checklist)
+	assert.Equal(t, "secrets", results[2].Name)
+	assert.Equal(t, check.StatusPassed, results[2].Status)
+	assert.Empty(t, results[2].Checklist)
+	assert.Equal(t, "test", results[3].Name)
+	assert.Equal(t, "* ⛔ **This PR has not been unit tested! The --notest option was used.**", results[3].Checklist)
+	mockExe.AssertExpectations(t)
+}
+
+func TestRun_CustomChecks(t *testing.T) {
+	tests := []struct {
+		name           string
+		checks         []config.Check
+		changedFiles   string
+		commandErr     error
+		expectRun      bool
+		expectedStatus check.Status
+		expectedLine   string
+		expectedErr    string
+	}{
+		{
+			name:           "Custom command passes",
+			checks:         []config.Check{{Name: "OpenAPI", Run: "make openapi"}},
+			expectRun:      true,
+			expectedStatus: check.StatusPassed,
+			expectedLine:   "* ✅ OpenAPI passed on local machine.",
+		},
+		{
+			name:           "Required custom command fails",
+			checks:         []config.Check{{Name: "OpenAPI", Run: "make openapi"}},
+			commandErr:     errors.New("exit status 2"),
+			expectRun:      true,
+			expectedStatus: check.StatusFailed,
+			expectedLine:   "* ⛔ **OpenAPI failed on local machine.**",
+			expectedErr:    "exit status 2",
+		},
+		{
+			name:           "Optional custom command fails",
+			checks:         []config.Check{{Name: "OpenAPI", Run: "make openapi", Severity: "optional"}},
+			commandErr:     errors.New("exit status 2"),
+			expectRun:      true,
+			expectedStatus: check.StatusFailed,
+			expectedLine:   "* ⛔ **OpenAPI failed on local machine.**",
+		},
+		{
+			name:           "When condition matches changed files",
+			checks:         []config.Check{{Name: "OpenAPI", Run: "make openapi", When: []string{"api/**/*.yaml"}}},
+			changedFiles:   "api/v1/openapi.yaml\nREADME.md\n",
+			expectRun:      true,
+			expectedStatus: check.StatusPassed,
+			expectedLine:   "* ✅ OpenAPI passed on local machine.",
+		},
+		{
+			name:           "When condition does not match changed files",
+			checks:         []config.Check{{Name: "OpenAPI", Run: "make openapi", When: []string{"api/**/*.yaml"}}},
+			changedFiles:   "README.md\n",
+			expectRun:      false,
+			expectedStatus: check.StatusSkipped,
+			expectedLine:   "",
+		},
+		{
+			name: "Checklist template",
+			checks: []config.Check{{
+				Name:      "Terraform plan",
+				Run:       "terraform plan",
+				Checklist: "* {{.Icon}} {{.Name}}: {{.Status}}",
+			}},
+			expectRun:      true,
+			expectedStatus: check.StatusPassed,
+			expectedLine:   "* ✅ Terraform plan: passed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExe := new(testutils.MockExecutor)
+			if tt.changedFiles != "" {
+				mockChangedFiles(mockExe, tt.changedFiles)
+			}
+			if tt.expectRun {
+				mockExe.On("CommandContext", mock.Anything, "sh", []string{"-c", tt.checks[0].Run}).Return(nil, tt.commandErr)
+			}
+
+			results, err := check.Run(mockExe, &config.Settings{Checks: tt.checks}, &check.Options{})
+
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedErr, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			require.Len(t, results, 1)
+			assert.Equal(t, tt.expectedStatus, results[0].Status)
+			assert.Equal(t, tt.expectedLine, results[0].Checklist)
+			mockExe.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRun_RequiredFailureStopsPipeline(t *testing.T) {
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("CommandContext", mock.Anything, "sh", []string{"-c", "false"}).Return(nil, errors.New("exit status 1"))
+
+	checks := []config.Check{
+		{Name: "First", Run: "false"},
+		{Name: "Second", Run: "true"},
+	}
+	results, err := check.Run(mockExe, &config.Settings{Checks: checks}, &check.Options{})
+
+	require.Error(t, err)
+	require.Len(t, results, 1)
+	mockExe.AssertNotCalled(t, "CommandContext", mock.Anything, "sh", []string{"-c", "true"})
+}
+
+func TestRun_License(t *testing.T) {
+	original := check.FileExists
+	defer func() { check.FileExists = original }()
+
+	tests := []struct {
+		name           string
+		existingFile   string
+		expectedStatus check.Status
+		expectErr      bool
+	}{
+		{name: "License file exists", existingFile: "LICENSE.md", expectedStatus: check.StatusPassed},
+		{name: "License file missing", expectedStatus: check.StatusFailed, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check.FileExists = func(path string) bool { return path == tt.existingFile }
+
+			results, err := check.Run(new(testutils.MockExecutor),
+				&config.Settings{Checks: []config.Check{{Builtin: "license"}}}, &check.Options{})
+
+			if tt.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedStatus, results[0].Status)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		checks      []config.Check
+		errContains string
+	}{
+		{
+			name:   "Valid checks",
+			checks: []config.Check{{Builtin: "lint"}, {Name: "Plan", Run: "terraform plan", Severity: "optional"}},
+		},
+		{
+			name:        "Neither builtin nor run",
+			checks:      []config.Check{{Name: "Empty"}},
+			errContains: "must define either builtin or run",
+		},
+		{
+			name:        "Both builtin and run",
+			checks:      []config.Check{{Builtin: "lint", Run: "make lint"}},
+			errContains: "cannot define both builtin and run",
+		},
+		{
+			name:        "Custom command without name",
+			checks:      []config.Check{{Run: "make lint"}},
+			errContains: "must have a name",
+		},
+		{
+			name:        "Unknown builtin",
+			checks:      []config.Check{{Builtin: "typo"}},
+			errContains: "unknown builtin",
+		},
+		{
+			name:        "Invalid severity",
+			checks:      []config.Check{{Builtin: "lint", Severity: "critical"}},
+			errContains: "invalid severity",
+		},
+		{
+			name:        "Invalid checklist template",
+			checks:      []config.Check{{Builtin: "lint", Checklist: "{{.Name"}},
+			errContains: "invalid checklist template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := check.Validate(tt.checks)
+
+			if tt.errContains == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Package check provides the pre-PR check pipeline for gh-dxp.
+package check
+
+// Status represents the outcome of a single check.
+type Status string
+
+const (
+	// StatusPassed means the check ran and succeeded.
+	StatusPassed Status = "passed"
+	// StatusFailed means the check ran and failed.
+	StatusFailed Status = "failed"
+	// StatusDisabled means the check was disabled by a command line option (e.g., --nolint).
+	StatusDisabled Status = "disabled"
+	// StatusSkipped means none of the changed files matched the check's when conditions.
+	StatusSkipped Status = "skipped"
+	// StatusUnavailable means there was nothing to run (e.g., no test command could be detected).
+	StatusUnavailable Status = "unavailable"
+)
+
+const (
+	// SeverityRequired checks abort the pipeline when they fail. This is the default.
+	SeverityRequired = "required"
+	// SeverityOptional checks are reported, but do not abort the pipeline when they fail.
+	SeverityOptional = "optional"
+)
+
+// Options represents the options for running the check pipeline.
+type Options struct {
+	TestRun bool
+	NoLint  bool
+	NoUnit  bool
+
+	// BaseBranch is the branch the changes are compared to, such as the target branch of the pull request. The
+	// default branch is used if it is empty.
+	BaseBranch string
+}
+
+// Result represents the outcome of a single check in the pipeline.
+type Result struct {
+	Name      string
+	Builtin   string
+	Status    Status
+	Required  bool
+	Summary   string
+	Checklist string
+}
+
+// Icon returns the emoji used to represent the result's status in the PR checklist.
+func (r Result) Icon() string {
+	switch r.Status {
+	case StatusPassed:
+		return "✅"
+	case StatusFailed, StatusDisabled:
+		return "⛔"
+	case StatusSkipped:
+		return "➖"
+	default:
+		return "⚠️"
+	}
+}
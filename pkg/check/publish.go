@@ -0,0 +1,64 @@
+// Package check provides the pre-PR check pipeline for gh-dxp.
+package check
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+// maxDescriptionLength is the maximum length of a commit status description accepted by GitHub.
+const maxDescriptionLength = 140
+
+var nonContextChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// Publish records the results of the check pipeline as commit statuses on the given commit. Each result is published
+// with the context dxp/<check name>, so that branch protection rules can require them.
+func Publish(exe ghutil.Executor, sha string, results []Result) error {
+	for _, r := range results {
+		logger.Debugf("Publishing status %s for %s on %s", commitState(r.Status), StatusContext(r.Name), sha)
+		_, err := exe.GH("api", "--method", "POST", "repos/{owner}/{repo}/statuses/"+sha,
+			"-f", "state="+commitState(r.Status),
+			"-f", "context="+StatusContext(r.Name),
+			"-f", "description="+description(r),
+		)
+		if err != nil {
+			return errors.Wrapf(err, "failed to publish status for check %s", r.Name)
+		}
+	}
+	return nil
+}
+
+// StatusContext returns the commit status context used for a check with the given name (e.g., dxp/lint).
+func StatusContext(name string) string {
+	slug := nonContextChars.ReplaceAllString(strings.ToLower(name), "-")
+	return "dxp/" + strings.Trim(slug, "-")
+}
+
+// commitState maps a check status to a GitHub commit status state. Checks that were skipped because no relevant
+// files changed are reported as successful, while disabled checks are reported as errors so they cannot satisfy a
+// required status.
+func commitState(status Status) string {
+	switch status {
+	case StatusPassed, StatusSkipped:
+		return "success"
+	case StatusFailed:
+		return "failure"
+	default:
+		return "error"
+	}
+}
+
+func description(r Result) string {
+	desc := r.Summary
+	if desc == "" {
+		desc = string(r.Status)
+	}
+	if runes := []rune(desc); len(runes) > maxDescriptionLength {
+		desc = string(runes[:maxDescriptionLength-3]) + "..."
+	}
+	return desc
+}
@@ -0,0 +1,63 @@
+package check_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/elhub/gh-dxp/pkg/check"
+	"github.com/elhub/gh-dxp/pkg/testutils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func statusArgs(sha, state, context, description string) []string {
+	return []string{"api", "--method", "POST", "repos/{owner}/{repo}/statuses/" + sha,
+		"-f", "state=" + state, "-f", "context=" + context, "-f", "description=" + description}
+}
+
+func TestPublish(t *testing.T) {
+	results := []check.Result{
+		{Name: "lint", Status: check.StatusPassed, Summary: "Lint checks passed"},
+		{Name: "OpenAPI validation", Status: check.StatusFailed, Summary: "OpenAPI validation failed: exit status 2"},
+		{Name: "Terraform plan", Status: check.StatusSkipped, Summary: "No matching changes"},
+		{Name: "test", Status: check.StatusDisabled, Summary: "The --nounit option was used"},
+	}
+
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("GH", statusArgs("abc123", "success", "dxp/lint", "Lint checks passed")).Return("", nil)
+	mockExe.On("GH", statusArgs("abc123", "failure", "dxp/openapi-validation",
+		"OpenAPI validation failed: exit status 2")).Return("", nil)
+	mockExe.On("GH", statusArgs("abc123", "success", "dxp/terraform-plan", "No matching changes")).Return("", nil)
+	mockExe.On("GH", statusArgs("abc123", "error", "dxp/test", "The --nounit option was used")).Return("", nil)
+
+	err := check.Publish(mockExe, "abc123", results)
+
+	require.NoError(t, err)
+	mockExe.AssertExpectations(t)
+}
+
+func TestPublish_TruncatesDescription(t *testing.T) {
+	long := strings.Repeat("x", 200)
+	expected := strings.Repeat("x", 137) + "..."
+
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("GH", statusArgs("abc123", "success", "dxp/lint", expected)).Return("", nil)
+
+	err := check.Publish(mockExe, "abc123", []check.Result{{Name: "lint", Status: check.StatusPassed, Summary: long}})
+
+	require.NoError(t, err)
+	mockExe.AssertExpectations(t)
+}
+
+func TestPublish_Error(t *testing.T) {
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("GH", statusArgs("abc123", "success", "dxp/lint", "Lint checks passed")).
+		Return("", errors.New("HTTP 404"))
+
+	err := check.Publish(mockExe, "abc123",
+		[]check.Result{{Name: "lint", Status: check.StatusPassed, Summary: "Lint checks passed"}})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to publish status for check lint")
+}
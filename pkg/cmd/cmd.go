@@ -8,6 +8,7 @@ import (
 	"github.com/elhub/gh-dxp/pkg/config"
 	"github.com/elhub/gh-dxp/pkg/ghutil"
 	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/elhub/gh-dxp/pkg/output"
 	"github.com/spf13/cobra"
 )
 
@@ -55,9 +56,11 @@ func GenerateCmd(settings *config.Settings, version string) (*cobra.Command, err
 		AliasCmd(exe),
 		BranchCmd(exe),
 		LintCmd(exe, settings),
+		MetricsCmd(exe),
 		OwnerCmd(exe, settings),
 		PRCmd(exe, settings),
 		RepoCmd(exe, settings),
+		SecretsCmd(exe),
 		TestCmd(exe),
 		TemplateCmd(exe, settings),
 		StatusCmd(exe),
@@ -66,3 +69,28 @@ func GenerateCmd(settings *config.Settings, version string) (*cobra.Command, err
 
 	return retCmd, nil
 }
+
+// addJSONFlags adds the --json, --jq and --template flags shared by the commands with machine-readable output.
+func addJSONFlags(cmd *cobra.Command, opts *output.Options) {
+	fl := cmd.Flags()
+	fl.StringSliceVar(
+		&opts.Fields,
+		"json",
+		nil,
+		"Output JSON with the specified fields",
+	)
+	fl.StringVarP(
+		&opts.JQ,
+		"jq",
+		"",
+		"",
+		"Filter JSON output using a jq expression",
+	)
+	fl.StringVarP(
+		&opts.Template,
+		"template",
+		"t",
+		"",
+		"Format JSON output using a Go template",
+	)
+}
@@ -0,0 +1,91 @@
+// Package cmd provides CLI commands for the gh-dxp extension.
+package cmd
+
+import (
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/metrics"
+	"github.com/spf13/cobra"
+)
+
+// MetricsCmd creates a new cobra command for the pull request metrics of a repository or organization.
+func MetricsCmd(exe ghutil.Executor) *cobra.Command {
+	opts := &metrics.Options{}
+
+	cmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "Show review turnaround and throughput metrics for PRs",
+		Long: heredoc.Docf(`
+			Show how long the PRs (pull requests) created in a date range waited for their first review and to be
+			merged, how large they were, who reviewed them and how they were labelled. Without %[1]s--repo%[1]s or
+			%[1]s--org%[1]s, the PRs of the current repository are used, and the date range defaults to the last 30 days.
+
+			The times are measured from when a PR was opened. Reviews by the author of a PR are not counted, and the
+			review load is the number of PRs each person reviewed. The labels are those used by %[1]sgh dxp pr create%[1]s.
+
+			The report is printed for the terminal, or with %[1]s--format csv%[1]s as one row per PR and with
+			%[1]s--format json%[1]s as the full report. The fetched PRs are cached for a day, so repeated runs with the
+			same range are fast; use %[1]s--no-cache%[1]s to fetch them again.
+		`, "`"),
+		Example: heredoc.Doc(`
+			# Metrics for the current repository over the last 30 days
+			$ gh dxp metrics
+
+			# Metrics for an organization in the first quarter
+			$ gh dxp metrics --org elhub --since 2024-01-01 --until 2024-03-31
+
+			# Export the metrics of each PR for a spreadsheet
+			$ gh dxp metrics --repo elhub/gh-dxp --format csv > metrics.csv
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return metrics.Execute(exe, opts)
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.StringVar(
+		&opts.Repo,
+		"repo",
+		"",
+		"The repository (<owner>/<repo>) to show the metrics of",
+	)
+	fl.StringVar(
+		&opts.Org,
+		"org",
+		"",
+		"The organization to show the metrics of",
+	)
+	fl.StringVar(
+		&opts.Since,
+		"since",
+		"",
+		"Only include PRs created on or after this date (YYYY-MM-DD), defaults to 30 days before --until",
+	)
+	fl.StringVar(
+		&opts.Until,
+		"until",
+		"",
+		"Only include PRs created on or before this date (YYYY-MM-DD), defaults to today",
+	)
+	fl.StringVar(
+		&opts.Format,
+		"format",
+		metrics.FormatText,
+		"The output format: text, csv or json",
+	)
+	fl.BoolVar(
+		&opts.NoCache,
+		"no-cache",
+		false,
+		"Fetch the PRs again instead of using the cached PRs",
+	)
+	fl.DurationVar(
+		&opts.CacheTTL,
+		"cache-ttl",
+		metrics.DefaultCacheTTL,
+		"How long the fetched PRs are cached",
+	)
+
+	return cmd
+}
@@ -3,54 +3,136 @@ package cmd
 
 import (
 	"os"
+	"strconv"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/elhub/gh-dxp/pkg/config"
 	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/logger"
 	"github.com/elhub/gh-dxp/pkg/owner"
 	"github.com/spf13/cobra"
 )
 
 // OwnerCmd creates a new cobra command for retrieving code owner information.
 func OwnerCmd(exe ghutil.Executor, _ *config.Settings) *cobra.Command {
+	opts := &owner.Options{}
 	cmd := &cobra.Command{
 		Use:   "owner",
 		Short: "Determines the owner of the specified file.",
 		Args:  cobra.MaximumNArgs(1),
 		Long: heredoc.Docf(`
-			Determine the owner of the specified file based on the CODEOWNERS file given in the .github directory. If
-			no CODEOWNERS is found in the .github directory, it will return undefined.
+			Determine the owner of the specified file based on the CODEOWNERS file. Like GitHub, the file is read
+			from the .github directory, the repository root or the docs directory, in that order. Paths are relative
+			to the repository root.
+
+			With %[1]s--history%[1]s, the top contributors to the path according to git blame (for files) or git log
+			(for directories) are returned when no CODEOWNERS rule gives the path an owner. The source of the owners
+			is always printed.
+
+			Without a path, the owners of the files changed on the current branch are listed, each with the files
+			they own.
 		`, "`"),
 		Example: heredoc.Doc(`
 			# Check the owner of the README.md file
 			$ gh dxp owner README.md
+
+			# Fall back to the top contributors if the file has no code owner
+			$ gh dxp owner pkg/cmd/owner.go --history
+
+			# List the owners of the changes on the current branch
+			$ gh dxp owner
 		`),
 		RunE: func(_ *cobra.Command, args []string) error {
-			var path string
-			if len(args) > 0 {
-				path = args[0]
-			} else {
-				defaultPath, err := owner.GetDefaultFile(exe)
-				if err != nil {
-					return err
-				}
+			if err := ghutil.SetWorkDirToGitHubRoot(exe); err != nil {
+				return err
+			}
 
-				path = defaultPath
+			if len(args) == 0 {
+				return owner.ExecuteDiff(exe)
 			}
 
-			owners, err := owner.Execute(path, exe)
+			ownership, err := owner.Resolve(args[0], exe, opts)
+			if err != nil {
+				return err
+			}
 
 			// Output the owners
-			for _, owner := range owners {
+			for _, owner := range ownership.Owners {
 				_, err := os.Stdout.Write([]byte(owner + "\n"))
 				if err != nil {
 					return err
 				}
 			}
 
-			return err
+			switch {
+			case len(ownership.Owners) == 0:
+				logger.Info("No owner found in " + ownership.Source)
+			case ownership.Line > 0:
+				logger.Info("Source: " + ownership.Source + " (line " + strconv.Itoa(ownership.Line) + ")")
+			default:
+				logger.Info("Source: " + ownership.Source)
+			}
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.BoolVar(
+		&opts.History,
+		"history",
+		false,
+		"Fall back to the top contributors according to git if no CODEOWNERS rule matches",
+	)
+	fl.IntVar(
+		&opts.MaxContributors,
+		"max-contributors",
+		3,
+		"Number of top contributors to return with --history",
+	)
+
+	cmd.AddCommand(OwnerCheckCmd(exe))
+
+	return cmd
+}
+
+// OwnerCheckCmd creates a new cobra command for checking the CODEOWNERS file.
+func OwnerCheckCmd(exe ghutil.Executor) *cobra.Command {
+	opts := &owner.CheckOptions{}
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check the CODEOWNERS file for problems.",
+		Args:  cobra.NoArgs,
+		Long: heredoc.Docf(`
+			Check the CODEOWNERS file against all files tracked by git and report:
+
+			* files that have no owner
+			* rules that do not match any file
+			* rules that only match files owned through later rules
+			* teams that do not exist, and owners that lack write access to the repository
+			* lines with syntax errors
+
+			The command exits with an error if any problems are found, so it can be used in CI. Use %[1]s--json%[1]s
+			for machine-readable output.
+		`, "`"),
+		Example: heredoc.Doc(`
+			# Check the CODEOWNERS file
+			$ gh dxp owner check
+
+			# Check without calling the GitHub API and output JSON
+			$ gh dxp owner check --no-verify --json
+		`),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := ghutil.SetWorkDirToGitHubRoot(exe); err != nil {
+				return err
+			}
+			return owner.ExecuteCheck(exe, opts)
 		},
 	}
 
+	fl := cmd.Flags()
+	fl.BoolVar(&opts.JSON, "json", false, "Output the report as JSON")
+	fl.BoolVar(&opts.NoVerify, "no-verify", false, "Do not verify owners through the GitHub API")
+	fl.BoolVar(&opts.AllowUnowned, "allow-unowned", false, "Do not fail on files without an owner")
+
 	return cmd
 }
@@ -2,6 +2,9 @@
 package cmd
 
 import (
+	"strings"
+	"time"
+
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/elhub/gh-dxp/pkg/config"
 	"github.com/elhub/gh-dxp/pkg/ghutil"
@@ -20,9 +23,11 @@ func PRCmd(exe ghutil.Executor, settings *config.Settings) *cobra.Command {
 		`),
 	}
 
+	cmd.AddCommand(PRCheckoutCmd(exe, settings))
 	cmd.AddCommand(PRCreateCmd(exe, settings))
-	cmd.AddCommand(PRListCmd(exe))
-	cmd.AddCommand(PRMergeCmd(exe))
+	cmd.AddCommand(PRListCmd(exe, settings))
+	cmd.AddCommand(PRMergeCmd(exe, settings))
+	cmd.AddCommand(PRReviewCmd(exe))
 	cmd.AddCommand(PRUpdateCmd(exe, settings))
 
 	var opts = &pr.Options{}
@@ -50,6 +55,64 @@ func PRCmd(exe ghutil.Executor, settings *config.Settings) *cobra.Command {
 	return cmd
 }
 
+// PRCheckoutCmd handles the checking out of a pull request for review.
+func PRCheckoutCmd(exe ghutil.Executor, settings *config.Settings) *cobra.Command {
+	opts := &pr.CheckoutOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "checkout <number> | <url>",
+		Short: "Check out a PR (Pull Request) for review",
+		Long: heredoc.Docf(`
+			Check out a PR (pull request) locally to review it. This is an opinionated command that will:
+
+			* Check out the head branch of the PR, also if it is in a fork
+			* Run the post-checkout steps in %[1]sprCheckout.steps%[1]s of the .devxp file, such as installing dependencies
+			* Run the same lint and test checks as %[1]sgh dxp pr create%[1]s, if %[1]s--check%[1]s is given
+
+			The checks let you reproduce the results the author reported in the PR before approving it.
+		`, "`"),
+		Example: heredoc.Doc(`
+			# Check out PR 42 and install its dependencies
+			$ gh dxp pr checkout 42
+
+			# Check out a PR by its URL and run the checks
+			$ gh dxp pr checkout https://github.com/elhub/gh-dxp/pull/42 --check
+		`),
+		Aliases: []string{"co"},
+		Args:    cobra.ExactArgs(1),
+		RunE: func(prCmd *cobra.Command, args []string) error {
+			prOpts, err := getPrOptionsFromCmd(prCmd)
+			if err != nil {
+				return err
+			}
+			opts.NoLint = prOpts.NoLint
+			opts.NoUnit = prOpts.NoUnit
+
+			err = ghutil.SetWorkDirToGitHubRoot(exe)
+			if err != nil {
+				return err
+			}
+			return pr.ExecuteCheckout(exe, settings, args[0], opts)
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.BoolVar(
+		&opts.RunChecks,
+		"check",
+		false,
+		"Run the lint and test checks of pr create after checking out the PR",
+	)
+	fl.BoolVar(
+		&opts.NoSteps,
+		"no-steps",
+		false,
+		"Do not run the post-checkout steps configured in .devxp",
+	)
+
+	return cmd
+}
+
 // PRCreateCmd handles the creation of a pull request.
 func PRCreateCmd(exe ghutil.Executor, settings *config.Settings) *cobra.Command {
 	opts := &pr.CreateOptions{}
@@ -120,32 +183,86 @@ func PRCreateCmd(exe ghutil.Executor, settings *config.Settings) *cobra.Command
 		false,
 		"Mark pull request as draft",
 	)
+	fl.BoolVar(
+		&opts.PublishChecks,
+		"publish-checks",
+		false,
+		"Publish the result of each check as a commit status (dxp/<check>) on the pushed commit",
+	)
+	fl.BoolVar(
+		&opts.SuggestReviewers,
+		"suggest-reviewers",
+		false,
+		"Suggest the code owners of the changed files as reviewers",
+	)
 
 	return cmd
 }
 
 // PRListCmd handles the listing of pull requests.
-func PRListCmd(exe ghutil.Executor) *cobra.Command {
+func PRListCmd(exe ghutil.Executor, settings *config.Settings) *cobra.Command {
 	opts := &pr.ListOptions{}
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List your PRs (Pull Requests) in GitHub",
-		Long: heredoc.Doc(`
+		Long: heredoc.Docf(`
 			List your PRs (pull requests) in GitHub. This is a general command that can help you get an overview
 			of all your PRs that your user has.
-		`),
+
+			The PRs are shown in an interactive dashboard, where you can open, check out, diff, approve or request
+			changes on the selected PR, view its checks and filter the list (press %[1]s/%[1]s). The list is refreshed
+			automatically. Use %[1]s--no-tui%[1]s to print a table instead.
+
+			By default, your own PRs and the PRs that request your review are listed. The views selected with
+			%[1]s--team-review%[1]s, %[1]s--org%[1]s, %[1]s--repo%[1]s, %[1]s--reviewed%[1]s and %[1]s--stale%[1]s
+			replace them, unless %[1]s--mine%[1]s or %[1]s--review-requested%[1]s are also given. The views can be combined,
+			and a PR found by several views is listed once.
+
+			The columns can be chosen and reordered with %[1]sprList.columns%[1]s in the .devxp file. The available
+			columns are: %[3]s.
+
+			Use %[1]s--json%[1]s with a comma-separated list of fields to print the PRs as JSON, and %[1]s--jq%[1]s or
+			%[1]s--template%[1]s to filter or format it, like the gh commands do. The available fields are:
+			%[2]s.
+		`, "`", strings.Join(pr.ListFields(), ", "), strings.Join(pr.ListColumns(), ", ")),
 		Example: heredoc.Doc(`
 			# List all PRs
 			$ gh dxp pr list
+
+			# Print the PRs as a table, without the dashboard
+			$ gh dxp pr list --no-tui
+
+			# List the PRs waiting for my teams, and the PRs I reviewed that have new commits
+			$ gh dxp pr list --team-review --reviewed
+
+			# List all open PRs in an organization, and the PRs involving me that were opened over 14 days ago
+			$ gh dxp pr list --org elhub --stale 14
+
+			# Print the URLs of the PRs waiting for my review
+			$ gh dxp pr list --mine=false --json url --jq '.[].url'
+
+			# Print one line per PR with a template
+			$ gh dxp pr list --json number,repository,title --template '{{range .}}{{.repository}}#{{.number}} {{.title}}{{"\n"}}{{end}}'
 		`),
 		Args: cobra.NoArgs,
-		RunE: func(_ *cobra.Command, _ []string) error {
+		RunE: func(listCmd *cobra.Command, _ []string) error {
 			err := ghutil.SetWorkDirToGitHubRoot(exe)
 			if err != nil {
 				return err
 			}
 
+			// Selecting another view replaces the default views, unless they are also selected explicitly
+			otherViews := opts.TeamReview || opts.ReviewedNewCommits || len(opts.Orgs) > 0 || len(opts.Repos) > 0 ||
+				opts.StaleDays > 0
+			if otherViews && !listCmd.Flags().Changed("mine") {
+				opts.Mine = false
+			}
+			if otherViews && !listCmd.Flags().Changed("review-requested") {
+				opts.ReviewRequested = false
+			}
+
+			opts.Columns = settings.PRList.Columns
 			return pr.ExecuteList(exe, opts)
 		},
 	}
@@ -165,12 +282,55 @@ func PRListCmd(exe ghutil.Executor) *cobra.Command {
 		true,
 		"Show all pull requests that request reviews from me.",
 	)
+	fl.BoolVar(
+		&opts.NoTUI,
+		"no-tui",
+		false,
+		"Print the pull requests as a table instead of showing the interactive dashboard.",
+	)
+	fl.DurationVar(
+		&opts.RefreshInterval,
+		"refresh",
+		5*time.Minute,
+		"How often the dashboard refreshes the pull requests, 0 to disable.",
+	)
+	fl.BoolVar(
+		&opts.TeamReview,
+		"team-review",
+		false,
+		"Show the pull requests that request reviews from any of my teams.",
+	)
+	fl.StringSliceVar(
+		&opts.Orgs,
+		"org",
+		nil,
+		"Show all open pull requests in the organization. Can be given multiple times.",
+	)
+	fl.StringSliceVar(
+		&opts.Repos,
+		"repo",
+		nil,
+		"Show all open pull requests in the repository (<owner>/<repo>). Can be given multiple times.",
+	)
+	fl.BoolVar(
+		&opts.ReviewedNewCommits,
+		"reviewed",
+		false,
+		"Show the pull requests I have reviewed that have new commits since my review.",
+	)
+	fl.IntVar(
+		&opts.StaleDays,
+		"stale",
+		0,
+		"Show the pull requests involving me that were opened more than this many days ago.",
+	)
+	addJSONFlags(cmd, &opts.Output)
 
 	return cmd
 }
 
 // PRMergeCmd handles the merging of a pull request.
-func PRMergeCmd(exe ghutil.Executor) *cobra.Command {
+func PRMergeCmd(exe ghutil.Executor, settings *config.Settings) *cobra.Command {
 	opts := &pr.MergeOptions{}
 
 	cmd := &cobra.Command{
@@ -194,7 +354,7 @@ func PRMergeCmd(exe ghutil.Executor) *cobra.Command {
 				return err
 			}
 
-			return pr.ExecuteMerge(exe, opts)
+			return pr.ExecuteMerge(exe, settings, opts)
 		},
 	}
 
@@ -210,6 +370,82 @@ func PRMergeCmd(exe ghutil.Executor) *cobra.Command {
 	return cmd
 }
 
+// PRReviewCmd handles the reviewing of a pull request.
+func PRReviewCmd(exe ghutil.Executor) *cobra.Command {
+	opts := &pr.ReviewOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "review [<number> | <url>]",
+		Short: "Review a PR (Pull Request)",
+		Long: heredoc.Docf(`
+			Review a PR (pull request), given by its number or URL, or the PR of the current branch.
+
+			The diff of the PR is shown file by file. Move to a line and press %[1]sc%[1]s to comment on it. The
+			comments are collected in a single review, which is submitted when you approve (%[1]sa%[1]s), request
+			changes (%[1]sx%[1]s) or comment (%[1]sm%[1]s). If you quit with unsubmitted comments, they are kept in a
+			pending review on GitHub, which is submitted the next time you review the PR.
+
+			Use %[1]s--approve%[1]s, %[1]s--comment%[1]s or %[1]s--request-changes%[1]s to submit the review without
+			showing the diff.
+		`, "`"),
+		Example: heredoc.Doc(`
+			# Review the PR of the current branch
+			$ gh dxp pr review
+
+			# Review PR 42
+			$ gh dxp pr review 42
+
+			# Approve PR 42 without showing the diff
+			$ gh dxp pr review 42 --approve --body "Looks good"
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			err := ghutil.SetWorkDirToGitHubRoot(exe)
+			if err != nil {
+				return err
+			}
+
+			pullRequest := ""
+			if len(args) > 0 {
+				pullRequest = args[0]
+			}
+			return pr.ExecuteReview(exe, pullRequest, opts)
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.BoolVarP(
+		&opts.Approve,
+		"approve",
+		"a",
+		false,
+		"Approve the PR without showing the diff",
+	)
+	fl.BoolVarP(
+		&opts.Comment,
+		"comment",
+		"c",
+		false,
+		"Comment on the PR without showing the diff",
+	)
+	fl.BoolVarP(
+		&opts.RequestChanges,
+		"request-changes",
+		"x",
+		false,
+		"Request changes on the PR without showing the diff",
+	)
+	fl.StringVarP(
+		&opts.Body,
+		"body",
+		"b",
+		"",
+		"The comment of the review",
+	)
+
+	return cmd
+}
+
 // PRUpdateCmd handles the updating of a pull request. This is a more limited version of the create command.
 func PRUpdateCmd(exe ghutil.Executor, settings *config.Settings) *cobra.Command {
 	opts := &pr.UpdateOptions{}
@@ -220,10 +456,17 @@ func PRUpdateCmd(exe ghutil.Executor, settings *config.Settings) *cobra.Command
 		Long: heredoc.Docf(`
 			Update the current PR (pull request). This is essentially the same as the
 			create command, except that it expects an existing PR.
+
+			Use %[1]s--refresh-body%[1]s to regenerate the commit summary, check results and documentation line in
+			the PR description. Only the sections generated by %[1]spr create%[1]s are replaced, and anything you
+			wrote by hand is kept.
 		`, "`"),
 		Example: heredoc.Doc(`
 			# Update the current PR
 			$ gh dxp pr update
+
+			# Update the current PR and its description
+			$ gh dxp pr update --refresh-body
 		`),
 		Args: cobra.NoArgs,
 		RunE: func(prCmd *cobra.Command, _ []string) error {
@@ -241,6 +484,20 @@ func PRUpdateCmd(exe ghutil.Executor, settings *config.Settings) *cobra.Command
 		},
 	}
 
+	fl := cmd.Flags()
+	fl.BoolVar(
+		&opts.PublishChecks,
+		"publish-checks",
+		false,
+		"Publish the result of each check as a commit status (dxp/<check>) on the pushed commit",
+	)
+	fl.BoolVar(
+		&opts.RefreshBody,
+		"refresh-body",
+		false,
+		"Regenerate the sections of the PR description generated by pr create",
+	)
+
 	return cmd
 }
 
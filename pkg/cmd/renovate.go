@@ -16,11 +16,15 @@ func RenovateCmd(exe ghutil.Executor, settings *config.Settings) *cobra.Command
 		Short: "Work with renovate",
 		Args:  cobra.MaximumNArgs(1),
 		Long: heredoc.Doc(`
-			The renovate command group allows you to validate renovate config.
+			The renovate command group allows you to validate renovate config and see the updates renovate has
+			proposed.
 		`),
 	}
 
-	cmd.AddCommand(ValidateCmd(exe, settings))
+	cmd.AddCommand(
+		ValidateCmd(exe, settings),
+		RenovateStatusCmd(exe),
+	)
 
 	return cmd
 }
@@ -34,6 +38,13 @@ func ValidateCmd(exe ghutil.Executor, settings *config.Settings) *cobra.Command
 		Args:  cobra.MaximumNArgs(0),
 		Long: heredoc.Docf(`
 			Validate renovate config when changed.
+
+			The config is looked up in the locations renovate supports (for example %[1]srenovate.json%[1]s,
+			%[1]s.github/renovate.json5%[1]s, %[1]s.renovaterc%[1]s or the %[1]srenovate%[1]s key in %[1]spackage.json%[1]s).
+
+			By default the config is validated offline against an embedded renovate schema. Use
+			%[1]s--strict-remote%[1]s to validate with renovate's own validator through npx instead. The renovate
+			version used for that can be set with %[1]srenovateVersion%[1]s in .devxp.
 		`, "`"),
 		Example: heredoc.Doc(`
 			# Validate renovate config if modified from 'main' branch
@@ -41,6 +52,12 @@ func ValidateCmd(exe ghutil.Executor, settings *config.Settings) *cobra.Command
 
 			# Force validation of renovate config (for example even if it's unchanged)
 			$ gh dxp renovate validate --force
+
+			# Validate with renovate-config-validator (requires npx and network access)
+			$ gh dxp renovate validate --strict-remote
+
+			# Show how the resolved config, including extended presets, differs from 'main'
+			$ gh dxp renovate validate --explain
 		`),
 		RunE: func(_ *cobra.Command, _ []string) error {
 			err := ghutil.SetWorkDirToGitHubRoot(exe)
@@ -59,6 +76,59 @@ func ValidateCmd(exe ghutil.Executor, settings *config.Settings) *cobra.Command
 		false,
 		"Force validation even if there are no changes",
 	)
+	fl.BoolVar(
+		&opts.StrictRemote,
+		"strict-remote",
+		false,
+		"Validate with renovate-config-validator through npx instead of the offline validator",
+	)
+	fl.BoolVar(
+		&opts.Explain,
+		"explain",
+		false,
+		"Show a diff of the resolved config, including extended presets, against the base branch",
+	)
+
+	return cmd
+}
+
+// RenovateStatusCmd creates a new command for showing renovate pull requests and Dependency Dashboards.
+func RenovateStatusCmd(exe ghutil.Executor) *cobra.Command {
+	opts := &renovate.StatusOptions{}
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show pending renovate updates",
+		Args:  cobra.NoArgs,
+		Long: heredoc.Docf(`
+			Show the open renovate pull requests and Dependency Dashboards of the current repository, another
+			repository or a whole organization.
+
+			Pull requests are grouped by manager and package, with the update type, age and whether their checks
+			are failing. With %[1]s--approve-patch%[1]s, all patch updates whose checks have not failed are approved
+			and set to auto-merge.
+		`, "`"),
+		Example: heredoc.Doc(`
+			# Show renovate updates for the current repository
+			$ gh dxp renovate status
+
+			# Show renovate updates across an organization
+			$ gh dxp renovate status --org elhub
+
+			# Approve and auto-merge all passing patch updates without prompting
+			$ gh dxp renovate status --org elhub --approve-patch --yes
+		`),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return renovate.ExecuteStatus(exe, opts)
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.StringVar(&opts.Org, "org", "", "Show updates across all repositories in an organization")
+	fl.StringVarP(&opts.Repo, "repo", "R", "", "Show updates for the given repository (owner/repo)")
+	fl.StringVar(&opts.Author, "author", renovate.DefaultAuthor, "Login of the renovate bot")
+	fl.BoolVar(&opts.ApprovePatch, "approve-patch", false, "Approve and auto-merge patch updates with passing checks")
+	fl.BoolVarP(&opts.AutoConfirm, "yes", "y", false, "Do not ask for confirmation before approving")
+	cmd.MarkFlagsMutuallyExclusive("org", "repo")
 
 	return cmd
 }
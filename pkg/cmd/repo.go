@@ -12,7 +12,7 @@ import (
 )
 
 // RepoCmd extends the functionality of the gh repo command.
-func RepoCmd(exe ghutil.Executor, _ *config.Settings) *cobra.Command {
+func RepoCmd(exe ghutil.Executor, settings *config.Settings) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "repo",
 		Short: "Work with Repositories",
@@ -24,6 +24,8 @@ func RepoCmd(exe ghutil.Executor, _ *config.Settings) *cobra.Command {
 	}
 
 	cmd.AddCommand(RepoCloneCmd(exe))
+	cmd.AddCommand(RepoSyncCmd(exe))
+	cmd.AddCommand(RepoForeachCmd(exe, settings))
 
 	return cmd
 }
@@ -36,8 +38,21 @@ func RepoCloneCmd(exe ghutil.Executor) *cobra.Command {
 		Use:   "clone-all [<pattern>]",
 		Short: "Clone all repositories with a given name.",
 		Long: heredoc.Docf(`
-			Clones all repositories with a given name pattern. If no pattern is provided,
-			clone all repositories from the user's organizations.
+			Clones all repositories whose name contains the given pattern. If no pattern is provided,
+			clone all repositories from the user's organizations. The repositories of each organization
+			are listed page by page, waiting for the rate limit to reset when needed. A warning is shown
+			if the list had to be truncated.
+
+			Repositories are cloned in parallel, and the progress is recorded in a state file
+			(%[1]s.dxp-clone-all.json%[1]s by default). If a run is interrupted or some clones fail, run the
+			command again with %[1]s--resume%[1]s to continue where it left off.
+
+			The repositories can be filtered by organization, topic, language, visibility, team and the date
+			of the last push, and repositories can be excluded by name with glob patterns. Archived repositories
+			are skipped unless %[1]s--include-archived%[1]s is given.
+
+			By default, every repository is cloned into a directory with its name. Use %[1]s--layout {org}/{repo}%[1]s
+			to clone repositories with the same name in different organizations side by side.
 		`, "`"),
 		Example: heredoc.Doc(`
 			# Clone everything
@@ -48,6 +63,16 @@ func RepoCloneCmd(exe ghutil.Executor) *cobra.Command {
 
 			# Check what repositories would be cloned
 			$ gh dxp repo clone-all gh --dryrun
+
+			# Clone with 8 parallel workers, and resume an interrupted run
+			$ gh dxp repo clone-all --workers 8
+			$ gh dxp repo clone-all --workers 8 --resume
+
+			# Clone the Go repositories of a team pushed to this year, grouped by organization
+			$ gh dxp repo clone-all --team elhub/devxp --language go --pushed-after 2025-01-01 --layout "{org}/{repo}"
+
+			# Clone all repositories of an organization except the archived and the test repositories
+			$ gh dxp repo clone-all --org elhub --exclude "test-*"
 		`),
 		RunE: func(_ *cobra.Command, args []string) error {
 			pattern := ""
@@ -66,6 +91,218 @@ func RepoCloneCmd(exe ghutil.Executor) *cobra.Command {
 		false,
 		"Do not actually clone the repositories, just list the repositories that would be cloned.",
 	)
+	fl.IntVarP(
+		&opts.Workers,
+		"workers",
+		"w",
+		4,
+		"Number of repositories to clone in parallel",
+	)
+	fl.BoolVar(
+		&opts.Resume,
+		"resume",
+		false,
+		"Resume an interrupted run, skipping repositories that were already cloned",
+	)
+	fl.StringVar(
+		&opts.StateFile,
+		"state-file",
+		repo.DefaultStateFile,
+		"File to record the progress in",
+	)
+	fl.StringSliceVar(
+		&opts.Orgs,
+		"org",
+		nil,
+		"Only search the given organizations instead of all your organizations",
+	)
+	fl.StringSliceVar(
+		&opts.Topics,
+		"topic",
+		nil,
+		"Only clone repositories with all the given topics",
+	)
+	fl.StringVar(
+		&opts.Language,
+		"language",
+		"",
+		"Only clone repositories in the given language",
+	)
+	fl.StringVar(
+		&opts.Visibility,
+		"visibility",
+		"",
+		"Only clone repositories with the given visibility: {public|private|internal}",
+	)
+	fl.StringVar(
+		&opts.Team,
+		"team",
+		"",
+		"Only clone repositories the team (<org>/<team>) has access to",
+	)
+	fl.StringVar(
+		&opts.PushedAfter,
+		"pushed-after",
+		"",
+		"Only clone repositories pushed to on or after the date (YYYY-MM-DD)",
+	)
+	fl.StringSliceVar(
+		&opts.Exclude,
+		"exclude",
+		nil,
+		"Skip repositories whose name or <org>/<repo> matches the glob pattern",
+	)
+	fl.BoolVar(
+		&opts.IncludeArchived,
+		"include-archived",
+		false,
+		"Also clone archived repositories",
+	)
+	fl.StringVar(
+		&opts.Layout,
+		"layout",
+		repo.DefaultLayout,
+		"Directory to clone each repository into, using the placeholders {org} and {repo}",
+	)
+
+	return cmd
+}
+
+// RepoSyncCmd creates a new command to update all repositories cloned in the current directory.
+func RepoSyncCmd(exe ghutil.Executor) *cobra.Command {
+	opts := &repo.SyncOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "sync-all",
+		Short: "Update all repositories cloned in the current directory.",
+		Long: heredoc.Docf(`
+			Updates every repository cloned in a subdirectory of the current directory, for example by
+			%[1]sclone-all%[1]s. Each repository is fetched, and its default branch is fast-forwarded if it is
+			checked out and the working tree is clean.
+
+			Repositories with uncommitted changes, on another branch, ahead of or diverged from the default
+			branch, or archived or deleted on GitHub are left as they are and reported in the summary.
+		`, "`"),
+		Example: heredoc.Doc(`
+			# Update all repositories in the current directory
+			$ gh dxp repo sync-all
+
+			# Update with 8 parallel workers
+			$ gh dxp repo sync-all --workers 8
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return repo.ExecuteSync(exe, opts)
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.IntVarP(
+		&opts.Workers,
+		"workers",
+		"w",
+		4,
+		"Number of repositories to sync in parallel",
+	)
+
+	return cmd
+}
+
+// RepoForeachCmd creates a new command to run a command in all repositories cloned in the current directory.
+func RepoForeachCmd(exe ghutil.Executor, settings *config.Settings) *cobra.Command {
+	opts := &repo.ForeachOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "foreach [flags] -- <command> [<args>...]",
+		Short: "Run a command in all repositories cloned in the current directory.",
+		Long: heredoc.Docf(`
+			Runs a command in every repository cloned in a subdirectory of the current directory, for example by
+			%[1]sclone-all%[1]s. The command runs in parallel, and its output and exit code are printed per
+			repository once all runs are done. Use %[1]s--match%[1]s to only run in some repositories.
+
+			With %[1]s--branch%[1]s and %[1]s--commitmessage%[1]s, the changes the command makes in a repository are
+			committed on a new branch. Repositories with uncommitted changes are skipped in that case. With
+			%[1]s--pr%[1]s, a pull request is then created for each branch in the same way as %[1]spr create%[1]s, without
+			prompting.
+		`, "`"),
+		Example: heredoc.Doc(`
+			# Show the status of all repositories
+			$ gh dxp repo foreach -- git status --short
+
+			# Bump a workflow in all gh-* repositories and open a pull request in each
+			$ gh dxp repo foreach --match "gh-*" --branch bump-checkout -m "chore: bump checkout action" --pr -- \
+			    sed -i "s/checkout@v4/checkout@v5/" .github/workflows/build.yml
+		`),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return repo.ExecuteForeach(exe, settings, args, opts)
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.IntVarP(
+		&opts.Workers,
+		"workers",
+		"w",
+		4,
+		"Number of repositories to run the command in in parallel",
+	)
+	fl.StringSliceVar(
+		&opts.Match,
+		"match",
+		nil,
+		"Only run in repositories whose directory or name matches the glob pattern",
+	)
+	fl.StringVarP(
+		&opts.Branch,
+		"branch",
+		"b",
+		"",
+		"Commit the changes made by the command to a new branch with this name",
+	)
+	fl.StringVarP(
+		&opts.CommitMessage,
+		"commitmessage",
+		"m",
+		"",
+		"Commit message for the changes made by the command",
+	)
+	fl.BoolVar(
+		&opts.CreatePR,
+		"pr",
+		false,
+		"Create a pull request for the changes in each repository",
+	)
+	fl.StringVar(
+		&opts.Title,
+		"title",
+		"",
+		"Title of the pull requests (default: the commit message)",
+	)
+	fl.StringVar(
+		&opts.Body,
+		"body",
+		"",
+		"Description of the pull requests",
+	)
+	fl.BoolVar(
+		&opts.Draft,
+		"draft",
+		false,
+		"Mark the pull requests as draft",
+	)
+	fl.BoolVar(
+		&opts.NoLint,
+		"nolint",
+		false,
+		"Do not run linting before creating the pull requests",
+	)
+	fl.BoolVar(
+		&opts.NoUnit,
+		"nounit",
+		false,
+		"Do not run tests before creating the pull requests",
+	)
 
 	return cmd
 }
@@ -0,0 +1,67 @@
+// Package cmd provides CLI commands for the gh-dxp extension.
+package cmd
+
+import (
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/secrets"
+	"github.com/spf13/cobra"
+)
+
+// SecretsCmd creates a new command group for working with secrets in the repository.
+func SecretsCmd(exe ghutil.Executor) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "Detect secrets in your changes",
+		Long: heredoc.Doc(`
+			The secrets command group helps you avoid pushing credentials to GitHub.
+		`),
+	}
+
+	cmd.AddCommand(SecretsScanCmd(exe))
+
+	return cmd
+}
+
+// SecretsScanCmd creates a new command for scanning outgoing changes for secrets.
+func SecretsScanCmd(exe ghutil.Executor) *cobra.Command {
+	opts := &secrets.Options{}
+
+	cmd := &cobra.Command{
+		Use:   "scan",
+		Short: "Scan outgoing changes for secrets",
+		Args:  cobra.NoArgs,
+		Long: heredoc.Docf(`
+			Scan the changes between the default branch and your working tree (committed, staged and unstaged
+			changes to tracked files) for credentials such as access tokens, private keys and high-entropy
+			passwords.
+
+			Findings can be ignored for a single run, or added to the %[1]s.dxpsecretsignore%[1]s allowlist in the
+			repository root. The allowlist contains finding fingerprints or path globs, one per line.
+		`, "`"),
+		Example: heredoc.Doc(`
+			# Scan the outgoing changes and decide what to do with each finding
+			$ gh dxp secrets scan
+
+			# Fail on any finding without prompting (e.g., in CI)
+			$ gh dxp secrets scan --non-interactive
+		`),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			err := ghutil.SetWorkDirToGitHubRoot(exe)
+			if err != nil {
+				return err
+			}
+			return secrets.Run(exe, opts)
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.BoolVar(
+		&opts.NonInteractive,
+		"non-interactive",
+		false,
+		"Fail on findings instead of prompting for how to handle them",
+	)
+
+	return cmd
+}
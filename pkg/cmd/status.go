@@ -2,6 +2,8 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/elhub/gh-dxp/pkg/ghutil"
 	"github.com/elhub/gh-dxp/pkg/status"
@@ -25,10 +27,20 @@ func StatusCmd(exe ghutil.Executor) *cobra.Command {
 			* View assigned PRs/Review Requests
 
 			This command supports both interactive mode and non-interactive mode via flags for quick access to specific information.
-		`, "`"),
+
+			Use %[1]s--json%[1]s with a comma-separated list of fields to print the status as JSON, and %[1]s--jq%[1]s or
+			%[1]s--template%[1]s to filter or format it. Only the sections named by the fields are retrieved. The available
+			fields are: %[2]s.
+		`, "`", strings.Join(status.Fields(), ", ")),
 		Example: heredoc.Doc(`
 			# Interactive mode
 			$ gh dxp status
+
+			# Print the branches and the PRs of the current repository as JSON
+			$ gh dxp status --json branches,pullRequests
+
+			# Print the number of PRs waiting for my review
+			$ gh dxp status --json pullRequests --jq '.pullRequests.needsReview | length'
 		`),
 		Args: cobra.NoArgs,
 		RunE: func(_ *cobra.Command, _ []string) error {
@@ -76,6 +88,7 @@ func StatusCmd(exe ghutil.Executor) *cobra.Command {
 		false,
 		"Get all relavant Issues",
 	)
+	addJSONFlags(cmd, &opts.Output)
 
 	return cmd
 }
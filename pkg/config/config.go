@@ -3,13 +3,24 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
-// ReadConfig reads the configuration settings from the specified file and unmarshals it into a Settings struct.
-func ReadConfig(filepath string) (*Settings, error) {
-	data, readErr := os.ReadFile(filepath)
+// ReadConfig reads the configuration settings from the specified file and unmarshals it into a Settings struct. A
+// leading ~/ in the path is expanded to the home directory of the user.
+func ReadConfig(path string) (*Settings, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, rest)
+	}
+
+	data, readErr := os.ReadFile(path)
 	if readErr != nil {
 		return nil, readErr
 	}
@@ -22,6 +33,20 @@ func ReadConfig(filepath string) (*Settings, error) {
 	return &cfg, nil
 }
 
+// ReadRepoConfig reads the .devxp file of a repository like ReadConfig, but leaves out the Jira API URL, user and
+// token. Otherwise a repository could send the token of the user to any host, so these are only read from the user
+// config file and the environment.
+func ReadRepoConfig(path string) (*Settings, error) {
+	cfg, err := ReadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Jira.APIURL = ""
+	cfg.Jira.User = ""
+	cfg.Jira.Token = ""
+	return cfg, nil
+}
+
 // DefaultSettings loads the default .devxp settings.
 func DefaultSettings() *Settings {
 	return &Settings{
@@ -29,6 +54,10 @@ func DefaultSettings() *Settings {
 		JiraURL:                "https://elhub.atlassian.net/browse",
 		ProjectType:            "",
 		MegalinterImageVersion: "docker.jfrog.elhub.cloud/oxsecurity/megalinter-cupcake:v10.0.0",
+		RenovateVersion:        "43.78.0",
+		LargeFiles: LargeFiles{
+			MaxSize: "10MB",
+		},
 	}
 }
 
@@ -39,5 +68,64 @@ func MergeSettings(source, newSettings *Settings) *Settings {
 		source.ProjectType = newSettings.ProjectType
 	}
 
+	if newSettings.RenovateVersion != "" {
+		source.RenovateVersion = newSettings.RenovateVersion
+	}
+
+	if len(newSettings.Checks) > 0 {
+		source.Checks = newSettings.Checks
+	}
+
+	if newSettings.PublishChecks {
+		source.PublishChecks = true
+	}
+
+	if newSettings.LargeFiles.MaxSize != "" {
+		source.LargeFiles.MaxSize = newSettings.LargeFiles.MaxSize
+	}
+
+	if len(newSettings.LargeFiles.AllowedBinaries) > 0 {
+		source.LargeFiles.AllowedBinaries = newSettings.LargeFiles.AllowedBinaries
+	}
+
+	if newSettings.Reviewers.FromCodeOwners != "" {
+		source.Reviewers.FromCodeOwners = newSettings.Reviewers.FromCodeOwners
+	}
+
+	if newSettings.Reviewers.Max > 0 {
+		source.Reviewers.Max = newSettings.Reviewers.Max
+	}
+	if len(newSettings.PRList.Columns) > 0 {
+		source.PRList.Columns = newSettings.PRList.Columns
+	}
+
+	if len(newSettings.PRCheckout.Steps) > 0 {
+		source.PRCheckout.Steps = newSettings.PRCheckout.Steps
+	}
+
+	if newSettings.Jira.APIURL != "" {
+		source.Jira.APIURL = newSettings.Jira.APIURL
+	}
+
+	if newSettings.Jira.User != "" {
+		source.Jira.User = newSettings.Jira.User
+	}
+
+	if newSettings.Jira.Token != "" {
+		source.Jira.Token = newSettings.Jira.Token
+	}
+
+	if newSettings.Jira.ReviewTransition != "" {
+		source.Jira.ReviewTransition = newSettings.Jira.ReviewTransition
+	}
+
+	if newSettings.Jira.DoneTransition != "" {
+		source.Jira.DoneTransition = newSettings.Jira.DoneTransition
+	}
+
+	if newSettings.Jira.RemoteLinks {
+		source.Jira.RemoteLinks = true
+	}
+
 	return source
 }
@@ -2,6 +2,7 @@ package config_test
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/elhub/gh-dxp/pkg/config"
@@ -40,6 +41,38 @@ projectType: "go"`))
 		assert.Equal(t, "go", cfg.ProjectType)
 	})
 
+	t.Run("config file with checks", func(t *testing.T) {
+		tmpfile := writeTempFile(t, []byte(`---
+checks:
+  - builtin: lint
+  - name: OpenAPI validation
+    run: make openapi-validate
+    when: ["api/**/*.yaml"]
+    severity: optional`))
+
+		cfg, err := config.ReadConfig(tmpfile.Name())
+
+		require.NoError(t, err)
+		require.Len(t, cfg.Checks, 2)
+		assert.Equal(t, "lint", cfg.Checks[0].Builtin)
+		assert.Equal(t, "make openapi-validate", cfg.Checks[1].Run)
+		assert.Equal(t, []string{"api/**/*.yaml"}, cfg.Checks[1].When)
+		assert.Equal(t, "optional", cfg.Checks[1].Severity)
+	})
+
+	t.Run("config file in the home directory", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		require.NoError(t, os.MkdirAll(filepath.Join(home, ".local", "devxp"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(home, ".local", "devxp", "config.yml"),
+			[]byte("jira:\n  token: secret\n"), 0o600))
+
+		cfg, err := config.ReadConfig("~/.local/devxp/config.yml")
+
+		require.NoError(t, err)
+		assert.Equal(t, "secret", cfg.Jira.Token)
+	})
+
 	t.Run("non existent config file", func(t *testing.T) {
 		_, err := config.ReadConfig(".devxpp")
 		require.Error(t, err)
@@ -59,6 +92,23 @@ projectTypes:
 	})
 }
 
+func TestReadRepoConfig(t *testing.T) {
+	tmpfile := writeTempFile(t, []byte(`---
+jira:
+  apiUrl: https://jira.example.com
+  user: dev@elhub.no
+  token: secret
+  reviewTransition: In Review`))
+
+	cfg, err := config.ReadRepoConfig(tmpfile.Name())
+
+	require.NoError(t, err)
+	assert.Equal(t, config.Jira{ReviewTransition: "In Review"}, cfg.Jira)
+
+	_, err = config.ReadRepoConfig(".devxpp")
+	require.Error(t, err)
+}
+
 // Test the MergeSettings function.
 func TestMergeSettings(t *testing.T) {
 	defaultSettings := config.DefaultSettings()
@@ -69,4 +119,43 @@ func TestMergeSettings(t *testing.T) {
 	mergedSettings := config.MergeSettings(defaultSettings, userSettings)
 
 	assert.Equal(t, "go", mergedSettings.ProjectType)
+	assert.Empty(t, mergedSettings.Checks)
+
+	checkSettings := &config.Settings{
+		Checks: []config.Check{{Builtin: "test"}},
+	}
+	mergedSettings = config.MergeSettings(mergedSettings, checkSettings)
+
+	assert.Equal(t, "go", mergedSettings.ProjectType)
+	assert.Equal(t, checkSettings.Checks, mergedSettings.Checks)
+	assert.Equal(t, "43.78.0", mergedSettings.RenovateVersion)
+
+	mergedSettings = config.MergeSettings(mergedSettings, &config.Settings{RenovateVersion: "44.0.0"})
+
+	assert.Equal(t, "44.0.0", mergedSettings.RenovateVersion)
+
+	steps := []config.CheckoutStep{{Name: "Install dependencies", Run: "npm ci"}}
+	mergedSettings = config.MergeSettings(mergedSettings, &config.Settings{PRCheckout: config.PRCheckout{Steps: steps}})
+
+	assert.Equal(t, steps, mergedSettings.PRCheckout.Steps)
+
+	mergedSettings = config.MergeSettings(mergedSettings, &config.Settings{Jira: config.Jira{
+		APIURL: "https://elhub.atlassian.net",
+		User:   "dev@elhub.no",
+		Token:  "secret",
+	}})
+	mergedSettings = config.MergeSettings(mergedSettings, &config.Settings{Jira: config.Jira{
+		ReviewTransition: "In Review",
+		DoneTransition:   "Done",
+		RemoteLinks:      true,
+	}})
+
+	assert.Equal(t, config.Jira{
+		APIURL:           "https://elhub.atlassian.net",
+		User:             "dev@elhub.no",
+		Token:            "secret",
+		ReviewTransition: "In Review",
+		DoneTransition:   "Done",
+		RemoteLinks:      true,
+	}, mergedSettings.Jira)
 }
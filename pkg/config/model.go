@@ -2,8 +2,84 @@ package config
 
 // Settings represents the configuration settings for the gh-dxp extension.
 type Settings struct {
-	ProjectTemplateURI     string `yaml:"projectTemplateUri"`
-	ProjectType            string `yaml:"projectType"`
-	JiraURL                string `yaml:"jiraUrl"`
-	MegalinterImageVersion string `yaml:"megalinterImageVersion"`
+	ProjectTemplateURI     string     `yaml:"projectTemplateUri"`
+	ProjectType            string     `yaml:"projectType"`
+	JiraURL                string     `yaml:"jiraUrl"`
+	MegalinterImageVersion string     `yaml:"megalinterImageVersion"`
+	RenovateVersion        string     `yaml:"renovateVersion"`
+	Checks                 []Check    `yaml:"checks"`
+	PublishChecks          bool       `yaml:"publishChecks"`
+	LargeFiles             LargeFiles `yaml:"largeFiles"`
+	Reviewers              Reviewers  `yaml:"reviewers"`
+	PRList                 PRList     `yaml:"prList"`
+	PRCheckout             PRCheckout `yaml:"prCheckout"`
+	Jira                   Jira       `yaml:"jira"`
+}
+
+// Check represents a single step in the pre-PR check pipeline. A check either names a built-in (e.g., lint or test)
+// or a custom shell command to run.
+type Check struct {
+	Name      string   `yaml:"name"`
+	Builtin   string   `yaml:"builtin"`
+	Run       string   `yaml:"run"`
+	When      []string `yaml:"when"`
+	Severity  string   `yaml:"severity"`
+	Checklist string   `yaml:"checklist"`
+}
+
+// LargeFiles represents the settings for detecting large and binary files before pushing.
+type LargeFiles struct {
+	MaxSize         string   `yaml:"maxSize"`
+	AllowedBinaries []string `yaml:"allowedBinaries"`
+}
+
+// Modes for requesting reviews from code owners.
+const (
+	ReviewersSuggest = "suggest"
+	ReviewersAuto    = "auto"
+)
+
+// Reviewers represents the settings for requesting reviews from code owners when creating a pull request.
+type Reviewers struct {
+	// FromCodeOwners is either ReviewersSuggest (ask before requesting reviews) or ReviewersAuto (request reviews
+	// without asking). Any other value disables reviewer suggestions.
+	FromCodeOwners string `yaml:"fromCodeOwners"`
+	Max            int    `yaml:"max"`
+}
+
+// PRList represents the settings for the pr list command.
+type PRList struct {
+	// Columns are the columns of the list, in order, for example [repository, number, title, checks, age].
+	Columns []string `yaml:"columns"`
+}
+
+// PRCheckout represents the settings for the pr checkout command.
+type PRCheckout struct {
+	// Steps are run in order after a pull request is checked out, for example to install its dependencies.
+	Steps []CheckoutStep `yaml:"steps"`
+}
+
+// CheckoutStep represents a shell command that is run after a pull request is checked out.
+type CheckoutStep struct {
+	Name string `yaml:"name"`
+	Run  string `yaml:"run"`
+}
+
+// Jira represents the settings for looking up and updating the Jira issues of a pull request. The URL, user and token
+// can also be set with the JIRA_API_URL, JIRA_USER and JIRA_API_TOKEN environment variables, which take precedence.
+type Jira struct {
+	// APIURL is the base URL of the Jira REST API, for example https://elhub.atlassian.net. The issues are only looked
+	// up if it is set.
+	APIURL string `yaml:"apiUrl"`
+	// User is the user the token belongs to. The token is sent as a bearer token (a personal access token) if no user
+	// is given, and with basic authentication (an API token) otherwise.
+	User  string `yaml:"user"`
+	Token string `yaml:"token"`
+
+	// ReviewTransition and DoneTransition are the transitions (or the statuses they lead to) applied to the issues
+	// when the pull request is created and merged, for example "In Review" and "Done". Empty disables them.
+	ReviewTransition string `yaml:"reviewTransition"`
+	DoneTransition   string `yaml:"doneTransition"`
+	// RemoteLinks adds a link to the pull request to the issues when it is created.
+	RemoteLinks bool `yaml:"remoteLinks"`
 }
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/testutils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -45,6 +46,19 @@ func TestLinuxExecutor_Command(t *testing.T) {
 	})
 }
 
+func TestQuietExecutor(t *testing.T) {
+	executor := ghutil.LinuxExecutor()
+
+	quiet := ghutil.QuietExecutor(executor)
+
+	require.IsType(t, &ghutil.LinuxExecutorImpl{}, quiet)
+	assert.True(t, quiet.(*ghutil.LinuxExecutorImpl).Quiet)
+	assert.False(t, executor.Quiet)
+
+	mockExe := new(testutils.MockExecutor)
+	assert.Same(t, mockExe, ghutil.QuietExecutor(mockExe))
+}
+
 func TestLinuxExecutor_CommandContext(t *testing.T) {
 	// Note: These tests rely on 'true' and 'sleep' being available in the path,
 	// which is standard for Linux environments.
@@ -3,6 +3,7 @@ package ghutil
 
 import (
 	"os"
+	"path"
 	"regexp"
 	"strings"
 
@@ -80,6 +81,58 @@ func CheckFilesUpdated(changedFiles, patterns []string) bool {
 	return false
 }
 
+// CheckFilesMatchGlobs checks if any of the changed files match any of the given glob patterns.
+func CheckFilesMatchGlobs(changedFiles, globs []string) bool {
+	for _, file := range changedFiles {
+		for _, glob := range globs {
+			if MatchesGlob(glob, file) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MatchesGlob reports whether a file path matches a glob pattern. In addition to the wildcards supported by
+// path.Match, "**" matches any number of directories. Patterns without a slash are matched against the file name only.
+func MatchesGlob(glob, file string) bool {
+	file = strings.TrimPrefix(file, "/")
+	if !strings.Contains(glob, "/") {
+		file = path.Base(file)
+	}
+
+	re, err := regexp.Compile(globToRegex(strings.TrimPrefix(glob, "/")))
+	if err != nil {
+		return false
+	}
+	return re.MatchString(file)
+}
+
+// globToRegex converts a glob pattern into an anchored regular expression.
+func globToRegex(glob string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			sb.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return sb.String()
+}
+
 // GetUntrackedChanges returns a list of file names for unchanged files in the current repo.
 func GetUntrackedChanges(exe Executor) ([]string, error) {
 	re := regexp.MustCompile(`^\?\?`)
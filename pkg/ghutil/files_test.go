@@ -374,6 +374,65 @@ func TestCheckFilesUpdated(t *testing.T) {
 	}
 }
 
+func TestCheckFilesMatchGlobs(t *testing.T) {
+	tests := []struct {
+		name          string
+		changedFiles  []string
+		globs         []string
+		expectedMatch bool
+	}{
+		{
+			name:          "No files changed",
+			changedFiles:  []string{},
+			globs:         []string{"*.go"},
+			expectedMatch: false,
+		},
+		{
+			name:          "File name glob matches in any directory",
+			changedFiles:  []string{"pkg/cmd/lint.go"},
+			globs:         []string{"*.go"},
+			expectedMatch: true,
+		},
+		{
+			name:          "Directory glob with double star",
+			changedFiles:  []string{"infra/modules/network/main.tf"},
+			globs:         []string{"infra/**/*.tf"},
+			expectedMatch: true,
+		},
+		{
+			name:          "Double star matches zero directories",
+			changedFiles:  []string{"infra/main.tf"},
+			globs:         []string{"infra/**/*.tf"},
+			expectedMatch: true,
+		},
+		{
+			name:          "Single star does not cross directories",
+			changedFiles:  []string{"api/v1/openapi.yaml"},
+			globs:         []string{"api/*.yaml"},
+			expectedMatch: false,
+		},
+		{
+			name:          "Leading slash is ignored",
+			changedFiles:  []string{"/docs/index.md"},
+			globs:         []string{"docs/**"},
+			expectedMatch: true,
+		},
+		{
+			name:          "No globs matched",
+			changedFiles:  []string{"main.go", "utils/helper.go"},
+			globs:         []string{"*.tf", "docs/**"},
+			expectedMatch: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := ghutil.CheckFilesMatchGlobs(tt.changedFiles, tt.globs)
+			assert.Equal(t, tt.expectedMatch, match)
+		})
+	}
+}
+
 func TestGetTrackedChanges(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -19,6 +19,8 @@ import (
 // LinuxExecutorImpl is the type of the Executor interface for Linux systems.
 type LinuxExecutorImpl struct {
 	ExecCommand func(name string, args ...string) *exec.Cmd
+	// Quiet turns off the logging of commands and their errors, e.g. while a terminal UI owns the screen.
+	Quiet bool
 }
 
 // LinuxExecutor returns a new LinuxExecutorImpl.
@@ -28,15 +30,27 @@ func LinuxExecutor() *LinuxExecutorImpl {
 	}
 }
 
+// QuietExecutor returns a copy of the executor that does not log, or the executor itself if it is not a
+// LinuxExecutorImpl.
+func QuietExecutor(exe Executor) Executor {
+	linux, ok := exe.(*LinuxExecutorImpl)
+	if !ok {
+		return exe
+	}
+	quiet := *linux
+	quiet.Quiet = true
+	return &quiet
+}
+
 // Command runs an OS command and returns its output.
 func (e *LinuxExecutorImpl) Command(name string, args ...string) (string, error) {
-	logger.Debug(fmt.Sprintf("Running '%s %s'", name, strings.Join(args, " ")))
+	e.debug(fmt.Sprintf("Running '%s %s'", name, strings.Join(args, " ")))
 	cmd := e.ExecCommand(name, args...)
 	bytes, err := cmd.CombinedOutput()
 
 	outputString := string(bytes)
 
-	if err != nil && outputString != "" {
+	if err != nil && outputString != "" && !e.Quiet {
 		logger.Error(outputString)
 	}
 	return string(bytes), err
@@ -90,16 +104,24 @@ func (e *LinuxExecutorImpl) CommandContext(ctx context.Context, name string, arg
 
 // GH runs a GitHub CLI command and returns its output.
 func (e *LinuxExecutorImpl) GH(args ...string) (string, error) {
-	logger.Debug(fmt.Sprintf("Running gh '%s'", strings.Join(args, " ")))
+	e.debug(fmt.Sprintf("Running gh '%s'", strings.Join(args, " ")))
 	stdOut, stdErr, err := gh.Exec(args...)
 	if err != nil {
-		logger.Error(stdErr.String())
-		logger.Debug(fmt.Sprintf("Error running GH command: %s", err.Error()))
+		if !e.Quiet {
+			logger.Error(stdErr.String())
+		}
+		e.debug(fmt.Sprintf("Error running GH command: %s", err.Error()))
 		return stdErr.String(), err
 	}
 	return stdOut.String(), err
 }
 
+func (e *LinuxExecutorImpl) debug(msg string) {
+	if !e.Quiet {
+		logger.Debug(msg)
+	}
+}
+
 // Chdir changes the current working directory.
 func (e *LinuxExecutorImpl) Chdir(dir string) error {
 	return os.Chdir(dir)
@@ -0,0 +1,214 @@
+// Package jira provides the lookup and updating of the Jira issues of pull requests.
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/elhub/gh-dxp/pkg/config"
+	"github.com/pkg/errors"
+)
+
+// DefaultClient is the HTTP client used to call Jira, if no other client is given.
+var DefaultClient = &http.Client{Timeout: 10 * time.Second} //nolint: gochecknoglobals // Shared HTTP client.
+
+// issueKeyRegex matches Jira issue keys like TDX-123.
+var issueKeyRegex = regexp.MustCompile( //nolint: gochecknoglobals // Compiled once.
+	`\b[A-Z][A-Z0-9]{1,9}-[1-9][0-9]*\b`)
+
+// IssueNotFoundError signifies that Jira does not know the issue, or that it is not visible to the user.
+type IssueNotFoundError struct {
+	Key string
+}
+
+func (e *IssueNotFoundError) Error() string {
+	return fmt.Sprintf("issue %s was not found in Jira", e.Key)
+}
+
+// NewClient returns a client for the Jira REST API in the settings, or nil if no API URL is configured. The URL, user
+// and token are read from the JIRA_API_URL, JIRA_USER and JIRA_API_TOKEN environment variables if they are set. If
+// httpClient is nil, DefaultClient is used.
+func NewClient(settings config.Jira, httpClient *http.Client) (*Client, error) {
+	baseURL := envOr("JIRA_API_URL", settings.APIURL)
+	if baseURL == "" {
+		return nil, nil
+	}
+
+	// http is allowed for a Jira stand-in on the local machine
+	parsed, err := url.Parse(baseURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return nil, errors.Errorf("invalid Jira API URL %q, use for example https://elhub.atlassian.net", baseURL)
+	}
+
+	if httpClient == nil {
+		httpClient = DefaultClient
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		user:    envOr("JIRA_USER", settings.User),
+		token:   envOr("JIRA_API_TOKEN", settings.Token),
+		http:    httpClient,
+	}, nil
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// DetectKeys returns the distinct issue keys in the texts, such as branch names and commit messages, in the order
+// they are found.
+func DetectKeys(texts ...string) []string {
+	var keys []string
+	seen := map[string]bool{}
+	for _, text := range texts {
+		for _, key := range issueKeyRegex.FindAllString(text, -1) {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// Issue looks up the summary and status of an issue.
+func (c *Client) Issue(key string) (Issue, error) {
+	var res issueResponse
+	status, err := c.do(http.MethodGet, "/rest/api/2/issue/"+url.PathEscape(key)+"?fields=summary,status", nil, &res)
+	if status == http.StatusNotFound {
+		return Issue{}, &IssueNotFoundError{Key: key}
+	}
+	if err != nil {
+		return Issue{}, errors.Wrapf(err, "failed to look up issue %s", key)
+	}
+	return Issue{Key: res.Key, Summary: res.Fields.Summary, Status: res.Fields.Status.Name}, nil
+}
+
+// Transition moves the issue with the transition that has the given name or leads to the status with that name. It
+// returns false if the issue already has that status.
+func (c *Client) Transition(key, name string) (bool, error) {
+	issue, err := c.Issue(key)
+	if err != nil {
+		return false, err
+	}
+	if strings.EqualFold(issue.Status, name) {
+		return false, nil
+	}
+
+	var res transitionsResponse
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "/transitions"
+	if _, err := c.do(http.MethodGet, path, nil, &res); err != nil {
+		return false, errors.Wrapf(err, "failed to list the transitions of issue %s", key)
+	}
+
+	var names []string
+	for _, t := range res.Transitions {
+		if strings.EqualFold(t.Name, name) || strings.EqualFold(t.To.Name, name) {
+			var req transitionRequest
+			req.Transition.ID = t.ID
+			if _, err := c.do(http.MethodPost, path, req, nil); err != nil {
+				return false, errors.Wrapf(err, "failed to move issue %s to %s", key, name)
+			}
+			return true, nil
+		}
+		names = append(names, t.Name)
+	}
+	return false, errors.Errorf("issue %s cannot be moved from %s to %s, the available transitions are: %s", key,
+		issue.Status, name, strings.Join(names, ", "))
+}
+
+// AddRemoteLink links the issue to a URL, such as the URL of a pull request. Adding the same URL again updates the
+// existing link.
+func (c *Client) AddRemoteLink(key, linkURL, title string) error {
+	req := remoteLinkRequest{GlobalID: linkURL, Object: remoteLinkObject{URL: linkURL, Title: title}}
+	if _, err := c.do(http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(key)+"/remotelink", req, nil); err != nil {
+		return errors.Wrapf(err, "failed to link issue %s to %s", key, linkURL)
+	}
+	return nil
+}
+
+// do sends a request to the Jira REST API, and decodes the JSON response into out if it is not nil. It returns the
+// HTTP status code, and an error if the request failed or the status is not successful.
+func (c *Client) do(method, path string, body, out any) (int, error) {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), method, c.baseURL+path, reqBody)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case c.user != "":
+		req.SetBasicAuth(c.user, c.token)
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req) //nolint:gosec // G704: The URL is the configured Jira API URL.
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, errors.New("Jira responded with " + resp.Status + errorMessages(data))
+	}
+
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return resp.StatusCode, errors.Wrap(err, "failed to unmarshal the Jira response")
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// errorMessages returns the error messages of a Jira error response, if there are any.
+func errorMessages(data []byte) string {
+	var res struct {
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	}
+	if json.Unmarshal(data, &res) != nil {
+		return ""
+	}
+	messages := res.ErrorMessages
+	fields := make([]string, 0, len(res.Errors))
+	for field := range res.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		messages = append(messages, field+": "+res.Errors[field])
+	}
+	if len(messages) == 0 {
+		return ""
+	}
+	return ": " + strings.Join(messages, "; ")
+}
@@ -0,0 +1,212 @@
+package jira_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/elhub/gh-dxp/pkg/config"
+	"github.com/elhub/gh-dxp/pkg/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeJira is a Jira stand-in with issues in the "To Do" status, which can be moved to "In Review" or "Done".
+type fakeJira struct {
+	statuses map[string]string
+	requests []string
+	bodies   []string
+	auth     string
+}
+
+func newFakeJira(t *testing.T) (*fakeJira, *httptest.Server) {
+	fake := &fakeJira{statuses: map[string]string{"TDX-1": "To Do", "TDX-2": "Done"}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		fake.requests = append(fake.requests, r.Method+" "+r.URL.RequestURI())
+		fake.bodies = append(fake.bodies, string(body))
+		fake.auth = r.Header.Get("Authorization")
+
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/"), "/")
+		status, ok := fake.statuses[parts[0]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errorMessages": ["Issue does not exist or you do not have permission to see it."]}`))
+			return
+		}
+
+		switch {
+		case len(parts) == 1:
+			_, _ = w.Write([]byte(`{"key": "` + parts[0] + `", "fields": {"summary": "Fix login", "status": {"name": "` +
+				status + `"}}}`))
+		case parts[1] == "transitions" && r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"transitions": [{"id": "21", "name": "Start review", "to": {"name": "In Review"}},
+				{"id": "31", "name": "Done", "to": {"name": "Done"}}]}`))
+		case parts[1] == "transitions":
+			w.WriteHeader(http.StatusNoContent)
+		case parts[1] == "remotelink":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 10000}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors": {"summary": "required"}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func newClient(t *testing.T, settings config.Jira) *jira.Client {
+	t.Helper()
+	client, err := jira.NewClient(settings, nil)
+	require.NoError(t, err)
+	require.NotNil(t, client)
+	return client
+}
+
+func TestDetectKeys(t *testing.T) {
+	keys := jira.DetectKeys("feature/TDX-123-login", "TDX-123 Add login page\nFix EDIEL-45 and TDX-7", "no keys, v1-2")
+
+	assert.Equal(t, []string{"TDX-123", "EDIEL-45", "TDX-7"}, keys)
+	assert.Empty(t, jira.DetectKeys("main", "tdx-123 lower case"))
+}
+
+func TestNewClient(t *testing.T) {
+	t.Setenv("JIRA_API_URL", "")
+	t.Setenv("JIRA_USER", "")
+	t.Setenv("JIRA_API_TOKEN", "")
+
+	t.Run("is nil without an API URL", func(t *testing.T) {
+		client, err := jira.NewClient(config.Jira{ReviewTransition: "In Review"}, nil)
+
+		require.NoError(t, err)
+		assert.Nil(t, client)
+	})
+
+	t.Run("rejects an invalid API URL", func(t *testing.T) {
+		_, err := jira.NewClient(config.Jira{APIURL: "ftp://jira"}, nil)
+
+		require.EqualError(t, err, `invalid Jira API URL "ftp://jira", use for example https://elhub.atlassian.net`)
+	})
+
+	t.Run("sends the token as a bearer token without a user", func(t *testing.T) {
+		fake, server := newFakeJira(t)
+
+		_, err := newClient(t, config.Jira{APIURL: server.URL, Token: "secret"}).Issue("TDX-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer secret", fake.auth)
+	})
+
+	t.Run("reads the settings from the environment", func(t *testing.T) {
+		fake, server := newFakeJira(t)
+		t.Setenv("JIRA_API_URL", server.URL+"/")
+		t.Setenv("JIRA_USER", "dev@elhub.no")
+		t.Setenv("JIRA_API_TOKEN", "env-token")
+
+		_, err := newClient(t, config.Jira{APIURL: "https://unused.example.com", Token: "secret"}).Issue("TDX-1")
+
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(fake.auth, "Basic "))
+		assert.Equal(t, []string{"GET /rest/api/2/issue/TDX-1?fields=summary,status"}, fake.requests)
+	})
+}
+
+func TestIssue(t *testing.T) {
+	_, server := newFakeJira(t)
+	client := newClient(t, config.Jira{APIURL: server.URL})
+
+	issue, err := client.Issue("TDX-1")
+	require.NoError(t, err)
+	assert.Equal(t, jira.Issue{Key: "TDX-1", Summary: "Fix login", Status: "To Do"}, issue)
+
+	_, err = client.Issue("TDX-404")
+	require.EqualError(t, err, "issue TDX-404 was not found in Jira")
+	var notFound *jira.IssueNotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, "TDX-404", notFound.Key)
+}
+
+func TestTransition(t *testing.T) {
+	tests := []struct {
+		name          string
+		key           string
+		transition    string
+		expectedMoved bool
+		expectedBody  string
+		expectedErr   string
+	}{
+		{
+			name:          "moves the issue with the transition to the status",
+			key:           "TDX-1",
+			transition:    "in review",
+			expectedMoved: true,
+			expectedBody:  `{"transition":{"id":"21"}}`,
+		},
+		{
+			name:          "moves the issue with the transition name",
+			key:           "TDX-1",
+			transition:    "Start review",
+			expectedMoved: true,
+			expectedBody:  `{"transition":{"id":"21"}}`,
+		},
+		{
+			name:       "does not move an issue that already has the status",
+			key:        "TDX-2",
+			transition: "Done",
+		},
+		{
+			name:        "fails if there is no such transition",
+			key:         "TDX-1",
+			transition:  "Closed",
+			expectedErr: "issue TDX-1 cannot be moved from To Do to Closed, the available transitions are: Start review, Done",
+		},
+		{
+			name:        "fails if the issue does not exist",
+			key:         "TDX-404",
+			transition:  "Done",
+			expectedErr: "issue TDX-404 was not found in Jira",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, server := newFakeJira(t)
+
+			moved, err := newClient(t, config.Jira{APIURL: server.URL}).Transition(tt.key, tt.transition)
+
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedMoved, moved)
+			if tt.expectedBody != "" {
+				assert.Equal(t, "POST /rest/api/2/issue/"+tt.key+"/transitions", fake.requests[len(fake.requests)-1])
+				assert.JSONEq(t, tt.expectedBody, fake.bodies[len(fake.bodies)-1])
+			}
+		})
+	}
+}
+
+func TestAddRemoteLink(t *testing.T) {
+	fake, server := newFakeJira(t)
+	client := newClient(t, config.Jira{APIURL: server.URL})
+
+	err := client.AddRemoteLink("TDX-1", "https://github.com/elhub/gh-dxp/pull/3", "Add login page")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"POST /rest/api/2/issue/TDX-1/remotelink"}, fake.requests)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal([]byte(fake.bodies[0]), &body))
+	assert.Equal(t, "https://github.com/elhub/gh-dxp/pull/3", body["globalId"])
+	assert.Equal(t, map[string]any{"url": "https://github.com/elhub/gh-dxp/pull/3", "title": "Add login page"},
+		body["object"])
+
+	err = client.AddRemoteLink("TDX-404", "https://github.com/elhub/gh-dxp/pull/3", "Add login page")
+	require.EqualError(t, err, "failed to link issue TDX-404 to https://github.com/elhub/gh-dxp/pull/3: "+
+		"Jira responded with 404 Not Found: Issue does not exist or you do not have permission to see it.")
+}
@@ -0,0 +1,58 @@
+// Package jira provides the lookup and updating of the Jira issues of pull requests.
+package jira
+
+import "net/http"
+
+// Client calls the Jira REST API.
+type Client struct {
+	baseURL string
+	user    string
+	token   string
+	http    *http.Client
+}
+
+// Issue represents the summary and status of a Jira issue.
+type Issue struct {
+	Key     string
+	Summary string
+	Status  string
+}
+
+// The following structs are used to marshal and unmarshal the JSON of the Jira REST API.
+type issueResponse struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  struct {
+			Name string `json:"name"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+type transitionsResponse struct {
+	Transitions []transition `json:"transitions"`
+}
+
+type transition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	To   struct {
+		Name string `json:"name"`
+	} `json:"to"`
+}
+
+type transitionRequest struct {
+	Transition struct {
+		ID string `json:"id"`
+	} `json:"transition"`
+}
+
+type remoteLinkRequest struct {
+	GlobalID string           `json:"globalId"`
+	Object   remoteLinkObject `json:"object"`
+}
+
+type remoteLinkObject struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+}
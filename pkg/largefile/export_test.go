@@ -0,0 +1,5 @@
+package largefile
+
+var Untrack = untrack           //nolint:gochecknoglobals // Expose for testing
+var Restore = restore           //nolint:gochecknoglobals // Expose for testing
+var MigrateToLFS = migrateToLFS //nolint:gochecknoglobals // Expose for testing
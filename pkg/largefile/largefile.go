@@ -0,0 +1,375 @@
+// Package largefile provides detection of large and binary files in outgoing commits.
+package largefile
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/elhub/gh-dxp/pkg/config"
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultMaxSize is the maximum file size used if none is configured.
+	DefaultMaxSize = "10MB"
+
+	// maxPointerSize is an upper bound on the size of a Git LFS pointer file.
+	maxPointerSize   = 1024
+	lfsPointerPrefix = "version https://git-lfs.github.com/spec/v1"
+
+	choiceAbort   = "Abort"
+	choiceUntrack = "Untrack"
+	choiceRestore = "Restore the previous version"
+	choiceMigrate = "Migrate to Git LFS"
+)
+
+// Run inspects the outgoing commits for large files, binaries and files that should be in Git LFS, and lets the user
+// decide how to handle each of them. In test runs, any finding results in an error.
+func Run(exe ghutil.Executor, settings *config.Settings, opts *Options) error {
+	findings, err := Inspect(exe, settings, opts.BaseRef)
+	if err != nil {
+		return err
+	}
+
+	if len(findings) == 0 {
+		return nil
+	}
+
+	for _, f := range findings {
+		logger.Warn(formatFinding(f))
+	}
+
+	if opts.TestRun {
+		return errors.Errorf("found %d file(s) that should not be pushed", len(findings))
+	}
+
+	handled := map[string]bool{}
+	for len(findings) > 0 {
+		f := findings[0]
+		if handled[f.Blob+f.Path] {
+			return errors.Errorf("%s is still in the outgoing commits", f.Path)
+		}
+		handled[f.Blob+f.Path] = true
+
+		// Files that are new can be untracked, while files that exist on the base branch are restored
+		fix := choiceRestore
+		if f.Added {
+			fix = choiceUntrack
+		}
+		choice, err := ghutil.AskForMultipleChoice(formatFinding(f)+"\nHow do you want to handle this?",
+			[]string{choiceAbort, fix, choiceMigrate})
+		if err != nil {
+			return err
+		}
+
+		switch choice {
+		case choiceUntrack:
+			err = untrack(exe, f, opts.BaseRef)
+		case choiceRestore:
+			err = restore(exe, f, opts.BaseRef)
+		case choiceMigrate:
+			err = migrateToLFS(exe, f, opts)
+		default:
+			return errors.New("User aborted workflow due to large or binary files")
+		}
+		if err != nil {
+			return err
+		}
+
+		// The outgoing commits were rewritten, so the remaining findings may be stale
+		findings, err = Inspect(exe, settings, opts.BaseRef)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Inspect returns the files in the commits between baseRef and HEAD that are too large, binary outside the allowed
+// globs, or should have been stored in Git LFS. Every blob in the outgoing commits is inspected, since files that are
+// added in one commit and removed in a later one are still pushed.
+func Inspect(exe ghutil.Executor, settings *config.Settings, baseRef string) ([]Finding, error) {
+	maxSize, err := ParseSize(maxSizeSetting(settings))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid largeFiles.maxSize setting")
+	}
+
+	blobs, err := outgoingBlobs(exe, baseRef)
+	if err != nil {
+		return nil, err
+	}
+	if len(blobs) == 0 {
+		return []Finding{}, nil
+	}
+
+	binaries, err := outgoingBinaries(exe, baseRef)
+	if err != nil {
+		return nil, err
+	}
+
+	lfsPaths, err := lfsTrackedPaths(exe, blobs)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := []Finding{}
+	for _, b := range blobs {
+		size, err := blobSize(exe, b.Blob)
+		if err != nil {
+			return nil, err
+		}
+		b.Size = size
+
+		switch {
+		case lfsPaths[b.Path]:
+			isPointer, err := isLFSPointer(exe, b)
+			if err != nil {
+				return nil, err
+			}
+			if !isPointer {
+				b.Kind = KindLFS
+				findings = append(findings, b)
+			}
+		case size > maxSize:
+			b.Kind = KindLarge
+			findings = append(findings, b)
+		case binaries[b.Path] && !ghutil.CheckFilesMatchGlobs([]string{b.Path}, settings.LargeFiles.AllowedBinaries):
+			b.Kind = KindBinary
+			findings = append(findings, b)
+		}
+	}
+
+	return findings, nil
+}
+
+// ParseSize parses a human readable size such as 500KB, 10MB or 1GB into bytes. Plain numbers are read as bytes.
+func ParseSize(size string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(size))
+	units := []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"GB", 1 << 30},
+		{"MB", 1 << 20},
+		{"KB", 1 << 10},
+		{"B", 1},
+	}
+
+	multiplier := int64(1)
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, u.suffix))
+			multiplier = u.multiplier
+			break
+		}
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || value < 0 {
+		return 0, errors.Errorf("could not parse size %q", size)
+	}
+	return int64(value * float64(multiplier)), nil
+}
+
+func maxSizeSetting(settings *config.Settings) string {
+	if settings.LargeFiles.MaxSize != "" {
+		return settings.LargeFiles.MaxSize
+	}
+	return DefaultMaxSize
+}
+
+// outgoingBlobs lists the blobs added or modified in the outgoing commits.
+func outgoingBlobs(exe ghutil.Executor, baseRef string) ([]Finding, error) {
+	out, err := exe.Command("git", "log", "--format=", "--raw", "--no-abbrev", "--no-renames", "--diff-filter=AM",
+		baseRef+"..HEAD")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list outgoing files")
+	}
+
+	seen := map[string]bool{}
+	blobs := []Finding{}
+	for _, line := range ghutil.ConvertTerminalOutputIntoList(out) {
+		// Format: ":<old mode> <new mode> <old sha> <new sha> <status>\t<path>"
+		meta, path, found := strings.Cut(line, "\t")
+		fields := strings.Fields(meta)
+		if !found || len(fields) < 5 || fields[1] == "160000" {
+			continue // Skip malformed lines and submodules
+		}
+
+		blob := fields[3]
+		if seen[blob+path] {
+			continue
+		}
+		seen[blob+path] = true
+		blobs = append(blobs, Finding{Path: path, Blob: blob, Added: fields[4] == "A"})
+	}
+	return blobs, nil
+}
+
+// outgoingBinaries returns the paths that git considers binary in any of the outgoing commits.
+func outgoingBinaries(exe ghutil.Executor, baseRef string) (map[string]bool, error) {
+	out, err := exe.Command("git", "log", "--format=", "--numstat", "--no-renames", baseRef+"..HEAD")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list outgoing binary files")
+	}
+
+	binaries := map[string]bool{}
+	for _, line := range ghutil.ConvertTerminalOutputIntoList(out) {
+		if path, ok := strings.CutPrefix(line, "-\t-\t"); ok {
+			binaries[path] = true
+		}
+	}
+	return binaries, nil
+}
+
+// lfsTrackedPaths returns the paths that have the lfs filter set in .gitattributes.
+func lfsTrackedPaths(exe ghutil.Executor, blobs []Finding) (map[string]bool, error) {
+	args := []string{"check-attr", "filter", "--"}
+	for _, b := range blobs {
+		args = append(args, b.Path)
+	}
+
+	out, err := exe.Command("git", args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check git attributes")
+	}
+
+	paths := map[string]bool{}
+	for _, line := range ghutil.ConvertTerminalOutputIntoList(out) {
+		if path, ok := strings.CutSuffix(line, ": filter: lfs"); ok {
+			paths[path] = true
+		}
+	}
+	return paths, nil
+}
+
+func blobSize(exe ghutil.Executor, blob string) (int64, error) {
+	out, err := exe.Command("git", "cat-file", "-s", blob)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get size of blob "+blob)
+	}
+	return strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+}
+
+func isLFSPointer(exe ghutil.Executor, f Finding) (bool, error) {
+	if f.Size > maxPointerSize {
+		return false, nil
+	}
+
+	content, err := exe.Command("git", "cat-file", "-p", f.Blob)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to read blob "+f.Blob)
+	}
+	return strings.HasPrefix(content, lfsPointerPrefix), nil
+}
+
+// untrack removes a new file from the index and amends the last commit. This only removes the file from the outgoing
+// commits if it was only changed in the last commit; otherwise the history must be rewritten manually.
+func untrack(exe ghutil.Executor, f Finding, baseRef string) error {
+	if !f.Added {
+		return errors.Errorf("%s exists on %s and cannot be untracked, restore its previous version instead", f.Path,
+			baseRef)
+	}
+	if err := requireOnlyInLastCommit(exe, f, baseRef); err != nil {
+		return err
+	}
+
+	if _, err := exe.Command("git", "rm", "--cached", "--", f.Path); err != nil {
+		return errors.Wrap(err, "failed to untrack "+f.Path)
+	}
+	if _, err := exe.Command("git", "commit", "--amend", "--no-edit"); err != nil {
+		return errors.Wrap(err, "failed to amend commit")
+	}
+
+	logger.Infof("Untracked %s. The file is still on disk; consider adding it to .gitignore.", f.Path)
+	return nil
+}
+
+// restore resets a modified file in the index to its version on the base branch and amends the last commit, so that
+// the change is dropped from the outgoing commits. As with untrack, the file must only be changed in the last commit.
+func restore(exe ghutil.Executor, f Finding, baseRef string) error {
+	if err := requireOnlyInLastCommit(exe, f, baseRef); err != nil {
+		return err
+	}
+
+	if _, err := exe.Command("git", "reset", "-q", baseRef, "--", f.Path); err != nil {
+		return errors.Wrap(err, "failed to restore "+f.Path)
+	}
+	if _, err := exe.Command("git", "commit", "--amend", "--no-edit"); err != nil {
+		return errors.Wrap(err, "failed to amend commit")
+	}
+
+	logger.Infof("Restored %s to its version on %s. Your changes are still on disk.", f.Path, baseRef)
+	return nil
+}
+
+// requireOnlyInLastCommit returns an error unless HEAD is the only outgoing commit that changes the file, since
+// amending HEAD would otherwise leave the file in the earlier commits.
+func requireOnlyInLastCommit(exe ghutil.Executor, f Finding, baseRef string) error {
+	out, err := exe.Command("git", "log", "--format=%H", baseRef+"..HEAD", "--", f.Path)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the commits that changed "+f.Path)
+	}
+	head, err := exe.Command("git", "rev-parse", "HEAD")
+	if err != nil {
+		return errors.Wrap(err, "failed to resolve HEAD")
+	}
+
+	commits := ghutil.ConvertTerminalOutputIntoList(out)
+	if len(commits) != 1 || commits[0] != strings.TrimSpace(head) {
+		return errors.Errorf("%s was not only changed in the last commit and cannot be fixed automatically. "+
+			"Squash your commits (e.g., git rebase -i %s) or migrate the file to Git LFS instead", f.Path, baseRef)
+	}
+	return nil
+}
+
+// migrateToLFS rewrites the outgoing commits so that the file is stored in Git LFS, updating .gitattributes. Commits
+// that are already on the base branch or pushed to the remote branch are left as they are, so that the branch can
+// still be pushed without force.
+func migrateToLFS(exe ghutil.Executor, f Finding, opts *Options) error {
+	args := []string{"lfs", "migrate", "import", "--yes",
+		"--include=" + f.Path,
+		"--include-ref=refs/heads/" + opts.Branch,
+		"--exclude-ref=refs/remotes/" + opts.BaseRef,
+	}
+	remoteBranch := "refs/remotes/origin/" + opts.Branch
+	if _, err := exe.Command("git", "rev-parse", "--verify", "--quiet", remoteBranch); err == nil {
+		args = append(args, "--exclude-ref="+remoteBranch)
+	}
+
+	if _, err := exe.Command("git", args...); err != nil {
+		return errors.Wrap(err, "failed to migrate "+f.Path+" to Git LFS (is git-lfs installed?)")
+	}
+
+	logger.Infof("Migrated %s to Git LFS", f.Path)
+	return nil
+}
+
+func formatFinding(f Finding) string {
+	switch f.Kind {
+	case KindLFS:
+		return fmt.Sprintf("%s (%s) should be stored in Git LFS according to .gitattributes", f.Path, formatSize(f.Size))
+	case KindBinary:
+		return fmt.Sprintf("%s (%s) is a binary file that does not match any allowed binary pattern", f.Path,
+			formatSize(f.Size))
+	default:
+		return fmt.Sprintf("%s (%s) is larger than the maximum allowed file size", f.Path, formatSize(f.Size))
+	}
+}
+
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(size)/(1<<30))
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
@@ -0,0 +1,272 @@
+package largefile_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/elhub/gh-dxp/pkg/config"
+	"github.com/elhub/gh-dxp/pkg/largefile"
+	"github.com/elhub/gh-dxp/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	blobJar     = "1111111111111111111111111111111111111111"
+	blobLogo    = "2222222222222222222222222222222222222222"
+	blobModel   = "3333333333333333333333333333333333333333"
+	blobPointer = "4444444444444444444444444444444444444444"
+	blobSource  = "5555555555555555555555555555555555555555"
+)
+
+func mockOutgoing(mockExe *testutils.MockExecutor) {
+	rawLog := ":000000 100644 0000000000000000000000000000000000000000 " + blobJar + " A\tlibs/app.jar\n" +
+		":000000 100644 0000000000000000000000000000000000000000 " + blobLogo + " A\tdocs/logo.png\n" +
+		":000000 100644 0000000000000000000000000000000000000000 " + blobModel + " A\tmodels/big.onnx\n" +
+		":000000 100644 0000000000000000000000000000000000000000 " + blobPointer + " A\tmodels/small.onnx\n" +
+		":100644 100644 6666666666666666666666666666666666666666 " + blobSource + " M\tmain.go\n"
+	numstat := "-\t-\tlibs/app.jar\n-\t-\tdocs/logo.png\n-\t-\tmodels/big.onnx\n3\t1\tmain.go\n"
+
+	mockExe.On("Command", "git", []string{"log", "--format=", "--raw", "--no-abbrev", "--no-renames", "--diff-filter=AM",
+		"origin/main..HEAD"}).Return(rawLog, nil)
+	mockExe.On("Command", "git", []string{"log", "--format=", "--numstat", "--no-renames", "origin/main..HEAD"}).
+		Return(numstat, nil)
+	mockExe.On("Command", "git", []string{"check-attr", "filter", "--", "libs/app.jar", "docs/logo.png",
+		"models/big.onnx", "models/small.onnx", "main.go"}).
+		Return("libs/app.jar: filter: unspecified\ndocs/logo.png: filter: unspecified\nmodels/big.onnx: filter: lfs\n"+
+			"models/small.onnx: filter: lfs\nmain.go: filter: unspecified\n", nil)
+	mockExe.On("Command", "git", []string{"cat-file", "-s", blobJar}).Return("209715200\n", nil)
+	mockExe.On("Command", "git", []string{"cat-file", "-s", blobLogo}).Return("20480\n", nil)
+	mockExe.On("Command", "git", []string{"cat-file", "-s", blobModel}).Return("52428800\n", nil)
+	mockExe.On("Command", "git", []string{"cat-file", "-s", blobPointer}).Return("131\n", nil)
+	mockExe.On("Command", "git", []string{"cat-file", "-p", blobPointer}).
+		Return("version https://git-lfs.github.com/spec/v1\noid sha256:abc\nsize 52428800\n", nil)
+	mockExe.On("Command", "git", []string{"cat-file", "-s", blobSource}).Return("2048\n", nil)
+}
+
+func TestInspect(t *testing.T) {
+	tests := []struct {
+		name            string
+		settings        *config.Settings
+		expectedKinds   map[string]largefile.Kind
+		expectedErrText string
+	}{
+		{
+			name:     "Default settings",
+			settings: &config.Settings{},
+			expectedKinds: map[string]largefile.Kind{
+				"libs/app.jar":    largefile.KindLarge,
+				"docs/logo.png":   largefile.KindBinary,
+				"models/big.onnx": largefile.KindLFS,
+			},
+		},
+		{
+			name: "Allowed binaries and larger max size",
+			settings: &config.Settings{LargeFiles: config.LargeFiles{
+				MaxSize:         "500MB",
+				AllowedBinaries: []string{"*.png", "*.jar"},
+			}},
+			expectedKinds: map[string]largefile.Kind{
+				"models/big.onnx": largefile.KindLFS,
+			},
+		},
+		{
+			name:            "Invalid max size",
+			settings:        &config.Settings{LargeFiles: config.LargeFiles{MaxSize: "huge"}},
+			expectedErrText: "invalid largeFiles.maxSize setting",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExe := new(testutils.MockExecutor)
+			mockOutgoing(mockExe)
+
+			findings, err := largefile.Inspect(mockExe, tt.settings, "origin/main")
+
+			if tt.expectedErrText != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrText)
+				return
+			}
+			require.NoError(t, err)
+
+			kinds := map[string]largefile.Kind{}
+			for _, f := range findings {
+				kinds[f.Path] = f.Kind
+				assert.True(t, f.Added, f.Path)
+			}
+			assert.Equal(t, tt.expectedKinds, kinds)
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Run("No outgoing files", func(t *testing.T) {
+		mockExe := new(testutils.MockExecutor)
+		mockExe.On("Command", "git", []string{"log", "--format=", "--raw", "--no-abbrev", "--no-renames", "--diff-filter=AM",
+			"origin/main..HEAD"}).Return("", nil)
+
+		err := largefile.Run(mockExe, &config.Settings{}, &largefile.Options{TestRun: true, BaseRef: "origin/main"})
+
+		require.NoError(t, err)
+		mockExe.AssertExpectations(t)
+	})
+
+	t.Run("Findings in test run", func(t *testing.T) {
+		mockExe := new(testutils.MockExecutor)
+		mockOutgoing(mockExe)
+
+		err := largefile.Run(mockExe, &config.Settings{}, &largefile.Options{TestRun: true, BaseRef: "origin/main"})
+
+		require.Error(t, err)
+		assert.Equal(t, "found 3 file(s) that should not be pushed", err.Error())
+	})
+}
+
+func TestUntrackAndRestore(t *testing.T) {
+	const (
+		headSHA    = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		earlierSHA = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	)
+	untrackArgs := []string{"rm", "--cached", "--", "libs/app.jar"}
+	restoreArgs := []string{"reset", "-q", "origin/main", "--", "libs/app.jar"}
+
+	tests := []struct {
+		name            string
+		restore         bool
+		added           bool
+		commits         string
+		expectedCall    []string
+		expectedErrText string
+	}{
+		{
+			name:         "Untracks a file added in the last commit",
+			added:        true,
+			commits:      headSHA + "\n",
+			expectedCall: untrackArgs,
+		},
+		{
+			name:            "Does not untrack a file that exists on the base branch",
+			commits:         headSHA + "\n",
+			expectedErrText: "libs/app.jar exists on origin/main and cannot be untracked",
+		},
+		{
+			name:         "Restores a file modified in the last commit",
+			restore:      true,
+			commits:      headSHA + "\n",
+			expectedCall: restoreArgs,
+		},
+		{
+			name:            "File added in an earlier commit",
+			added:           true,
+			commits:         earlierSHA + "\n",
+			expectedErrText: "libs/app.jar was not only changed in the last commit",
+		},
+		{
+			name:            "File changed in several commits",
+			restore:         true,
+			commits:         headSHA + "\n" + earlierSHA + "\n",
+			expectedErrText: "libs/app.jar was not only changed in the last commit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExe := new(testutils.MockExecutor)
+			mockExe.On("Command", "git", []string{"log", "--format=%H", "origin/main..HEAD", "--", "libs/app.jar"}).
+				Return(tt.commits, nil)
+			mockExe.On("Command", "git", []string{"rev-parse", "HEAD"}).Return(headSHA+"\n", nil)
+			mockExe.On("Command", "git", untrackArgs).Return("", nil)
+			mockExe.On("Command", "git", restoreArgs).Return("", nil)
+			mockExe.On("Command", "git", []string{"commit", "--amend", "--no-edit"}).Return("", nil)
+
+			f := largefile.Finding{Path: "libs/app.jar", Added: tt.added}
+			var err error
+			if tt.restore {
+				err = largefile.Restore(mockExe, f, "origin/main")
+			} else {
+				err = largefile.Untrack(mockExe, f, "origin/main")
+			}
+
+			if tt.expectedErrText != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrText)
+				mockExe.AssertNotCalled(t, "Command", "git", []string{"commit", "--amend", "--no-edit"})
+				return
+			}
+			require.NoError(t, err)
+			mockExe.AssertCalled(t, "Command", "git", tt.expectedCall)
+			mockExe.AssertCalled(t, "Command", "git", []string{"commit", "--amend", "--no-edit"})
+		})
+	}
+}
+
+func TestMigrateToLFS(t *testing.T) {
+	migrateArgs := []string{"lfs", "migrate", "import", "--yes", "--include=models/big.onnx",
+		"--include-ref=refs/heads/feature", "--exclude-ref=refs/remotes/origin/main"}
+	verifyRemoteArgs := []string{"rev-parse", "--verify", "--quiet", "refs/remotes/origin/feature"}
+	opts := &largefile.Options{Branch: "feature", BaseRef: "origin/main"}
+
+	t.Run("Migrates the file", func(t *testing.T) {
+		mockExe := new(testutils.MockExecutor)
+		mockExe.On("Command", "git", verifyRemoteArgs).Return("", errors.New("exit status 1"))
+		mockExe.On("Command", "git", migrateArgs).Return("", nil)
+
+		err := largefile.MigrateToLFS(mockExe, largefile.Finding{Path: "models/big.onnx"}, opts)
+
+		require.NoError(t, err)
+		mockExe.AssertExpectations(t)
+	})
+
+	t.Run("Leaves the pushed commits of the branch as they are", func(t *testing.T) {
+		mockExe := new(testutils.MockExecutor)
+		mockExe.On("Command", "git", verifyRemoteArgs).Return("cccccccccccccccccccccccccccccccccccccccc\n", nil)
+		mockExe.On("Command", "git", append(migrateArgs, "--exclude-ref=refs/remotes/origin/feature")).Return("", nil)
+
+		err := largefile.MigrateToLFS(mockExe, largefile.Finding{Path: "models/big.onnx"}, opts)
+
+		require.NoError(t, err)
+		mockExe.AssertExpectations(t)
+	})
+
+	t.Run("Git LFS is not installed", func(t *testing.T) {
+		mockExe := new(testutils.MockExecutor)
+		mockExe.On("Command", "git", verifyRemoteArgs).Return("", errors.New("exit status 1"))
+		mockExe.On("Command", "git", migrateArgs).Return("", errors.New("git: 'lfs' is not a git command"))
+
+		err := largefile.MigrateToLFS(mockExe, largefile.Finding{Path: "models/big.onnx"}, opts)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to migrate models/big.onnx to Git LFS (is git-lfs installed?)")
+	})
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  int64
+		expectErr bool
+	}{
+		{input: "1024", expected: 1024},
+		{input: "500KB", expected: 500 * 1024},
+		{input: "10MB", expected: 10 * 1024 * 1024},
+		{input: "1.5gb", expected: 3 * 512 * 1024 * 1024},
+		{input: "10 MB", expected: 10 * 1024 * 1024},
+		{input: "ten", expectErr: true},
+		{input: "-1MB", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			size, err := largefile.ParseSize(tt.input)
+
+			if tt.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, size)
+			}
+		})
+	}
+}
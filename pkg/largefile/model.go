@@ -0,0 +1,34 @@
+// Package largefile provides detection of large and binary files in outgoing commits.
+package largefile
+
+// Options represents the options for inspecting outgoing commits.
+type Options struct {
+	TestRun bool
+
+	// Branch is the local branch that is about to be pushed.
+	Branch string
+	// BaseRef is the remote ref the branch is compared to (e.g., origin/main).
+	BaseRef string
+}
+
+// Kind describes why a file was flagged.
+type Kind string
+
+const (
+	// KindLarge means the file is larger than the configured maximum size.
+	KindLarge Kind = "large"
+	// KindBinary means the file is binary and does not match any of the allowed binary globs.
+	KindBinary Kind = "binary"
+	// KindLFS means the file should be stored in Git LFS according to .gitattributes, but was committed directly.
+	KindLFS Kind = "lfs"
+)
+
+// Finding represents a file in the outgoing commits that should not be pushed as-is.
+type Finding struct {
+	Path string
+	Blob string
+	Size int64
+	Kind Kind
+	// Added is true if the file does not exist on the base branch.
+	Added bool
+}
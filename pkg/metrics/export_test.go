@@ -0,0 +1,3 @@
+package metrics
+
+var ExecuteAt = execute //nolint:gochecknoglobals // Expose for testing
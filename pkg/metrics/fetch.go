@@ -0,0 +1,211 @@
+package metrics
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+// maxSearchResults is the number of results the GitHub search returns at most.
+const maxSearchResults = 1000
+
+const pullRequestsQuery = `query($q: String!, $after: String) {
+  search(query: $q, type: ISSUE, first: 100, after: $after) {
+    issueCount
+    pageInfo { hasNextPage endCursor }
+    nodes {
+      ... on PullRequest {
+        number title url createdAt mergedAt additions deletions
+        author { login }
+        repository { nameWithOwner }
+        labels(first: 20) { nodes { name } }
+        reviews(first: 100) { nodes { author { login } state submittedAt } }
+      }
+    }
+  }
+}`
+
+var cacheKeyRegex = regexp.MustCompile(`[^A-Za-z0-9._-]+`) //nolint: gochecknoglobals // Compiled once.
+
+type searchResponse struct {
+	Data struct {
+		Search struct {
+			IssueCount int `json:"issueCount"`
+			PageInfo   struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Nodes []pullRequestNode `json:"nodes"`
+		} `json:"search"`
+	} `json:"data"`
+}
+
+type pullRequestNode struct {
+	Number     int        `json:"number"`
+	Title      string     `json:"title"`
+	URL        string     `json:"url"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	MergedAt   *time.Time `json:"mergedAt"`
+	Additions  int        `json:"additions"`
+	Deletions  int        `json:"deletions"`
+	Author     *login     `json:"author"`
+	Repository struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"repository"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Reviews struct {
+		Nodes []struct {
+			Author      *login     `json:"author"`
+			State       string     `json:"state"`
+			SubmittedAt *time.Time `json:"submittedAt"`
+		} `json:"nodes"`
+	} `json:"reviews"`
+}
+
+// login is a GitHub actor, which is null if the account has been deleted.
+type login struct {
+	Login string `json:"login"`
+}
+
+func (l *login) name() string {
+	if l == nil {
+		return "ghost"
+	}
+	return l.Login
+}
+
+// cacheEntry is the content of a cache file.
+type cacheEntry struct {
+	FetchedAt    time.Time     `json:"fetchedAt"`
+	PullRequests []PullRequest `json:"pullRequests"`
+}
+
+// fetchPullRequests returns the pull requests matching the search, from the cache if it was fetched within the TTL.
+func fetchPullRequests(exe ghutil.Executor, search string, opts *Options, now time.Time) ([]PullRequest, error) {
+	cacheFile := ""
+	if !opts.NoCache {
+		cacheFile = filepath.Join(opts.CacheDir, cacheKeyRegex.ReplaceAllString(search, "_")+".json")
+		if entry, ok := readCache(cacheFile); ok && now.Sub(entry.FetchedAt) < opts.CacheTTL {
+			logger.Debugf("Using the pull requests cached at %s", entry.FetchedAt.Format(time.RFC3339))
+			return entry.PullRequests, nil
+		}
+	}
+
+	pullRequests, err := searchPullRequests(exe, search)
+	if err != nil {
+		return nil, err
+	}
+
+	if cacheFile != "" {
+		if err := writeCache(cacheFile, cacheEntry{FetchedAt: now, PullRequests: pullRequests}); err != nil {
+			logger.Warn("Failed to cache the pull requests: " + err.Error())
+		}
+	}
+	return pullRequests, nil
+}
+
+// searchPullRequests fetches the pull requests matching the search, 100 per page.
+func searchPullRequests(exe ghutil.Executor, search string) ([]PullRequest, error) {
+	pullRequests := []PullRequest{}
+	after := ""
+
+	for {
+		args := []string{"api", "graphql", "-f", "query=" + pullRequestsQuery, "-f", "q=" + search}
+		if after != "" {
+			args = append(args, "-f", "after="+after)
+		}
+
+		res, err := exe.GH(args...)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to search for pull requests")
+		}
+
+		var response searchResponse
+		if err := json.Unmarshal([]byte(res), &response); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal the pull requests")
+		}
+
+		result := response.Data.Search
+		if after == "" && result.IssueCount > maxSearchResults {
+			logger.Warnf("Only the first %d of the %d pull requests can be searched, use a shorter date range",
+				maxSearchResults, result.IssueCount)
+		}
+		for _, node := range result.Nodes {
+			// Search results that are not pull requests are skipped
+			if node.URL == "" {
+				continue
+			}
+			pullRequests = append(pullRequests, node.toPullRequest())
+		}
+
+		if !result.PageInfo.HasNextPage {
+			return pullRequests, nil
+		}
+		after = result.PageInfo.EndCursor
+	}
+}
+
+func (n pullRequestNode) toPullRequest() PullRequest {
+	pr := PullRequest{
+		Repository: n.Repository.NameWithOwner,
+		Number:     n.Number,
+		Title:      n.Title,
+		URL:        n.URL,
+		Author:     n.Author.name(),
+		CreatedAt:  n.CreatedAt,
+		MergedAt:   n.MergedAt,
+		Additions:  n.Additions,
+		Deletions:  n.Deletions,
+		Labels:     []string{},
+		Reviews:    []Review{},
+	}
+	for _, label := range n.Labels.Nodes {
+		pr.Labels = append(pr.Labels, label.Name)
+	}
+	for _, review := range n.Reviews.Nodes {
+		// Pending reviews have not been submitted yet
+		if review.SubmittedAt == nil {
+			continue
+		}
+		pr.Reviews = append(pr.Reviews, Review{
+			Author:      review.Author.name(),
+			State:       review.State,
+			SubmittedAt: *review.SubmittedAt,
+		})
+	}
+	return pr
+}
+
+func readCache(cacheFile string) (cacheEntry, bool) {
+	var entry cacheEntry
+	data, err := os.ReadFile(cacheFile)
+	if err != nil {
+		return entry, false
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		logger.Debugf("Ignoring the invalid cache file %s: %s", cacheFile, err.Error())
+		return entry, false
+	}
+	return entry, true
+}
+
+func writeCache(cacheFile string, entry cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(cacheFile, data, 0o600)
+}
@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+// defaultDays is the length of the date range if no start date is given.
+const defaultDays = 30
+
+// Execute computes the metrics of the pull requests created in the date range, in the repository or organization of
+// the options, or in the current repository.
+func Execute(exe ghutil.Executor, opts *Options) error {
+	return execute(exe, opts, time.Now())
+}
+
+func execute(exe ghutil.Executor, opts *Options, now time.Time) error {
+	if err := applyDefaults(opts, now); err != nil {
+		return err
+	}
+
+	scope, err := resolveScope(exe, opts)
+	if err != nil {
+		return err
+	}
+
+	search := fmt.Sprintf("is:pr %s created:%s..%s", scope, opts.Since, opts.Until)
+	pullRequests, err := fetchPullRequests(exe, search, opts, now)
+	if err != nil {
+		return err
+	}
+
+	_, name, _ := strings.Cut(scope, ":")
+	report := BuildReport(name, opts.Since, opts.Until, pullRequests)
+
+	switch opts.Format {
+	case FormatCSV:
+		return WriteCSV(os.Stdout, report)
+	case FormatJSON:
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal the report")
+		}
+		_, err = fmt.Fprintln(os.Stdout, string(out))
+		return err
+	}
+
+	logger.Info(FormatReport(report))
+	return nil
+}
+
+// applyDefaults validates the options and fills in the date range, format and cache settings that are not set.
+func applyDefaults(opts *Options, now time.Time) error {
+	if opts.Repo != "" && opts.Org != "" {
+		return errors.New("use either --repo or --org, not both")
+	}
+
+	if opts.Until == "" {
+		opts.Until = now.Format(time.DateOnly)
+	}
+	until, err := time.Parse(time.DateOnly, opts.Until)
+	if err != nil {
+		return errors.Errorf("invalid date %q for --until, use the format YYYY-MM-DD", opts.Until)
+	}
+	if opts.Since == "" {
+		opts.Since = until.AddDate(0, 0, -defaultDays).Format(time.DateOnly)
+	}
+	since, err := time.Parse(time.DateOnly, opts.Since)
+	if err != nil {
+		return errors.Errorf("invalid date %q for --since, use the format YYYY-MM-DD", opts.Since)
+	}
+	if since.After(until) {
+		return errors.Errorf("--since %s is after --until %s", opts.Since, opts.Until)
+	}
+
+	switch opts.Format {
+	case "":
+		opts.Format = FormatText
+	case FormatText, FormatCSV, FormatJSON:
+	default:
+		return errors.Errorf("invalid format %q, use text, csv or json", opts.Format)
+	}
+
+	if opts.CacheTTL == 0 {
+		opts.CacheTTL = DefaultCacheTTL
+	}
+	if opts.CacheDir == "" && !opts.NoCache {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			logger.Warn("Not caching the pull requests, as there is no cache directory: " + err.Error())
+			opts.NoCache = true
+		} else {
+			opts.CacheDir = filepath.Join(cacheDir, "gh-dxp", "metrics")
+		}
+	}
+	return nil
+}
+
+// resolveScope returns the search qualifier for the repository or organization, which defaults to the current
+// repository.
+func resolveScope(exe ghutil.Executor, opts *Options) (string, error) {
+	switch {
+	case opts.Org != "":
+		return "org:" + opts.Org, nil
+	case opts.Repo != "":
+		if owner, name, found := strings.Cut(opts.Repo, "/"); !found || owner == "" || name == "" {
+			return "", errors.Errorf("invalid repository %q, use the format <owner>/<repo>", opts.Repo)
+		}
+		return "repo:" + opts.Repo, nil
+	}
+
+	current, err := exe.GH("repo", "view", "--json", "nameWithOwner", "--jq", ".nameWithOwner")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to find the current repository, use --repo or --org")
+	}
+	return "repo:" + strings.TrimSpace(current), nil
+}
@@ -0,0 +1,228 @@
+package metrics_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elhub/gh-dxp/pkg/metrics"
+	"github.com/elhub/gh-dxp/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func at(hours float64) time.Time {
+	return time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC).Add(time.Duration(hours * float64(time.Hour)))
+}
+
+func merged(hours float64) *time.Time {
+	t := at(hours)
+	return &t
+}
+
+func testPullRequests() []metrics.PullRequest {
+	return []metrics.PullRequest{
+		{
+			Repository: "elhub/web", Number: 1, Title: "Add login", Author: "alice",
+			CreatedAt: at(0), MergedAt: merged(30), Additions: 120, Deletions: 30, Labels: []string{"feature"},
+			Reviews: []metrics.Review{
+				{Author: "alice", State: "COMMENTED", SubmittedAt: at(1)},
+				{Author: "bob", State: "CHANGES_REQUESTED", SubmittedAt: at(4)},
+				{Author: "bob", State: "APPROVED", SubmittedAt: at(20)},
+			},
+		},
+		{
+			Repository: "elhub/web", Number: 2, Title: "Fix typo", Author: "bob",
+			CreatedAt: at(0), MergedAt: merged(2), Additions: 1, Deletions: 1, Labels: []string{"Documentation", "Bugfix"},
+			Reviews: []metrics.Review{{Author: "carol", State: "APPROVED", SubmittedAt: at(1)}},
+		},
+		{
+			Repository: "elhub/api", Number: 3, Title: "Rewrite everything", Author: "carol",
+			CreatedAt: at(0), Additions: 5000, Deletions: 2000, Labels: []string{"wip"},
+			Reviews: []metrics.Review{{Author: "bob", State: "COMMENTED", SubmittedAt: at(48)}},
+		},
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	report := metrics.BuildReport("elhub", "2024-03-01", "2024-03-31", testPullRequests())
+
+	assert.Equal(t, 3, report.PullRequests)
+	assert.Equal(t, 2, report.Merged)
+	assert.Equal(t, metrics.DurationStats{Count: 3, MedianHours: 4, P90Hours: 48, AverageHours: 17.7},
+		report.TimeToFirstReview)
+	assert.Equal(t, metrics.DurationStats{Count: 2, MedianHours: 2, P90Hours: 30, AverageHours: 16},
+		report.TimeToMerge)
+	assert.Equal(t, []metrics.Count{{"XS", 1}, {"S", 0}, {"M", 1}, {"L", 0}, {"XL", 1}}, report.Sizes)
+	assert.Equal(t, []metrics.Count{{"bob", 2}, {"carol", 1}}, report.ReviewLoad)
+
+	labels := map[string]int{}
+	for _, label := range report.Labels {
+		labels[label.Name] = label.Count
+	}
+	assert.Equal(t, 1, labels["Feature"])
+	assert.Equal(t, 1, labels["Documentation"])
+	assert.Equal(t, 0, labels["Bugfix"])
+	assert.Equal(t, 1, labels["Unlabelled"])
+
+	require.Len(t, report.Details, 3)
+	assert.Equal(t, "Feature", report.Details[0].Label)
+	assert.InDelta(t, 4.0, *report.Details[0].FirstReviewHours, 0.01)
+	assert.InDelta(t, 30.0, *report.Details[0].MergeHours, 0.01)
+	assert.Nil(t, report.Details[2].MergeHours)
+}
+
+func TestFormatReport(t *testing.T) {
+	report := metrics.BuildReport("elhub", "2024-03-01", "2024-03-31", testPullRequests())
+
+	text := metrics.FormatReport(report)
+
+	assert.Contains(t, text, "Pull requests in elhub created 2024-03-01 to 2024-03-31: 3, of which 2 merged")
+	assert.Regexp(t, `Time to first review\s+3\s+4h\s+2d 0h\s+18h`, text)
+	assert.Regexp(t, `Time to merge\s+2\s+2h\s+1d 6h\s+16h`, text)
+	assert.Regexp(t, `bob\s+2`, text)
+
+	empty := metrics.FormatReport(metrics.BuildReport("elhub/web", "2024-03-01", "2024-03-31", nil))
+	assert.Regexp(t, `Time to merge\s+0\s+-\s+-\s+-`, empty)
+	assert.Contains(t, empty, "Review load (reviewed PRs per person):\n  none")
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	report := metrics.BuildReport("elhub", "2024-03-01", "2024-03-31", testPullRequests())
+
+	require.NoError(t, metrics.WriteCSV(&buf, report))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "repository,number,title,author,created_at,merged_at,size,changes,label,"+
+		"first_review_hours,merge_hours", lines[0])
+	assert.Equal(t, "elhub/web,1,Add login,alice,2024-03-01T08:00:00Z,2024-03-02T14:00:00Z,M,150,Feature,4,30",
+		lines[1])
+	assert.Equal(t, "elhub/api,3,Rewrite everything,carol,2024-03-01T08:00:00Z,,XL,7000,Unlabelled,48,", lines[3])
+}
+
+const searchPage = `{"data":{"search":{"issueCount":1,"pageInfo":{"hasNextPage":%t,"endCursor":"C1"},"nodes":[` +
+	`{"number":%d,"title":"Add login","url":"https://github.com/elhub/web/pull/%[2]d",` +
+	`"createdAt":"2024-03-01T08:00:00Z","mergedAt":null,"additions":10,"deletions":5,` +
+	`"author":{"login":"alice"},"repository":{"nameWithOwner":"elhub/web"},"labels":{"nodes":[]},` +
+	`"reviews":{"nodes":[{"author":null,"state":"APPROVED","submittedAt":"2024-03-01T10:00:00Z"},` +
+	`{"author":{"login":"bob"},"state":"PENDING","submittedAt":null}]}},{}]}}}`
+
+func searchArgs(search string, after string) interface{} {
+	return mock.MatchedBy(func(args []string) bool {
+		expected := []string{"-f", "q=" + search}
+		if after != "" {
+			expected = append(expected, "-f", "after="+after)
+		}
+		return len(args) > 4 && args[0] == "api" && args[1] == "graphql" &&
+			strings.HasPrefix(args[3], "query=") && assert.ObjectsAreEqual(expected, args[4:])
+	})
+}
+
+func TestExecuteUsesCache(t *testing.T) {
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	search := "is:pr repo:elhub/web created:2024-03-01..2024-03-31"
+	cacheDir := t.TempDir()
+
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("GH", []string{"repo", "view", "--json", "nameWithOwner", "--jq", ".nameWithOwner"}).
+		Return("elhub/web\n", nil)
+	mockExe.On("GH", searchArgs(search, "")).Return(fmt.Sprintf(searchPage, true, 1), nil).Once()
+	mockExe.On("GH", searchArgs(search, "C1")).Return(fmt.Sprintf(searchPage, false, 2), nil).Once()
+
+	opts := func() *metrics.Options {
+		return &metrics.Options{Since: "2024-03-01", Until: "2024-03-31", CacheDir: cacheDir, Format: metrics.FormatJSON}
+	}
+
+	require.NoError(t, metrics.ExecuteAt(mockExe, opts(), now))
+	// The second run within the TTL uses the cache instead of searching again
+	require.NoError(t, metrics.ExecuteAt(mockExe, opts(), now.Add(time.Hour)))
+	mockExe.AssertExpectations(t)
+
+	// After the TTL the pull requests are fetched again
+	mockExe.On("GH", searchArgs(search, "")).Return(fmt.Sprintf(searchPage, false, 1), nil).Once()
+	require.NoError(t, metrics.ExecuteAt(mockExe, opts(), now.Add(25*time.Hour)))
+	mockExe.AssertExpectations(t)
+}
+
+func TestExecuteOptions(t *testing.T) {
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		opts        metrics.Options
+		search      string
+		expectedErr string
+	}{
+		{
+			name:   "Organization with the default date range",
+			opts:   metrics.Options{Org: "elhub"},
+			search: "is:pr org:elhub created:2024-03-01..2024-03-31",
+		},
+		{
+			name:   "Repository",
+			opts:   metrics.Options{Repo: "elhub/api", Since: "2024-01-01", Until: "2024-01-31", Format: "csv"},
+			search: "is:pr repo:elhub/api created:2024-01-01..2024-01-31",
+		},
+		{
+			name:        "Repository and organization",
+			opts:        metrics.Options{Repo: "elhub/api", Org: "elhub"},
+			expectedErr: "use either --repo or --org, not both",
+		},
+		{
+			name:        "Invalid repository",
+			opts:        metrics.Options{Repo: "api"},
+			expectedErr: `invalid repository "api", use the format <owner>/<repo>`,
+		},
+		{
+			name:        "Invalid date",
+			opts:        metrics.Options{Org: "elhub", Since: "01.01.2024"},
+			expectedErr: `invalid date "01.01.2024" for --since, use the format YYYY-MM-DD`,
+		},
+		{
+			name:        "Reversed date range",
+			opts:        metrics.Options{Org: "elhub", Since: "2024-02-01", Until: "2024-01-01"},
+			expectedErr: "--since 2024-02-01 is after --until 2024-01-01",
+		},
+		{
+			name:        "Invalid format",
+			opts:        metrics.Options{Org: "elhub", Format: "xml"},
+			expectedErr: `invalid format "xml", use text, csv or json`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExe := new(testutils.MockExecutor)
+			if tt.search != "" {
+				mockExe.On("GH", searchArgs(tt.search, "")).Return(fmt.Sprintf(searchPage, false, 1), nil).Once()
+			}
+			tt.opts.NoCache = true
+
+			err := metrics.ExecuteAt(mockExe, &tt.opts, now)
+
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedErr, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			mockExe.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExecuteSearchFails(t *testing.T) {
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("GH", mock.Anything).Return("", errors.New("HTTP 502"))
+
+	err := metrics.ExecuteAt(mockExe, &metrics.Options{Org: "elhub", NoCache: true}, time.Now())
+
+	require.Error(t, err)
+	assert.Equal(t, "failed to search for pull requests: HTTP 502", err.Error())
+}
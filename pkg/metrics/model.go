@@ -0,0 +1,98 @@
+// Package metrics computes review turnaround and throughput metrics for the pull requests of a repository or an
+// organization.
+package metrics
+
+import "time"
+
+// Output formats of the metrics report.
+const (
+	FormatText = "text"
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// DefaultCacheTTL is how long fetched pull requests are reused before they are fetched again.
+const DefaultCacheTTL = 24 * time.Hour
+
+// Options represents the options for the metrics command.
+type Options struct {
+	Repo string
+	Org  string
+	// Since and Until limit the pull requests to those created in the date range, as YYYY-MM-DD. Since defaults to
+	// 30 days ago and Until to today.
+	Since  string
+	Until  string
+	Format string
+
+	NoCache bool
+	// CacheDir is where fetched pull requests are cached. It defaults to gh-dxp/metrics in the user cache directory.
+	CacheDir string
+	CacheTTL time.Duration
+}
+
+// PullRequest represents a pull request and its reviews, as fetched from GitHub and cached.
+type PullRequest struct {
+	Repository string     `json:"repository"`
+	Number     int        `json:"number"`
+	Title      string     `json:"title"`
+	URL        string     `json:"url"`
+	Author     string     `json:"author"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	MergedAt   *time.Time `json:"mergedAt"`
+	Additions  int        `json:"additions"`
+	Deletions  int        `json:"deletions"`
+	Labels     []string   `json:"labels"`
+	Reviews    []Review   `json:"reviews"`
+}
+
+// Review represents a submitted review of a pull request.
+type Review struct {
+	Author      string    `json:"author"`
+	State       string    `json:"state"`
+	SubmittedAt time.Time `json:"submittedAt"`
+}
+
+// Report represents the metrics of the pull requests created in a date range.
+type Report struct {
+	Scope             string          `json:"scope"`
+	Since             string          `json:"since"`
+	Until             string          `json:"until"`
+	PullRequests      int             `json:"pullRequests"`
+	Merged            int             `json:"merged"`
+	TimeToFirstReview DurationStats   `json:"timeToFirstReview"`
+	TimeToMerge       DurationStats   `json:"timeToMerge"`
+	Sizes             []Count         `json:"sizes"`
+	ReviewLoad        []Count         `json:"reviewLoad"`
+	Labels            []Count         `json:"labels"`
+	Details           []DetailedStats `json:"details"`
+}
+
+// DurationStats summarizes durations in hours.
+type DurationStats struct {
+	Count        int     `json:"count"`
+	MedianHours  float64 `json:"medianHours"`
+	P90Hours     float64 `json:"p90Hours"`
+	AverageHours float64 `json:"averageHours"`
+}
+
+// Count is the number of pull requests (or reviews) of a size, a person or a label.
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// DetailedStats represents the metrics of a single pull request.
+type DetailedStats struct {
+	Repository string `json:"repository"`
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	Author     string `json:"author"`
+	CreatedAt  string `json:"createdAt"`
+	MergedAt   string `json:"mergedAt"`
+	Size       string `json:"size"`
+	Changes    int    `json:"changes"`
+	Label      string `json:"label"`
+	// FirstReviewHours and MergeHours are nil if the pull request has not been reviewed or merged.
+	FirstReviewHours *float64 `json:"firstReviewHours"`
+	MergeHours       *float64 `json:"mergeHours"`
+}
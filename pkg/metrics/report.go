@@ -0,0 +1,268 @@
+package metrics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/elhub/gh-dxp/pkg/pr"
+)
+
+// unlabelled is the label of pull requests without a label from pr.PullRequestLabels.
+const unlabelled = "Unlabelled"
+
+// sizeBuckets are the pull request sizes by the number of changed lines, smallest first.
+var sizeBuckets = []struct { //nolint: gochecknoglobals // Constant lookup table.
+	name     string
+	maxLines int
+}{
+	{"XS", 9},
+	{"S", 49},
+	{"M", 249},
+	{"L", 999},
+	{"XL", math.MaxInt},
+}
+
+// BuildReport computes the metrics of the pull requests.
+func BuildReport(scope, since, until string, pullRequests []PullRequest) Report {
+	report := Report{
+		Scope:        scope,
+		Since:        since,
+		Until:        until,
+		PullRequests: len(pullRequests),
+		Details:      []DetailedStats{},
+	}
+
+	var firstReviews, merges []time.Duration
+	sizes := map[string]int{}
+	reviewers := map[string]int{}
+	labels := map[string]int{}
+
+	for _, p := range pullRequests {
+		detail := DetailedStats{
+			Repository: p.Repository,
+			Number:     p.Number,
+			Title:      p.Title,
+			Author:     p.Author,
+			CreatedAt:  p.CreatedAt.Format(time.RFC3339),
+			Changes:    p.Additions + p.Deletions,
+			Size:       sizeOf(p.Additions + p.Deletions),
+			Label:      taxonomyLabel(p.Labels),
+		}
+		sizes[detail.Size]++
+		labels[detail.Label]++
+
+		if firstReview, ok := firstReviewAt(p); ok {
+			wait := firstReview.Sub(p.CreatedAt)
+			firstReviews = append(firstReviews, wait)
+			detail.FirstReviewHours = hoursPtr(wait)
+		}
+		if p.MergedAt != nil {
+			report.Merged++
+			merge := p.MergedAt.Sub(p.CreatedAt)
+			merges = append(merges, merge)
+			detail.MergedAt = p.MergedAt.Format(time.RFC3339)
+			detail.MergeHours = hoursPtr(merge)
+		}
+
+		// The review load counts the pull requests each person reviewed, not the number of reviews
+		reviewed := map[string]bool{}
+		for _, review := range p.Reviews {
+			if review.Author != p.Author && !reviewed[review.Author] {
+				reviewed[review.Author] = true
+				reviewers[review.Author]++
+			}
+		}
+
+		report.Details = append(report.Details, detail)
+	}
+
+	report.TimeToFirstReview = durationStats(firstReviews)
+	report.TimeToMerge = durationStats(merges)
+
+	for _, bucket := range sizeBuckets {
+		report.Sizes = append(report.Sizes, Count{Name: bucket.name, Count: sizes[bucket.name]})
+	}
+	report.ReviewLoad = sortedCounts(reviewers)
+	for _, label := range pr.PullRequestLabels {
+		report.Labels = append(report.Labels, Count{Name: label.Name, Count: labels[label.Name]})
+	}
+	report.Labels = append(report.Labels, Count{Name: unlabelled, Count: labels[unlabelled]})
+
+	return report
+}
+
+// firstReviewAt returns when the pull request was first reviewed by someone other than its author.
+func firstReviewAt(p PullRequest) (time.Time, bool) {
+	var first time.Time
+	found := false
+	for _, review := range p.Reviews {
+		if review.Author == p.Author {
+			continue
+		}
+		if !found || review.SubmittedAt.Before(first) {
+			first = review.SubmittedAt
+			found = true
+		}
+	}
+	return first, found
+}
+
+func sizeOf(changes int) string {
+	for _, bucket := range sizeBuckets {
+		if changes <= bucket.maxLines {
+			return bucket.name
+		}
+	}
+	return sizeBuckets[len(sizeBuckets)-1].name
+}
+
+// taxonomyLabel returns the first label of the pull request that is one of pr.PullRequestLabels.
+func taxonomyLabel(labels []string) string {
+	for _, label := range labels {
+		for _, known := range pr.PullRequestLabels {
+			if strings.EqualFold(label, known.Name) {
+				return known.Name
+			}
+		}
+	}
+	return unlabelled
+}
+
+func durationStats(durations []time.Duration) DurationStats {
+	if len(durations) == 0 {
+		return DurationStats{}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	return DurationStats{
+		Count:        len(durations),
+		MedianHours:  hours(percentile(durations, 0.5)),
+		P90Hours:     hours(percentile(durations, 0.9)),
+		AverageHours: hours(total / time.Duration(len(durations))),
+	}
+}
+
+// percentile returns the nearest-rank percentile of the sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	return sorted[max(rank-1, 0)]
+}
+
+func hours(d time.Duration) float64 {
+	return math.Round(d.Hours()*10) / 10
+}
+
+func hoursPtr(d time.Duration) *float64 {
+	h := hours(d)
+	return &h
+}
+
+// sortedCounts returns the counts with the highest first, and by name if they are equal.
+func sortedCounts(counts map[string]int) []Count {
+	result := []Count{}
+	for name, count := range counts {
+		result = append(result, Count{Name: name, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count == result[j].Count {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].Count > result[j].Count
+	})
+	return result
+}
+
+// FormatReport formats the report for the terminal.
+func FormatReport(report Report) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Pull requests in %s created %s to %s: %d, of which %d merged\n\n",
+		report.Scope, report.Since, report.Until, report.PullRequests, report.Merged)
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tPRS\tMEDIAN\tP90\tAVERAGE")
+	for _, row := range []struct {
+		name  string
+		stats DurationStats
+	}{
+		{"Time to first review", report.TimeToFirstReview},
+		{"Time to merge", report.TimeToMerge},
+	} {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", row.name, row.stats.Count,
+			formatHours(row.stats.MedianHours), formatHours(row.stats.P90Hours), formatHours(row.stats.AverageHours))
+	}
+	_ = w.Flush()
+
+	writeCounts(&b, "Size (changed lines)", report.Sizes)
+	writeCounts(&b, "Labels", report.Labels)
+	writeCounts(&b, "Review load (reviewed PRs per person)", report.ReviewLoad)
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func writeCounts(b *strings.Builder, title string, counts []Count) {
+	fmt.Fprintf(b, "\n%s:\n", title)
+	if len(counts) == 0 {
+		b.WriteString("  none\n")
+		return
+	}
+	w := tabwriter.NewWriter(b, 0, 0, 2, ' ', 0)
+	for _, count := range counts {
+		fmt.Fprintf(w, "  %s\t%d\n", count.Name, count.Count)
+	}
+	_ = w.Flush()
+}
+
+// formatHours formats a number of hours as days and hours, for example 1d 4h.
+func formatHours(h float64) string {
+	d := time.Duration(h * float64(time.Hour)).Round(time.Hour)
+	switch {
+	case d == 0 && h == 0:
+		return "-"
+	case d < time.Hour:
+		return "<1h"
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	days := int(d.Hours()) / 24
+	return fmt.Sprintf("%dd %dh", days, int(d.Hours())-days*24)
+}
+
+// WriteCSV writes the metrics of each pull request as CSV.
+func WriteCSV(w io.Writer, report Report) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{
+		"repository", "number", "title", "author", "created_at", "merged_at", "size", "changes", "label",
+		"first_review_hours", "merge_hours",
+	}); err != nil {
+		return err
+	}
+	for _, d := range report.Details {
+		if err := writer.Write([]string{
+			d.Repository, strconv.Itoa(d.Number), d.Title, d.Author, d.CreatedAt, d.MergedAt, d.Size,
+			strconv.Itoa(d.Changes), d.Label, formatOptionalHours(d.FirstReviewHours), formatOptionalHours(d.MergeHours),
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatOptionalHours(h *float64) string {
+	if h == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*h, 'f', -1, 64)
+}
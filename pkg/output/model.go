@@ -0,0 +1,10 @@
+// Package output renders command results as JSON, filtered with jq or formatted with a Go template.
+package output
+
+// Options represents the machine-readable output options of a command.
+type Options struct {
+	// Fields are the JSON fields to output. No fields means the command prints its usual human output.
+	Fields   []string
+	JQ       string
+	Template string
+}
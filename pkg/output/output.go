@@ -0,0 +1,146 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/jq"
+	"github.com/cli/go-gh/v2/pkg/template"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/pkg/errors"
+)
+
+// Enabled returns true if the command should print JSON instead of its usual output.
+func (opts *Options) Enabled() bool {
+	return opts != nil && len(opts.Fields) > 0
+}
+
+// Validate checks that jq and template are only used together with fields, and that all fields are available.
+func (opts *Options) Validate(available []string) error {
+	if opts.JQ != "" && opts.Template != "" {
+		return errors.New("only one of `--jq` or `--template` may be used")
+	}
+	if !opts.Enabled() {
+		switch {
+		case opts.JQ != "":
+			return errors.New("cannot use `--jq` without specifying `--json`")
+		case opts.Template != "":
+			return errors.New("cannot use `--template` without specifying `--json`")
+		}
+		return nil
+	}
+
+	for _, field := range opts.Fields {
+		if !slices.Contains(available, field) {
+			return errors.Errorf("Unknown JSON field: %q\nAvailable fields:\n  %s",
+				field, strings.Join(available, "\n  "))
+		}
+	}
+	return nil
+}
+
+// Fields returns the sorted JSON field names of a struct value, skipping fields that are not serialized.
+func Fields(v any) []string {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	var fields []string
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Write prints the selected fields of data, which is a struct or a slice of structs, to w. The JSON is passed
+// through the jq expression or the template if one is given, and printed indented otherwise.
+func Write(w io.Writer, data any, opts *Options) error {
+	selected, err := selectFields(data, opts.Fields)
+	if err != nil {
+		return err
+	}
+
+	if opts.JQ == "" && opts.Template == "" {
+		out, err := json.MarshalIndent(selected, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal JSON output")
+		}
+		_, err = fmt.Fprintln(w, string(out))
+		return err
+	}
+
+	input, err := json.Marshal(selected)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal JSON output")
+	}
+
+	if opts.JQ != "" {
+		return jq.Evaluate(bytes.NewReader(input), w, opts.JQ)
+	}
+
+	terminal := term.FromEnv()
+	width, _, err := terminal.Size()
+	if err != nil {
+		width = 80
+	}
+	t := template.New(w, width, terminal.IsColorEnabled())
+	if err := t.Parse(opts.Template); err != nil {
+		return err
+	}
+	if err := t.Execute(bytes.NewReader(input)); err != nil {
+		return err
+	}
+	return t.Flush()
+}
+
+// selectFields converts data to generic JSON values that only contain the given fields.
+func selectFields(data any, fields []string) (any, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal JSON output")
+	}
+
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal JSON output")
+	}
+
+	switch v := value.(type) {
+	case []any:
+		for i, item := range v {
+			v[i] = filterObject(item, fields)
+		}
+		return v, nil
+	default:
+		return filterObject(v, fields), nil
+	}
+}
+
+func filterObject(value any, fields []string) any {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+
+	filtered := make(map[string]any, len(fields))
+	for _, field := range fields {
+		filtered[field] = object[field]
+	}
+	return filtered
+}
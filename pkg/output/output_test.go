@@ -0,0 +1,133 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/elhub/gh-dxp/pkg/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Hidden string `json:"-"`
+	Author author `json:"author"`
+}
+
+type author struct {
+	Login string `json:"login"`
+}
+
+func TestFields(t *testing.T) {
+	assert.Equal(t, []string{"author", "number", "title"}, output.Fields(item{}))
+	assert.Equal(t, []string{"author", "number", "title"}, output.Fields([]item{}))
+}
+
+func TestValidate(t *testing.T) {
+	available := []string{"number", "title"}
+
+	tests := []struct {
+		name        string
+		opts        output.Options
+		expectedErr string
+	}{
+		{
+			name: "No output options",
+		},
+		{
+			name: "Known fields",
+			opts: output.Options{Fields: []string{"number", "title"}, JQ: ".[].number"},
+		},
+		{
+			name:        "Unknown field",
+			opts:        output.Options{Fields: []string{"number", "url"}},
+			expectedErr: "Unknown JSON field: \"url\"\nAvailable fields:\n  number\n  title",
+		},
+		{
+			name:        "jq without json",
+			opts:        output.Options{JQ: ".[].number"},
+			expectedErr: "cannot use `--jq` without specifying `--json`",
+		},
+		{
+			name:        "Template without json",
+			opts:        output.Options{Template: "{{.}}"},
+			expectedErr: "cannot use `--template` without specifying `--json`",
+		},
+		{
+			name:        "jq and template",
+			opts:        output.Options{Fields: []string{"number"}, JQ: ".", Template: "{{.}}"},
+			expectedErr: "only one of `--jq` or `--template` may be used",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate(available)
+
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedErr, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	items := []item{
+		{Number: 1, Title: "First", Hidden: "secret", Author: author{Login: "alice"}},
+		{Number: 2, Title: "Second", Author: author{Login: "bob"}},
+	}
+
+	tests := []struct {
+		name     string
+		data     any
+		opts     output.Options
+		expected string
+	}{
+		{
+			name:     "Selected fields of a list",
+			data:     items,
+			opts:     output.Options{Fields: []string{"number"}},
+			expected: "[\n  {\n    \"number\": 1\n  },\n  {\n    \"number\": 2\n  }\n]\n",
+		},
+		{
+			name:     "Selected fields of an object",
+			data:     items[0],
+			opts:     output.Options{Fields: []string{"author", "title"}},
+			expected: "{\n  \"author\": {\n    \"login\": \"alice\"\n  },\n  \"title\": \"First\"\n}\n",
+		},
+		{
+			name:     "jq expression",
+			data:     items,
+			opts:     output.Options{Fields: []string{"author"}, JQ: ".[].author.login"},
+			expected: "alice\nbob\n",
+		},
+		{
+			name:     "jq only sees the selected fields",
+			data:     items,
+			opts:     output.Options{Fields: []string{"number"}, JQ: ".[0] | keys"},
+			expected: "[\"number\"]\n",
+		},
+		{
+			name:     "Template",
+			data:     items,
+			opts:     output.Options{Fields: []string{"number", "title"}, Template: "{{range .}}#{{.number}} {{.title}}\n{{end}}"},
+			expected: "#1 First\n#2 Second\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := output.Write(&buf, tt.data, &tt.opts)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
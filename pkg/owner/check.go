@@ -0,0 +1,323 @@
+// Package owner provides the functionality to get the codeowners of a given path
+package owner
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/hmarr/codeowners"
+	"github.com/pkg/errors"
+)
+
+var parseErrorPrefix = regexp.MustCompile(`^line \d+: `)
+
+// IssuesFoundError signifies that the CODEOWNERS check found issues.
+type IssuesFoundError struct {
+	Count int
+}
+
+func (e *IssuesFoundError) Error() string {
+	return fmt.Sprintf("found %d issue(s) in the CODEOWNERS file", e.Count)
+}
+
+// ExecuteCheck checks the CODEOWNERS file of the repository and prints a report, either as text or as JSON. An
+// *IssuesFoundError is returned if the report contains issues, so the command can be used in CI.
+func ExecuteCheck(exe ghutil.Executor, opts *CheckOptions) error {
+	file, err := GetDefaultFile(exe)
+	if err != nil {
+		return err
+	}
+
+	report, err := Check(exe, file, opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.JSON {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to format the report")
+		}
+		if _, err := os.Stdout.Write(append(out, '\n')); err != nil {
+			return err
+		}
+	} else {
+		logger.Info(FormatReport(report, opts.AllowUnowned))
+	}
+
+	if count := report.IssueCount(opts.AllowUnowned); count > 0 {
+		return &IssuesFoundError{Count: count}
+	}
+	return nil
+}
+
+// Check analyzes the given CODEOWNERS file against the files tracked by git.
+func Check(exe ghutil.Executor, file string, opts *CheckOptions) (*Report, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rules, syntaxErrors, err := parseRules(f)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := exe.Command("git", "ls-files")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list tracked files")
+	}
+	files := ghutil.ConvertTerminalOutputIntoList(out)
+
+	report := &Report{
+		File:           file,
+		TrackedFiles:   len(files),
+		UnownedFiles:   []string{},
+		UnmatchedRules: []RuleRef{},
+		ShadowedRules:  []ShadowedRule{},
+		InvalidOwners:  []InvalidOwner{},
+		SyntaxErrors:   syntaxErrors,
+	}
+
+	if err := analyzeMatches(report, rules, files); err != nil {
+		return nil, err
+	}
+
+	if !opts.NoVerify {
+		repo, err := exe.GH("repo", "view", "--json", "nameWithOwner", "--jq", ".nameWithOwner")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to find the repository of the CODEOWNERS file")
+		}
+		report.InvalidOwners, err = verifyOwners(exe, strings.TrimSpace(repo), rules)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// parseRules parses the CODEOWNERS file line by line, so that all syntax errors are reported instead of only the
+// first one.
+func parseRules(r io.Reader) (codeowners.Ruleset, []SyntaxError, error) {
+	var rules codeowners.Ruleset
+	syntaxErrors := []SyntaxError{}
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parsed, err := codeowners.ParseFile(strings.NewReader(line))
+		if err != nil {
+			syntaxErrors = append(syntaxErrors, SyntaxError{
+				Line:    lineNo,
+				Message: parseErrorPrefix.ReplaceAllString(err.Error(), ""),
+			})
+			continue
+		}
+
+		for _, rule := range parsed {
+			rule.LineNumber = lineNo
+			rules = append(rules, rule)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read the CODEOWNERS file")
+	}
+	return rules, syntaxErrors, nil
+}
+
+// analyzeMatches finds unowned files, rules that match no files and rules whose files are all owned through later
+// rules. As in GitHub, the last matching rule determines the owners of a file.
+func analyzeMatches(report *Report, rules codeowners.Ruleset, files []string) error {
+	matches := make([]int, len(rules))
+	effective := make([]int, len(rules))
+	shadowedBy := make([]map[int]bool, len(rules))
+
+	for _, file := range files {
+		owner := -1
+		for i := len(rules) - 1; i >= 0; i-- {
+			match, err := rules[i].Match(file)
+			if err != nil {
+				return errors.Wrapf(err, "failed to match line %d", rules[i].LineNumber)
+			}
+			if !match {
+				continue
+			}
+
+			matches[i]++
+			if owner == -1 {
+				owner = i
+				effective[i]++
+				continue
+			}
+			if shadowedBy[i] == nil {
+				shadowedBy[i] = map[int]bool{}
+			}
+			shadowedBy[i][rules[owner].LineNumber] = true
+		}
+
+		if owner == -1 || len(rules[owner].Owners) == 0 {
+			report.UnownedFiles = append(report.UnownedFiles, file)
+		}
+	}
+
+	for i, rule := range rules {
+		ref := RuleRef{Line: rule.LineNumber, Pattern: rule.RawPattern()}
+		switch {
+		case matches[i] == 0:
+			report.UnmatchedRules = append(report.UnmatchedRules, ref)
+		case effective[i] == 0:
+			lines := make([]int, 0, len(shadowedBy[i]))
+			for line := range shadowedBy[i] {
+				lines = append(lines, line)
+			}
+			sort.Ints(lines)
+			report.ShadowedRules = append(report.ShadowedRules, ShadowedRule{RuleRef: ref, ShadowedBy: lines})
+		}
+	}
+
+	return nil
+}
+
+// verifyOwners checks through the GitHub API that every team owner exists and that every team and user owner has
+// write access to the repository, since GitHub ignores owners without it. Email owners cannot be verified and are
+// skipped.
+func verifyOwners(exe ghutil.Executor, repo string, rules codeowners.Ruleset) ([]InvalidOwner, error) {
+	lines := map[string][]int{}
+	owners := map[string]codeowners.Owner{}
+	for _, rule := range rules {
+		for _, o := range rule.Owners {
+			if !slices.Contains(lines[o.String()], rule.LineNumber) {
+				lines[o.String()] = append(lines[o.String()], rule.LineNumber)
+			}
+			owners[o.String()] = o
+		}
+	}
+
+	names := make([]string, 0, len(owners))
+	for name := range owners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	invalid := []InvalidOwner{}
+	for _, name := range names {
+		o := owners[name]
+		var reason string
+		switch o.Type {
+		case codeowners.TeamOwner:
+			reason = verifyTeam(exe, repo, o.Value)
+		case codeowners.UsernameOwner:
+			var err error
+			if reason, err = verifyUser(exe, repo, o.Value); err != nil {
+				return nil, errors.Wrapf(err, "failed to verify the owner %s", name)
+			}
+		}
+		if reason != "" {
+			invalid = append(invalid, InvalidOwner{Owner: name, Lines: lines[name], Reason: reason})
+		}
+	}
+	return invalid, nil
+}
+
+// verifyTeam returns why a team cannot own files in the repository, or an empty string if it can.
+func verifyTeam(exe ghutil.Executor, repo, value string) string {
+	org, team, _ := strings.Cut(value, "/")
+	if _, err := exe.GH("api", "orgs/"+org+"/teams/"+team); err != nil {
+		return "team does not exist or is not visible to you"
+	}
+
+	// The repository media type includes the permissions of the team in the response
+	push, err := exe.GH("api", "-H", "Accept: application/vnd.github.v3.repository+json",
+		"orgs/"+org+"/teams/"+team+"/repos/"+repo, "--jq", ".permissions.push")
+	if err != nil || strings.TrimSpace(push) != "true" {
+		return "team does not have write access to " + repo
+	}
+	return ""
+}
+
+// verifyUser returns why a user cannot own files in the repository, or an empty string if they can. Organization
+// members and outside collaborators alike need write access.
+func verifyUser(exe ghutil.Executor, repo, login string) (string, error) {
+	permission, err := exe.GH("api", "repos/"+repo+"/collaborators/"+login+"/permission", "--jq", ".permission")
+	if err != nil {
+		return "", err
+	}
+	if !slices.Contains([]string{"admin", "maintain", "write"}, strings.TrimSpace(permission)) {
+		return "user does not have write access to " + repo, nil
+	}
+	return "", nil
+}
+
+// FormatReport renders a report as human-readable text. Unowned files are listed, but only count as issues if
+// allowUnowned is false.
+func FormatReport(report *Report, allowUnowned bool) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Checked %s against %d tracked files\n", report.File, report.TrackedFiles)
+
+	if len(report.SyntaxErrors) > 0 {
+		b.WriteString("\nSyntax errors:\n")
+		for _, e := range report.SyntaxErrors {
+			fmt.Fprintf(&b, "  line %d: %s\n", e.Line, e.Message)
+		}
+	}
+	if len(report.InvalidOwners) > 0 {
+		b.WriteString("\nInvalid owners:\n")
+		for _, o := range report.InvalidOwners {
+			fmt.Fprintf(&b, "  %s (%s): %s\n", o.Owner, formatLines(o.Lines), o.Reason)
+		}
+	}
+	if len(report.UnmatchedRules) > 0 {
+		b.WriteString("\nRules that match no files:\n")
+		for _, r := range report.UnmatchedRules {
+			fmt.Fprintf(&b, "  line %d: %s\n", r.Line, r.Pattern)
+		}
+	}
+	if len(report.ShadowedRules) > 0 {
+		b.WriteString("\nRules shadowed by later rules:\n")
+		for _, r := range report.ShadowedRules {
+			fmt.Fprintf(&b, "  line %d: %s (shadowed by %s)\n", r.Line, r.Pattern, formatLines(r.ShadowedBy))
+		}
+	}
+	if len(report.UnownedFiles) > 0 {
+		fmt.Fprintf(&b, "\nUnowned files (%d):\n", len(report.UnownedFiles))
+		for _, f := range report.UnownedFiles {
+			fmt.Fprintf(&b, "  %s\n", f)
+		}
+	}
+
+	if report.IssueCount(allowUnowned) == 0 {
+		b.WriteString("No issues found\n")
+	}
+	return b.String()
+}
+
+func formatLines(lines []int) string {
+	parts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		parts = append(parts, strconv.Itoa(line))
+	}
+	if len(parts) == 1 {
+		return "line " + parts[0]
+	}
+	return "lines " + strings.Join(parts, ", ")
+}
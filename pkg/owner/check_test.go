@@ -0,0 +1,107 @@
+package owner_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/elhub/gh-dxp/pkg/owner"
+	"github.com/elhub/gh-dxp/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	root := createTempRepoWithCodeowners(t, `# Default owners
+* @elhub/devxp
+docs/ @elhub/writers
+*.go @elhub/backend
+pkg/ @elhub/backend @outsider @former @reader @maintainer
+*.py @elhub/python
+generated/ @elhub/devxp @elhub/devxp!
+vendor/
+`)
+	file := filepath.Join(root, ".github", "CODEOWNERS")
+
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("Command", "git", []string{"ls-files"}).
+		Return("README.md\ndocs/index.md\npkg/main.go\nvendor/lib.go\n", nil)
+	mockExe.On("GH", []string{"repo", "view", "--json", "nameWithOwner", "--jq", ".nameWithOwner"}).
+		Return("elhub/web\n", nil)
+	for team, push := range map[string]string{"devxp": "true", "writers": "false", "backend": "true"} {
+		mockExe.On("GH", []string{"api", "orgs/elhub/teams/" + team}).Return("{}", nil)
+		mockExe.On("GH", []string{"api", "-H", "Accept: application/vnd.github.v3.repository+json",
+			"orgs/elhub/teams/" + team + "/repos/elhub/web", "--jq", ".permissions.push"}).Return(push+"\n", nil)
+	}
+	mockExe.On("GH", []string{"api", "orgs/elhub/teams/python"}).Return("", errors.New("HTTP 404"))
+	for login, permission := range map[string]string{
+		"outsider": "write", "former": "none", "reader": "read", "maintainer": "maintain",
+	} {
+		mockExe.On("GH", []string{"api", "repos/elhub/web/collaborators/" + login + "/permission", "--jq",
+			".permission"}).Return(permission+"\n", nil)
+	}
+
+	report, err := owner.Check(mockExe, file, &owner.CheckOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.TrackedFiles)
+	assert.Equal(t, []string{"vendor/lib.go"}, report.UnownedFiles)
+	assert.Equal(t, []owner.RuleRef{{Line: 6, Pattern: "*.py"}}, report.UnmatchedRules)
+	assert.Equal(t, []owner.ShadowedRule{{RuleRef: owner.RuleRef{Line: 4, Pattern: "*.go"}, ShadowedBy: []int{5, 8}}},
+		report.ShadowedRules)
+	assert.Equal(t, []owner.InvalidOwner{
+		{Owner: "@elhub/python", Lines: []int{6}, Reason: "team does not exist or is not visible to you"},
+		{Owner: "@elhub/writers", Lines: []int{3}, Reason: "team does not have write access to elhub/web"},
+		{Owner: "@former", Lines: []int{5}, Reason: "user does not have write access to elhub/web"},
+		{Owner: "@reader", Lines: []int{5}, Reason: "user does not have write access to elhub/web"},
+	}, report.InvalidOwners)
+	require.Len(t, report.SyntaxErrors, 1)
+	assert.Equal(t, 7, report.SyntaxErrors[0].Line)
+	assert.Equal(t, "unexpected character '!' at position 37", report.SyntaxErrors[0].Message)
+	assert.Equal(t, 8, report.IssueCount(false))
+	assert.Equal(t, 7, report.IssueCount(true))
+	mockExe.AssertExpectations(t)
+
+	text := owner.FormatReport(report, false)
+	assert.Contains(t, text, "line 4: *.go (shadowed by lines 5, 8)")
+	assert.Contains(t, text, "@reader (line 5): user does not have write access to elhub/web")
+}
+
+func TestCheckOwnerLookupFails(t *testing.T) {
+	root := createTempRepoWithCodeowners(t, `* @ghost`)
+
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("Command", "git", []string{"ls-files"}).Return("README.md\n", nil)
+	mockExe.On("GH", []string{"repo", "view", "--json", "nameWithOwner", "--jq", ".nameWithOwner"}).
+		Return("elhub/web\n", nil)
+	mockExe.On("GH", []string{"api", "repos/elhub/web/collaborators/ghost/permission", "--jq", ".permission"}).
+		Return("", errors.New("HTTP 404"))
+
+	_, err := owner.Check(mockExe, filepath.Join(root, ".github", "CODEOWNERS"), &owner.CheckOptions{})
+
+	require.EqualError(t, err, "failed to verify the owner @ghost: HTTP 404")
+}
+
+func TestCheckNoVerify(t *testing.T) {
+	root := createTempRepoWithCodeowners(t, `* @elhub/devxp`)
+
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("Command", "git", []string{"ls-files"}).Return("README.md\n", nil)
+
+	report, err := owner.Check(mockExe, filepath.Join(root, ".github", "CODEOWNERS"), &owner.CheckOptions{NoVerify: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.IssueCount(false))
+	assert.Contains(t, owner.FormatReport(report, false), "No issues found")
+	mockExe.AssertNotCalled(t, "GH")
+}
+
+func TestFormatReportAllowUnowned(t *testing.T) {
+	report := &owner.Report{File: ".github/CODEOWNERS", TrackedFiles: 2, UnownedFiles: []string{"vendor/lib.go"}}
+
+	assert.NotContains(t, owner.FormatReport(report, false), "No issues found")
+
+	text := owner.FormatReport(report, true)
+	assert.Contains(t, text, "Unowned files (1):\n  vendor/lib.go")
+	assert.Contains(t, text, "No issues found")
+}
@@ -0,0 +1,131 @@
+// Package owner provides the functionality to get the codeowners of a given path
+package owner
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/hmarr/codeowners"
+	"github.com/pkg/errors"
+)
+
+// NoOwner is the group of files not covered by any CODEOWNERS rule.
+const NoOwner = "(no owner)"
+
+// OwnerFiles lists the changed files owned by a single owner.
+type OwnerFiles struct {
+	Owner string
+	Files []string
+}
+
+// ExecuteDiff prints the owners of the files changed on the current branch, with the files each of them owns.
+func ExecuteDiff(exe ghutil.Executor) error {
+	owners, err := DiffOwners(exe, "")
+	if err != nil {
+		return err
+	}
+
+	if len(owners) == 0 {
+		logger.Info("No changed files found")
+		return nil
+	}
+
+	var b strings.Builder
+	for _, o := range owners {
+		b.WriteString(o.Owner + "\n")
+		for _, file := range o.Files {
+			b.WriteString("  " + file + "\n")
+		}
+	}
+	_, err = os.Stdout.Write([]byte(b.String()))
+	return err
+}
+
+// DiffOwners groups the files changed on the current branch by their owners. The changes are compared to baseBranch,
+// or to the default branch if it is empty. Files with several owners appear under each of them, and files without an
+// owner are grouped under NoOwner. Owners are sorted by the number of files they own, most first.
+func DiffOwners(exe ghutil.Executor, baseBranch string) ([]OwnerFiles, error) {
+	file, err := GetDefaultFile(exe)
+	if err != nil {
+		return nil, err
+	}
+	ruleset, err := codeowners.LoadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	changedFiles, err := changedFilesSince(exe, baseBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	byOwner := map[string][]string{}
+	for _, changed := range changedFiles {
+		rule, err := ruleset.Match(changed)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to match %s", changed)
+		}
+		if rule == nil || len(rule.Owners) == 0 {
+			byOwner[NoOwner] = append(byOwner[NoOwner], changed)
+			continue
+		}
+		for _, o := range rule.Owners {
+			byOwner[o.String()] = append(byOwner[o.String()], changed)
+		}
+	}
+
+	owners := make([]OwnerFiles, 0, len(byOwner))
+	for o, files := range byOwner {
+		owners = append(owners, OwnerFiles{Owner: o, Files: files})
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		if len(owners[i].Files) != len(owners[j].Files) {
+			return len(owners[i].Files) > len(owners[j].Files)
+		}
+		return owners[i].Owner < owners[j].Owner
+	})
+	return owners, nil
+}
+
+// SuggestReviewers returns the owners of the files changed on the current branch compared to baseBranch as reviewer
+// ids for gh, leaving out the author and email owners. Owners of more files come first, and at most limit reviewers
+// are returned if limit is positive.
+func SuggestReviewers(exe ghutil.Executor, baseBranch, author string, limit int) ([]string, error) {
+	owners, err := DiffOwners(exe, baseBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers := []string{}
+	for _, o := range owners {
+		reviewer, ok := strings.CutPrefix(o.Owner, "@")
+		if !ok || strings.EqualFold(reviewer, author) {
+			continue
+		}
+		if limit > 0 && len(reviewers) >= limit {
+			break
+		}
+		reviewers = append(reviewers, reviewer)
+	}
+	return reviewers, nil
+}
+
+// changedFilesSince returns the files changed on the current branch since it diverged from baseBranch on origin, or
+// the files changed compared to the default branch if baseBranch is empty.
+func changedFilesSince(exe ghutil.Executor, baseBranch string) ([]string, error) {
+	if baseBranch == "" {
+		return ghutil.GetChangedFiles(exe)
+	}
+
+	if _, err := exe.Command("git", "fetch", "origin", baseBranch); err != nil {
+		return nil, errors.Wrap(err, "failed to fetch "+baseBranch)
+	}
+	out, err := exe.Command("git", "diff", "--name-only", "origin/"+baseBranch+"...HEAD", "--relative")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the files changed since "+baseBranch)
+	}
+	return ghutil.ConvertTerminalOutputIntoList(out), nil
+}
@@ -0,0 +1,95 @@
+package owner_test
+
+import (
+	"testing"
+
+	"github.com/elhub/gh-dxp/pkg/owner"
+	"github.com/elhub/gh-dxp/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockDiff(mockExe *testutils.MockExecutor, root, changedFiles string) {
+	mockExe.On("Command", "git", []string{"rev-parse", "--show-toplevel"}).Return(root+"\n", nil)
+	mockExe.On("Command", "git", []string{"branch"}).Return("main\nfeature\n", nil)
+	mockExe.On("Command", "git", []string{"fetch", "origin", "main"}).Return("", nil)
+	mockExe.On("Command", "git", []string{"remote", "set-head", "origin", "--auto"}).Return("", nil)
+	mockExe.On("Command", "git", []string{"symbolic-ref", "--short", "refs/remotes/origin/HEAD"}).Return("origin/main\n", nil)
+	mockExe.On("Command", "git", []string{"diff", "--name-only", "origin/main", "--relative"}).Return(changedFiles, nil)
+}
+
+const diffCodeowners = `* @elhub/devxp
+pkg/api/ @elhub/api @alice
+docs/ @elhub/writers docs@elhub.no
+vendor/
+`
+
+func TestDiffOwners(t *testing.T) {
+	root := createTempRepoWithCodeowners(t, diffCodeowners)
+	mockExe := new(testutils.MockExecutor)
+	mockDiff(mockExe, root, "pkg/api/a.go\npkg/api/b.go\ndocs/index.md\nvendor/lib.go\nMakefile\n")
+
+	owners, err := owner.DiffOwners(mockExe, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, []owner.OwnerFiles{
+		{Owner: "@alice", Files: []string{"pkg/api/a.go", "pkg/api/b.go"}},
+		{Owner: "@elhub/api", Files: []string{"pkg/api/a.go", "pkg/api/b.go"}},
+		{Owner: "(no owner)", Files: []string{"vendor/lib.go"}},
+		{Owner: "@elhub/devxp", Files: []string{"Makefile"}},
+		{Owner: "@elhub/writers", Files: []string{"docs/index.md"}},
+		{Owner: "docs@elhub.no", Files: []string{"docs/index.md"}},
+	}, owners)
+}
+
+func TestSuggestReviewers(t *testing.T) {
+	tests := []struct {
+		name     string
+		author   string
+		limit    int
+		expected []string
+	}{
+		{
+			name:     "all owners except the author and email owners",
+			author:   "Alice",
+			expected: []string{"elhub/api", "elhub/devxp", "elhub/writers"},
+		},
+		{
+			name:     "limited",
+			author:   "bob",
+			limit:    2,
+			expected: []string{"alice", "elhub/api"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := createTempRepoWithCodeowners(t, diffCodeowners)
+			mockExe := new(testutils.MockExecutor)
+			mockDiff(mockExe, root, "pkg/api/a.go\npkg/api/b.go\ndocs/index.md\nvendor/lib.go\nMakefile\n")
+
+			reviewers, err := owner.SuggestReviewers(mockExe, "", tt.author, tt.limit)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, reviewers)
+		})
+	}
+}
+
+func TestDiffOwnersSinceBaseBranch(t *testing.T) {
+	root := createTempRepoWithCodeowners(t, diffCodeowners)
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("Command", "git", []string{"rev-parse", "--show-toplevel"}).Return(root+"\n", nil)
+	mockExe.On("Command", "git", []string{"fetch", "origin", "release/1.0"}).Return("", nil)
+	mockExe.On("Command", "git", []string{"diff", "--name-only", "origin/release/1.0...HEAD", "--relative"}).
+		Return("docs/index.md\n", nil)
+
+	owners, err := owner.DiffOwners(mockExe, "release/1.0")
+
+	require.NoError(t, err)
+	assert.Equal(t, []owner.OwnerFiles{
+		{Owner: "@elhub/writers", Files: []string{"docs/index.md"}},
+		{Owner: "docs@elhub.no", Files: []string{"docs/index.md"}},
+	}, owners)
+	mockExe.AssertNotCalled(t, "Command", "git", []string{"branch"})
+}
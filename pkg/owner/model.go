@@ -0,0 +1,68 @@
+// Package owner provides the functionality to get the codeowners of a given path
+package owner
+
+// CheckOptions represents the options for the owner check command.
+type CheckOptions struct {
+	JSON         bool
+	NoVerify     bool
+	AllowUnowned bool
+}
+
+// Report is the result of checking a CODEOWNERS file against the tracked files of a repository.
+type Report struct {
+	File           string         `json:"file"`
+	TrackedFiles   int            `json:"trackedFiles"`
+	UnownedFiles   []string       `json:"unownedFiles"`
+	UnmatchedRules []RuleRef      `json:"unmatchedRules"`
+	ShadowedRules  []ShadowedRule `json:"shadowedRules"`
+	InvalidOwners  []InvalidOwner `json:"invalidOwners"`
+	SyntaxErrors   []SyntaxError  `json:"syntaxErrors"`
+}
+
+// RuleRef identifies a CODEOWNERS rule by its line number and pattern.
+type RuleRef struct {
+	Line    int    `json:"line"`
+	Pattern string `json:"pattern"`
+}
+
+// ShadowedRule is a rule that matches files, but all of them are owned through later rules.
+type ShadowedRule struct {
+	RuleRef
+	ShadowedBy []int `json:"shadowedBy"`
+}
+
+// InvalidOwner is an owner that is not a valid user or team, or that cannot own files in the repository.
+type InvalidOwner struct {
+	Owner  string `json:"owner"`
+	Lines  []int  `json:"lines"`
+	Reason string `json:"reason"`
+}
+
+// SyntaxError is a line of the CODEOWNERS file that could not be parsed.
+type SyntaxError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// IssueCount returns the number of issues in the report. Unowned files are only counted if allowUnowned is false.
+func (r *Report) IssueCount(allowUnowned bool) int {
+	count := len(r.UnmatchedRules) + len(r.ShadowedRules) + len(r.InvalidOwners) + len(r.SyntaxErrors)
+	if !allowUnowned {
+		count += len(r.UnownedFiles)
+	}
+	return count
+}
+
+// Options represents the options for looking up the owners of a path.
+type Options struct {
+	History         bool
+	MaxContributors int
+}
+
+// Ownership is the set of owners of a path and the source they were found in: the path of the CODEOWNERS file
+// (with the line of the matching rule) or SourceHistory.
+type Ownership struct {
+	Owners []string
+	Source string
+	Line   int
+}
@@ -2,44 +2,190 @@
 package owner
 
 import (
+	"bufio"
 	"errors"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/elhub/gh-dxp/pkg/ghutil"
 	"github.com/hmarr/codeowners"
 )
 
-// Execute determines the owner of the specified file based on the CODEOWNERS file given in the .github directory.
+// SourceHistory is the source of owners derived from the git history.
+const SourceHistory = "git history"
+
+// defaultMaxContributors is the number of top contributors returned by the history fallback if no limit is given.
+const defaultMaxContributors = 3
+
+// CodeownersLocations lists the locations GitHub reads the CODEOWNERS file from, in order of precedence.
+var CodeownersLocations = []string{ //nolint: gochecknoglobals // Constant list of CODEOWNERS locations.
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+var shortlogLine = regexp.MustCompile(`^\s*(\d+)\s+(.+)$`)
+
+// Execute determines the owner of the specified file based on the repository's CODEOWNERS file.
 func Execute(path string, exe ghutil.Executor) ([]string, error) {
+	ownership, err := Resolve(path, exe, &Options{})
+	if err != nil {
+		return nil, err
+	}
+	return ownership.Owners, nil
+}
+
+// Resolve determines the owners of the specified path and where they came from. The CODEOWNERS file is used first;
+// if no rule gives the path an owner and opts.History is set, the top contributors to the path according to git
+// are returned instead.
+func Resolve(path string, exe ghutil.Executor, opts *Options) (*Ownership, error) {
 	gitRoot, err := ghutil.GetGitRootDirectory(exe)
 	if err != nil {
 		return nil, err
 	}
 
-	codeownersFile, err := os.Open(gitRoot + "/.github/CODEOWNERS")
+	file, findErr := FindCodeownersFile(gitRoot)
+	if findErr == nil {
+		ownership, err := matchCodeowners(gitRoot, file, path)
+		if err != nil {
+			return nil, err
+		}
+		if len(ownership.Owners) > 0 || !opts.History {
+			return ownership, nil
+		}
+	} else if !opts.History {
+		return nil, findErr
+	}
+
+	contributors, err := TopContributors(exe, path, opts.MaxContributors)
+	if err != nil {
+		return nil, err
+	}
+	return &Ownership{Owners: contributors, Source: SourceHistory}, nil
+}
+
+func matchCodeowners(gitRoot, file, path string) (*Ownership, error) {
+	codeownersFile, err := os.Open(file)
 	if err != nil {
 		return nil, err
 	}
+	defer codeownersFile.Close()
 
 	ruleset, err := codeowners.ParseFile(codeownersFile)
 	if err != nil {
 		return nil, err
 	}
 
+	source, err := filepath.Rel(gitRoot, file)
+	if err != nil {
+		source = file
+	}
+	ownership := &Ownership{Owners: []string{}, Source: filepath.ToSlash(source)}
+
 	rule, err := ruleset.Match(path)
 	if err != nil {
 		return nil, err
 	}
+	if rule == nil {
+		return ownership, nil
+	}
 
 	// Convert rule.Owners to []string
-	owners := make([]string, len(rule.Owners))
-	for i, owner := range rule.Owners {
-		owners[i] = owner.String()
+	for _, owner := range rule.Owners {
+		ownership.Owners = append(ownership.Owners, owner.String())
+	}
+	ownership.Line = rule.LineNumber
+	return ownership, nil
+}
+
+// TopContributors returns the people who contributed most to the given path, as "Name <email>". For a file, the
+// authors of its current lines are counted using git blame; for a directory, the commits touching it are counted
+// using git shortlog.
+func TopContributors(exe ghutil.Executor, path string, limit int) ([]string, error) {
+	if limit <= 0 {
+		limit = defaultMaxContributors
+	}
+
+	var counts map[string]int
+	var err error
+	if info, statErr := os.Stat(path); statErr == nil && !info.IsDir() {
+		counts, err = blameCounts(exe, path)
+	} else {
+		counts, err = shortlogCounts(exe, path)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	contributors := make([]string, 0, len(counts))
+	for contributor := range counts {
+		contributors = append(contributors, contributor)
+	}
+	sort.Slice(contributors, func(i, j int) bool {
+		if counts[contributors[i]] != counts[contributors[j]] {
+			return counts[contributors[i]] > counts[contributors[j]]
+		}
+		return contributors[i] < contributors[j]
+	})
+
+	if len(contributors) > limit {
+		contributors = contributors[:limit]
+	}
+	return contributors, nil
+}
+
+func blameCounts(exe ghutil.Executor, path string) (map[string]int, error) {
+	out, err := exe.Command("git", "blame", "--line-porcelain", "--", path)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	name := ""
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if after, ok := strings.CutPrefix(line, "author "); ok {
+			name = after
+		} else if after, ok := strings.CutPrefix(line, "author-mail "); ok && name != "Not Committed Yet" {
+			counts[name+" "+after]++
+		}
+	}
+	return counts, nil
+}
 
-	// Return the owners of the file
-	return owners, nil
+func shortlogCounts(exe ghutil.Executor, path string) (map[string]int, error) {
+	out, err := exe.Command("git", "shortlog", "-sne", "--no-merges", "HEAD", "--", path)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, line := range strings.Split(out, "\n") {
+		match := shortlogLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		count, _ := strconv.Atoi(match[1])
+		counts[strings.TrimSpace(match[2])] += count
+	}
+	return counts, nil
+}
+
+// FindCodeownersFile returns the path of the CODEOWNERS file in the given repository root, looking in the same
+// locations and order as GitHub.
+func FindCodeownersFile(rootDir string) (string, error) {
+	for _, location := range CodeownersLocations {
+		ownersFile := filepath.Join(rootDir, location)
+		if ghutil.FileExists(ownersFile) {
+			return ownersFile, nil
+		}
+	}
+	return "", errors.New("could not find CODEOWNERS file in the .github directory, the repository root or docs")
 }
 
 // GetDefaultFile returns the default file to check for codeowners.
@@ -49,11 +195,5 @@ func GetDefaultFile(exe ghutil.Executor) (string, error) {
 		return "", err
 	}
 
-	ownersFile := filepath.Join(rootDir, ".github/CODEOWNERS")
-
-	if ghutil.FileExists(ownersFile) {
-		return ownersFile, nil
-	}
-
-	return "", errors.New("could not find CODEOWNERS file in .github directory")
+	return FindCodeownersFile(rootDir)
 }
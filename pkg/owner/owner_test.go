@@ -50,7 +50,7 @@ src/ @elhub/developers`),
 			path:        "README.md",
 			gitRoot:     createTempRepoWithoutCodeowners(t),
 			expectErr:   true,
-			errContains: "no such file or directory",
+			errContains: "could not find CODEOWNERS file",
 		},
 		{
 			name:           "Multiple team owners",
@@ -0,0 +1,113 @@
+package owner_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elhub/gh-dxp/pkg/owner"
+	"github.com/elhub/gh-dxp/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCodeowners(t *testing.T, root, location, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, location)), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, location), []byte(content), 0o600))
+}
+
+func TestResolveCodeownersPrecedence(t *testing.T) {
+	tests := []struct {
+		name           string
+		files          map[string]string
+		expectedOwners []string
+		expectedSource string
+	}{
+		{
+			name:           "root CODEOWNERS",
+			files:          map[string]string{"CODEOWNERS": "* @elhub/root"},
+			expectedOwners: []string{"@elhub/root"},
+			expectedSource: "CODEOWNERS",
+		},
+		{
+			name:           "docs CODEOWNERS",
+			files:          map[string]string{"docs/CODEOWNERS": "* @elhub/docs"},
+			expectedOwners: []string{"@elhub/docs"},
+			expectedSource: "docs/CODEOWNERS",
+		},
+		{
+			name: ".github takes precedence",
+			files: map[string]string{
+				".github/CODEOWNERS": "# Owners\n* @elhub/github",
+				"CODEOWNERS":         "* @elhub/root",
+				"docs/CODEOWNERS":    "* @elhub/docs",
+			},
+			expectedOwners: []string{"@elhub/github"},
+			expectedSource: ".github/CODEOWNERS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for location, content := range tt.files {
+				writeCodeowners(t, root, location, content)
+			}
+			mockExe := new(testutils.MockExecutor)
+			mockExe.On("Command", "git", []string{"rev-parse", "--show-toplevel"}).Return(root+"\n", nil)
+
+			ownership, err := owner.Resolve("README.md", mockExe, &owner.Options{})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedOwners, ownership.Owners)
+			assert.Equal(t, tt.expectedSource, ownership.Source)
+		})
+	}
+}
+
+func TestResolveHistoryFallback(t *testing.T) {
+	root := t.TempDir()
+	writeCodeowners(t, root, "CODEOWNERS", "docs/ @elhub/docs\n")
+	t.Chdir(root)
+	require.NoError(t, os.WriteFile("main.go", []byte("package main\n"), 0o600))
+
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("Command", "git", []string{"rev-parse", "--show-toplevel"}).Return(root+"\n", nil)
+	mockExe.On("Command", "git", []string{"blame", "--line-porcelain", "--", "main.go"}).Return(`abc 1 1 1
+author Jane Doe
+author-mail <jane@elhub.no>
+	package main
+abc 2 2 1
+author John Doe
+author-mail <john@elhub.no>
+	import "fmt"
+abc 3 3 1
+author Jane Doe
+author-mail <jane@elhub.no>
+	func main() {}
+def 4 4 1
+author Not Committed Yet
+author-mail <not.committed.yet>
+	// wip
+`, nil)
+	mockExe.On("Command", "git", []string{"shortlog", "-sne", "--no-merges", "HEAD", "--", "pkg"}).
+		Return("    12\tJohn Doe <john@elhub.no>\n     3\tJane Doe <jane@elhub.no>\n     1\tBot <bot@elhub.no>\n", nil)
+
+	ownership, err := owner.Resolve("main.go", mockExe, &owner.Options{History: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, owner.SourceHistory, ownership.Source)
+	assert.Equal(t, []string{"Jane Doe <jane@elhub.no>", "John Doe <john@elhub.no>"}, ownership.Owners)
+
+	ownership, err = owner.Resolve("pkg", mockExe, &owner.Options{History: true, MaxContributors: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"John Doe <john@elhub.no>", "Jane Doe <jane@elhub.no>"}, ownership.Owners)
+
+	ownership, err = owner.Resolve("docs/index.md", mockExe, &owner.Options{History: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, "CODEOWNERS", ownership.Source)
+	assert.Equal(t, 1, ownership.Line)
+}
@@ -1,13 +1,139 @@
 package pr
 
-import "charm.land/bubbles/v2/table"
+import (
+	"fmt"
+
+	"charm.land/bubbles/v2/table"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/elhub/gh-dxp/pkg/check"
+	"github.com/elhub/gh-dxp/pkg/config"
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/jira"
+)
 
 type PrAuthor = prAuthor
 type PrRepository = prRepository
 
-var InitialModel = initialModel           //nolint:gochecknoglobals // Expose for testing
 var ValidateLocalChanges = validateLocalChanges //nolint:gochecknoglobals // Expose for testing
 
+func InitialModel(pullRequests []PullRequestInfo) PullRequestUI {
+	return initialModel(pullRequests, nil)
+}
+
+func InitialModelWithColumns(pullRequests []PullRequestInfo, names []string) (PullRequestUI, error) {
+	columns, err := selectColumns(names)
+	if err != nil {
+		return PullRequestUI{}, err
+	}
+	return initialModel(pullRequests, columns), nil
+}
+
+// Rows returns the cells of the table without colours.
 func (ui PullRequestUI) Rows() []table.Row {
-	return ui.table.Rows()
+	var rows []table.Row
+	for _, row := range ui.table.Rows() {
+		plain := make(table.Row, len(row))
+		for i, cell := range row {
+			plain[i] = ansi.Strip(cell)
+		}
+		rows = append(rows, plain)
+	}
+	return rows
+}
+
+func (ui PullRequestUI) ColumnWidths() []int {
+	var widths []int
+	for _, col := range ui.table.Columns() {
+		widths = append(widths, col.Width)
+	}
+	return widths
+}
+
+func (ui *PullRequestUI) FitColumns(width int) {
+	ui.fitColumns(width)
+}
+
+var FormatAge = formatAge //nolint:gochecknoglobals // Expose for testing
+
+var AddCodeOwnerReviewers = addCodeOwnerReviewers //nolint:gochecknoglobals // Expose for testing
+
+func (options *CreateOptions) SetBaseBranch(baseBranch string) {
+	options.baseBranch = baseBranch
+}
+
+var FilterPullRequests = filterPullRequests //nolint:gochecknoglobals // Expose for testing
+var FetchPullRequests = fetchPullRequests   //nolint:gochecknoglobals // Expose for testing
+
+func (ui PullRequestUI) Interactive(exe ghutil.Executor, fetch func() ([]PullRequestInfo, error)) PullRequestUI {
+	return ui.interactive(exe, fetch, 0)
+}
+
+func (ui PullRequestUI) Visible() []PullRequestInfo {
+	return ui.visible
+}
+
+func (ui PullRequestUI) Status() string {
+	return ui.status
+}
+
+type ReviewComment = reviewComment
+
+// ParseDiff returns the lines of each file in the diff as "<kind> <old line> <new line> <text>".
+func ParseDiff(diff string) map[string][]string {
+	files := map[string][]string{}
+	for _, file := range parseDiff(diff) {
+		lines := []string{}
+		for _, line := range file.lines {
+			lines = append(lines, fmt.Sprintf("%c %d %d %s", line.kind, line.oldLine, line.newLine, line.text))
+		}
+		files[file.path] = lines
+	}
+	return files
+}
+
+func InitialReviewModel(number int, diff string) ReviewUI {
+	return initialReviewModel(reviewTarget{Number: number}, parseDiff(diff))
+}
+
+func (ui ReviewUI) Comments() []ReviewComment {
+	return ui.comments
+}
+
+func (ui ReviewUI) Event() string {
+	return ui.event
+}
+
+func (ui ReviewUI) Body() string {
+	return ui.body
+}
+
+func (ui ReviewUI) Status() string {
+	return ui.status
+}
+
+func SubmitReview(exe ghutil.Executor, id string, number int, event, body string, comments []ReviewComment) error {
+	return submitReview(exe, reviewTarget{ID: id, Number: number, HeadRefOid: "abc123"}, event, body, comments)
+}
+
+func ReplaceManagedSections(body, commits, checks, docs string) (string, []string) {
+	return replaceManagedSections(body, []bodySection{
+		{name: sectionCommits, content: commits},
+		{name: sectionChecks, content: checks},
+		{name: sectionDocs, content: docs},
+	})
+}
+
+func RefreshBody(exe ghutil.Executor, branchID, targetBranch, prID string, checklist ...string) error {
+	pr := PullRequest{branchID: branchID, targetBranch: targetBranch}
+	for _, line := range checklist {
+		pr.checks = append(pr.checks, check.Result{Checklist: line})
+	}
+	return refreshBody(exe, pr, prID)
+}
+
+var SelectIssues = selectIssues               //nolint:gochecknoglobals // Expose for testing
+var UpdateIssuesOnMerge = updateIssuesOnMerge //nolint:gochecknoglobals // Expose for testing
+
+func UpdateIssuesOnCreate(client *jira.Client, settings *config.Settings, title, prURL string, issues ...string) {
+	updateIssuesOnCreate(client, settings, PullRequest{Title: title, issues: issues}, prURL)
 }
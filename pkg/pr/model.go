@@ -2,28 +2,43 @@
 package pr
 
 import (
+	"time"
+
 	"charm.land/bubbles/v2/table"
+	"charm.land/bubbles/v2/textinput"
+	"charm.land/bubbles/v2/viewport"
+	"github.com/elhub/gh-dxp/pkg/check"
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/output"
 )
 
 // Options represents the options for the pr command.
 type Options struct {
-	TestRun bool
-	NoLint  bool
-	NoUnit  bool
+	TestRun        bool
+	NoLint         bool
+	NoUnit         bool
+	NonInteractive bool
 
 	CommitMessage string
 }
 
 // CreateOptions represents the options for the pr create command.
 type CreateOptions struct {
-	TestRun bool
-	NoLint  bool
-	NoUnit  bool
-	Draft   bool
+	TestRun          bool
+	NoLint           bool
+	NoUnit           bool
+	Draft            bool
+	PublishChecks    bool
+	SuggestReviewers bool
+
+	// NonInteractive uses the given or default values instead of prompting for them.
+	NonInteractive bool
 
 	Branch        string
 	CommitMessage string
 	Issues        string
+	Title         string
+	Body          string
 
 	baseBranch string
 
@@ -36,6 +51,35 @@ type ListOptions struct {
 	TestRun         bool
 	Mine            bool
 	ReviewRequested bool
+	NoTUI           bool
+
+	// RefreshInterval is how often the dashboard reloads the pull requests. Zero disables the automatic refresh.
+	RefreshInterval time.Duration
+
+	// Columns are the names of the columns to show, in order. The default columns are shown if none are given.
+	Columns []string
+
+	// Output prints the pull requests as JSON instead of showing the dashboard.
+	Output output.Options
+
+	// The views that are combined with the pull requests selected by Mine and ReviewRequested.
+	TeamReview         bool
+	ReviewedNewCommits bool
+	Orgs               []string
+	Repos              []string
+	// StaleDays lists the pull requests involving the user that were opened more than this many days ago.
+	StaleDays int
+}
+
+// CheckoutOptions represents the options for the pr checkout command.
+type CheckoutOptions struct {
+	NoLint bool
+	NoUnit bool
+
+	// RunChecks runs the check pipeline of pr create after the pull request is checked out.
+	RunChecks bool
+	// NoSteps skips the post-checkout steps configured in .devxp.
+	NoSteps bool
 }
 
 // MergeOptions represents the options for the pr merge command.
@@ -43,11 +87,25 @@ type MergeOptions struct {
 	AutoConfirm bool
 }
 
+// ReviewOptions represents the options for the pr review command. If none of Approve, Comment and RequestChanges is
+// set, the diff is shown so that line comments can be added before the review is submitted.
+type ReviewOptions struct {
+	TestRun        bool
+	Approve        bool
+	Comment        bool
+	RequestChanges bool
+
+	Body string
+}
+
 // UpdateOptions represents the options for the pr update command.
 type UpdateOptions struct {
-	TestRun bool
-	NoLint  bool
-	NoUnit  bool
+	TestRun       bool
+	NoLint        bool
+	NoUnit        bool
+	PublishChecks bool
+	// RefreshBody regenerates the sections of the PR body that were generated by pr create.
+	RefreshBody bool
 
 	CommitMessage string
 }
@@ -58,32 +116,126 @@ type PullRequest struct {
 	targetBranch string
 	Title        string
 	Body         string
-	isLinted     bool
-	isTested     bool
+	checks       []check.Result
 	label        string
+	issues       []string
 }
 
+// pullRequestFields are the fields retrieved for each pull request found by the searches of pr list.
+const pullRequestFields = `fragment pullRequests on SearchResultItemConnection {
+  pageInfo { hasNextPage endCursor }
+  nodes {
+    ... on PullRequest {
+      number title url createdAt additions deletions reviewDecision isDraft mergeable headRefOid
+      author { __typename login ... on User { id name } ... on Bot { id } }
+      repository { nameWithOwner }
+      headRepository { id name }
+      labels(first: 20) { nodes { name } }
+      commits(last: 1) { nodes { commit { statusCheckRollup { state } } } }
+      viewerLatestReview { commit { oid } }
+      reviewRequests(first: 100) { nodes { requestedReviewer { ... on User { isViewer } } } }
+    }
+  }
+}`
+
 // The following structs are used to unmarshal the JSON responses from the GitHub API.
-type searchResult struct {
-	Number     int              `json:"number"`
-	Repository searchRepository `json:"repository"`
+type pullRequestsResponse struct {
+	// Data holds the result of each search by its alias
+	Data map[string]*pullRequestSearch `json:"data"`
+}
+
+type pullRequestSearch struct {
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Nodes []pullRequestNode `json:"nodes"`
+}
+
+type pullRequestNode struct {
+	Number         int    `json:"number"`
+	Title          string `json:"title"`
+	URL            string `json:"url"`
+	CreatedAt      string `json:"createdAt"`
+	Additions      int    `json:"additions"`
+	Deletions      int    `json:"deletions"`
+	ReviewDecision string `json:"reviewDecision"`
+	IsDraft        bool   `json:"isDraft"`
+	Mergeable      string `json:"mergeable"`
+	HeadRefOid     string `json:"headRefOid"`
+	Author         struct {
+		Typename string `json:"__typename"`
+		ID       string `json:"id"`
+		Login    string `json:"login"`
+		Name     string `json:"name"`
+	} `json:"author"`
+	Repository struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"repository"`
+	HeadRepository *prRepository `json:"headRepository"`
+	Labels         struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					State string `json:"state"`
+				} `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+	ViewerLatestReview *struct {
+		Commit *struct {
+			Oid string `json:"oid"`
+		} `json:"commit"`
+	} `json:"viewerLatestReview"`
+	ReviewRequests struct {
+		Nodes []struct {
+			RequestedReviewer struct {
+				IsViewer bool `json:"isViewer"`
+			} `json:"requestedReviewer"`
+		} `json:"nodes"`
+	} `json:"reviewRequests"`
 }
 
-type searchRepository struct {
-	Name          string `json:"name"`
-	NameWithOwner string `json:"nameWithOwner"`
+// listView is one of the searches combined by pr list, for example the pull requests in an organization.
+type listView struct {
+	name  string
+	query string
+	// keep, if set, drops the pull requests found by the search that do not belong to the view
+	keep func(node pullRequestNode) bool
+
+	// done and after track the pagination of the search
+	done  bool
+	after string
 }
 
 // PullRequestInfo represents detailed information about a pull request.
 type PullRequestInfo struct {
 	Additions      int          `json:"additions"`
 	Author         prAuthor     `json:"author"`
+	ChecksState    string       `json:"checksState"`
 	CreatedAt      string       `json:"createdAt"`
 	Deletions      int          `json:"deletions"`
 	HeadRepository prRepository `json:"headRepository"`
+	IsDraft        bool         `json:"isDraft"`
+	Labels         []string     `json:"labels"`
+	Mergeable      string       `json:"mergeable"`
 	Number         int          `json:"number"`
 	ReviewDecision string       `json:"reviewDecision"`
 	Title          string       `json:"title"`
+	URL            string       `json:"url"`
+
+	// ReviewRequested is true if the pull request is waiting for the user's review.
+	ReviewRequested bool `json:"reviewRequested"`
+	// Views are the views of pr list that the pull request was found in, for example mine or org:elhub.
+	Views []string `json:"views"`
+
+	// Repository is the owner/name of the repository the pull request was opened in.
+	Repository string `json:"repository"`
 }
 
 type prRepository struct {
@@ -100,9 +252,106 @@ type prAuthor struct {
 
 // PullRequestUI represents the UI model for displaying pull requests.
 type PullRequestUI struct {
-	table table.Model
+	table        table.Model
+	columns      []listColumn
+	pullRequests []PullRequestInfo
+	visible      []PullRequestInfo
+
+	// exe and fetch are only set for the interactive dashboard.
+	exe             ghutil.Executor
+	fetch           func() ([]PullRequestInfo, error)
+	refreshInterval time.Duration
+	refreshedAt     time.Time
+
+	filter     string
+	input      textinput.Model
+	inputMode  inputMode
+	detail     viewport.Model
+	showDetail bool
+	detailName string
+	status     string
+	width      int
+	height     int
+}
+
+// inputMode is what the text input of the dashboard is currently used for.
+type inputMode int
+
+const (
+	inputNone inputMode = iota
+	inputFilter
+	inputRequestChanges
+)
+
+// reviewTarget is the pull request being reviewed.
+type reviewTarget struct {
+	ID         string `json:"id"`
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	URL        string `json:"url"`
+	HeadRefOid string `json:"headRefOid"`
 }
 
+// diffFile is a file in the diff of a pull request.
+type diffFile struct {
+	path  string
+	lines []diffLine
+}
+
+// diffLine is a line of a diff. Hunk headers have neither an old nor a new line number, and cannot be commented on.
+type diffLine struct {
+	kind    byte // '+', '-', ' ' or '@' for a hunk header
+	text    string
+	oldLine int
+	newLine int
+}
+
+// reviewComment is a comment on a line of the diff. Side is LEFT for removed lines, and RIGHT otherwise.
+type reviewComment struct {
+	Path string
+	Line int
+	Side string
+	Body string
+}
+
+// ReviewUI represents the UI model for reviewing the diff of a pull request.
+type ReviewUI struct {
+	target   reviewTarget
+	files    []diffFile
+	file     int
+	cursor   int
+	offset   int
+	comments []reviewComment
+
+	input     textinput.Model
+	inputMode reviewInputMode
+	status    string
+	width     int
+	height    int
+
+	// event and body are set when the review is submitted.
+	event string
+	body  string
+}
+
+// reviewInputMode is what the text input of the review UI is currently used for.
+type reviewInputMode int
+
+const (
+	reviewInputNone reviewInputMode = iota
+	reviewInputLineComment
+	reviewInputApprove
+	reviewInputComment
+	reviewInputRequestChanges
+)
+
+// The events a review can be submitted with.
+const (
+	reviewEventApprove        = "APPROVE"
+	reviewEventComment        = "COMMENT"
+	reviewEventRequestChanges = "REQUEST_CHANGES"
+)
+
 // PullRequestLabel represents the model for the pull request label.
 type PullRequestLabel struct {
 	Name        string
@@ -6,12 +6,11 @@ import (
 	"strings"
 
 	"github.com/elhub/gh-dxp/pkg/branch"
+	"github.com/elhub/gh-dxp/pkg/check"
 	"github.com/elhub/gh-dxp/pkg/config"
 	"github.com/elhub/gh-dxp/pkg/ghutil"
-	"github.com/elhub/gh-dxp/pkg/lint"
+	"github.com/elhub/gh-dxp/pkg/largefile"
 	"github.com/elhub/gh-dxp/pkg/logger"
-	"github.com/elhub/gh-dxp/pkg/renovate"
-	"github.com/elhub/gh-dxp/pkg/test"
 	"github.com/pkg/errors"
 )
 
@@ -117,7 +116,7 @@ func handleUntrackedChanges(exe ghutil.Executor, options *Options) error {
 	}
 
 	// Skip if no untracked changes or in test mode
-	if len(untrackedChanges) == 0 || options.TestRun {
+	if len(untrackedChanges) == 0 || options.TestRun || options.NonInteractive {
 		return nil
 	}
 
@@ -167,7 +166,7 @@ func handleUncommittedChanges(exe ghutil.Executor, options *Options) ([]string,
 	}
 
 	// Skip confirmation if test run or commit message already provided
-	if options.TestRun || options.CommitMessage != "" {
+	if options.TestRun || options.NonInteractive || options.CommitMessage != "" {
 		return uncommittedTrackedChanges, nil
 	}
 
@@ -191,6 +190,8 @@ func addAndCommitFiles(exe ghutil.Executor, options *Options) error {
 		commitMessage = options.CommitMessage
 	case options.TestRun:
 		commitMessage = "default commit message"
+	case options.NonInteractive:
+		return errors.New("A commit message is required to commit the changes")
 	default:
 		commitMessage, err = ghutil.AskForString("Please enter a commit message: ", "")
 		if err != nil {
@@ -232,6 +233,12 @@ func performPreCreateOperations(exe ghutil.Executor, settings *config.Settings,
 		return pr, nil
 	}
 
+	if options.NonInteractive {
+		logger.Info("Setting PR Label to \"Chore\"")
+		pr.label = "Chore"
+		return pr, nil
+	}
+
 	return promptForLabel(pr)
 
 }
@@ -247,27 +254,15 @@ func performPreCreateUpdateOperations(exe ghutil.Executor, settings *config.Sett
 		return pr, err
 	}
 
-	// Run lint
-	if !options.NoLint {
-		err = lint.Run(exe, settings, &lint.Options{})
-		if err != nil {
-			return pr, err
-		}
-
-		// Run renovate config validation
-		err = renovate.Run(exe, settings, &renovate.Options{})
-		if err != nil {
-			return pr, err
-		}
-		pr.isLinted = true
-	}
-
-	// Run tests
-	if !options.NoUnit {
-		pr.isTested, err = test.RunTest(exe)
-		if err != nil {
-			return pr, err
-		}
+	// Run the check pipeline (lint, renovate, secrets and test by default, or as configured in .devxp)
+	pr.checks, err = check.Run(exe, settings, &check.Options{
+		TestRun:    options.TestRun || options.NonInteractive,
+		NoLint:     options.NoLint,
+		NoUnit:     options.NoUnit,
+		BaseBranch: pr.targetBranch,
+	})
+	if err != nil {
+		return pr, err
 	}
 
 	if len(filesToCommit) > 0 {
@@ -277,6 +272,16 @@ func performPreCreateUpdateOperations(exe ghutil.Executor, settings *config.Sett
 		}
 	}
 
+	// Check the outgoing commits for files that should not be pushed
+	err = largefile.Run(exe, settings, &largefile.Options{
+		TestRun: options.TestRun || options.NonInteractive,
+		Branch:  pr.branchID,
+		BaseRef: "origin/" + pr.targetBranch,
+	})
+	if err != nil {
+		return pr, err
+	}
+
 	return pr, nil
 }
 
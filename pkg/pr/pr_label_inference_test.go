@@ -106,6 +106,9 @@ func TestPerformPreCreateOperationsAutoSetsLabelFromConventionalCommit(t *testin
 	mockExe.On("Command", "git", []string{"status", "--porcelain"}).Return("", nil)
 	mockExe.On("Command", "git", []string{"log", "--oneline", "origin/main.."}).Return("abc123 feat: add inference", nil)
 	mockExe.On("Command", "git", []string{"log", "main..feature-branch", "--oneline", "--pretty=format:%s"}).Return("feat: add inference", nil)
+	mockExe.On("Command", "git", []string{"merge-base", "HEAD", "origin/main"}).Return("abc123\n", nil)
+	mockExe.On("Command", "git", []string{"log", "--format=", "--raw", "--no-abbrev", "--no-renames", "--diff-filter=AM", "origin/main..HEAD"}).Return("", nil)
+	mockExe.On("Command", "git", []string{"diff", "--unified=0", "--no-color", "--no-ext-diff", "abc123"}).Return("", nil)
 
 	prIn := PullRequest{
 		branchID:     "feature-branch",
@@ -120,3 +123,27 @@ func TestPerformPreCreateOperationsAutoSetsLabelFromConventionalCommit(t *testin
 	assert.Equal(t, "Feature", prOut.label)
 	mockExe.AssertExpectations(t)
 }
+
+func TestPerformPreCreateOperationsDefaultsLabelWhenNonInteractive(t *testing.T) {
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("Command", "git", []string{"status", "--porcelain"}).Return("", nil)
+	mockExe.On("Command", "git", []string{"log", "--oneline", "origin/main.."}).Return("abc123 Update workflow", nil)
+	mockExe.On("Command", "git", []string{"log", "main..feature-branch", "--oneline", "--pretty=format:%s"}).Return("Update workflow", nil)
+	mockExe.On("Command", "git", []string{"merge-base", "HEAD", "origin/main"}).Return("abc123\n", nil)
+	mockExe.On("Command", "git", []string{"log", "--format=", "--raw", "--no-abbrev", "--no-renames", "--diff-filter=AM", "origin/main..HEAD"}).Return("", nil)
+	mockExe.On("Command", "git", []string{"diff", "--unified=0", "--no-color", "--no-ext-diff", "abc123"}).Return("", nil)
+
+	prIn := PullRequest{
+		branchID:     "feature-branch",
+		targetBranch: "main",
+	}
+
+	prOut, err := performPreCreateOperations(mockExe, &config.Settings{}, prIn, &Options{
+		NoLint:         true,
+		NoUnit:         true,
+		NonInteractive: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Chore", prOut.label)
+	mockExe.AssertExpectations(t)
+}
@@ -0,0 +1,118 @@
+package pr
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/elhub/gh-dxp/pkg/branch"
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+// The sections of the PR body that are generated by gh dxp, and can be regenerated by pr update --refresh-body.
+const (
+	sectionCommits = "commits"
+	sectionChecks  = "checks"
+	sectionDocs    = "docs"
+)
+
+// bodySection is the generated content of a managed section of the PR body.
+type bodySection struct {
+	name    string
+	content string
+}
+
+// managedSection wraps the content in the marker comments of the section, so that it can be found and replaced later.
+// The markers are kept when the content is empty, so the section can be filled in by a later refresh.
+func managedSection(name, content string) string {
+	content = strings.TrimSuffix(content, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	return "<!-- dxp:start:" + name + " -->\n" + content + "<!-- dxp:end:" + name + " -->"
+}
+
+func managedSectionRegex(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(?s)<!-- dxp:start:` + regexp.QuoteMeta(name) + ` -->\n?.*?<!-- dxp:end:` +
+		regexp.QuoteMeta(name) + ` -->`)
+}
+
+// replaceManagedSections replaces the content between the markers of each section, and leaves the rest of the body
+// as it is. Sections without markers in the body are not added. It returns the new body and the names of the sections
+// that were found.
+func replaceManagedSections(body string, sections []bodySection) (string, []string) {
+	var found []string
+	for _, section := range sections {
+		re := managedSectionRegex(section.name)
+		if !re.MatchString(body) {
+			continue
+		}
+		found = append(found, section.name)
+		replacement := managedSection(section.name, section.content)
+		body = re.ReplaceAllLiteralString(body, replacement)
+	}
+	return body, found
+}
+
+// commitSummary lists the commit messages after the first, which is used as the title of the PR.
+func commitSummary(commits string) string {
+	commitLines := strings.Split(commits, "\n")
+	if len(commitLines) <= 1 {
+		return ""
+	}
+	var sb strings.Builder
+	for _, line := range commitLines[1:] {
+		sb.WriteString("* " + line + "\n")
+	}
+	return sb.String()
+}
+
+// checksChecklist returns the checklist lines of the check results.
+func checksChecklist(pr PullRequest) string {
+	var lines []string
+	for _, result := range pr.checks {
+		if result.Checklist != "" {
+			lines = append(lines, result.Checklist)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// refreshBody regenerates the managed sections of the body of the PR with the commits, check results and
+// documentation changes of the current branch.
+func refreshBody(exe ghutil.Executor, pr PullRequest, prID string) error {
+	body, err := GetPRBody(exe)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch the description of PR #"+prID)
+	}
+
+	commits, err := branch.GetCommitMessages(exe, pr.targetBranch, pr.branchID)
+	if err != nil {
+		return err
+	}
+	docs, err := documentationChanges(exe)
+	if err != nil {
+		return err
+	}
+
+	newBody, found := replaceManagedSections(body, []bodySection{
+		{name: sectionCommits, content: commitSummary(commits)},
+		{name: sectionChecks, content: checksChecklist(pr)},
+		{name: sectionDocs, content: docs},
+	})
+	if len(found) == 0 {
+		logger.Warn("The description of PR #" + prID + " has no sections generated by gh dxp, so it was not refreshed")
+		return nil
+	}
+	if strings.TrimSpace(newBody) == strings.TrimSpace(body) {
+		logger.Info("The description of PR #" + prID + " is up to date")
+		return nil
+	}
+
+	if _, err := exe.GH("pr", "edit", prID, "--body", newBody); err != nil {
+		return errors.Wrap(err, "failed to update the description of PR #"+prID)
+	}
+	logger.Info("Refreshed the " + strings.Join(found, ", ") + " sections of the description of PR #" + prID)
+	return nil
+}
@@ -0,0 +1,166 @@
+package pr_test
+
+import (
+	"testing"
+
+	"github.com/elhub/gh-dxp/pkg/pr"
+	"github.com/elhub/gh-dxp/pkg/testutils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const managedBody = `## 📝 Description
+
+I rewrote the parser, see the notes below.
+
+<!-- dxp:start:commits -->
+* Add parser
+<!-- dxp:end:commits -->
+
+## 📋 Checklist
+
+<!-- dxp:start:checks -->
+* ⛔ **Lint checks failed on local machine.**
+<!-- dxp:end:checks -->
+* ✅ This PR adds new tests.
+<!-- dxp:start:docs -->
+<!-- dxp:end:docs -->
+
+Notes for the reviewer.
+`
+
+func TestReplaceManagedSections(t *testing.T) {
+	t.Run("replaces the content between the markers and keeps the rest", func(t *testing.T) {
+		body, found := pr.ReplaceManagedSections(managedBody,
+			"* Add parser\n* Fix parser\n",
+			"* ✅ Lint checks passed on local machine.",
+			"* ✅ Documentation Updates: README")
+
+		assert.Equal(t, []string{"commits", "checks", "docs"}, found)
+		assert.Equal(t, `## 📝 Description
+
+I rewrote the parser, see the notes below.
+
+<!-- dxp:start:commits -->
+* Add parser
+* Fix parser
+<!-- dxp:end:commits -->
+
+## 📋 Checklist
+
+<!-- dxp:start:checks -->
+* ✅ Lint checks passed on local machine.
+<!-- dxp:end:checks -->
+* ✅ This PR adds new tests.
+<!-- dxp:start:docs -->
+* ✅ Documentation Updates: README
+<!-- dxp:end:docs -->
+
+Notes for the reviewer.
+`, body)
+	})
+
+	t.Run("empties a section and keeps its markers", func(t *testing.T) {
+		body, _ := pr.ReplaceManagedSections(managedBody, "", "* ✅ Lint checks passed on local machine.", "")
+
+		assert.Contains(t, body, "<!-- dxp:start:commits -->\n<!-- dxp:end:commits -->")
+
+		// The markers are still there, so the section can be filled in again
+		body, _ = pr.ReplaceManagedSections(body, "* Fix parser", "", "")
+		assert.Contains(t, body, "<!-- dxp:start:commits -->\n* Fix parser\n<!-- dxp:end:commits -->")
+	})
+
+	t.Run("does not add sections without markers", func(t *testing.T) {
+		handWritten := "## 📝 Description\n\nWritten by hand.\n"
+
+		body, found := pr.ReplaceManagedSections(handWritten, "* Add parser", "* ✅ Lint", "")
+
+		assert.Empty(t, found)
+		assert.Equal(t, handWritten, body)
+	})
+}
+
+func TestRefreshBody(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		editErr      error
+		expectedEdit bool
+		expectedErr  string
+	}{
+		{
+			name:         "updates the description with the managed sections",
+			body:         managedBody,
+			expectedEdit: true,
+		},
+		{
+			name: "does not update a description without managed sections",
+			body: "Written by hand.",
+		},
+		{
+			name: "does not update a description that is up to date",
+			body: "<!-- dxp:start:checks -->\n* ✅ Lint checks passed on local machine.\n<!-- dxp:end:checks -->",
+		},
+		{
+			name:         "fails if the description cannot be updated",
+			body:         managedBody,
+			editErr:      errors.New("HTTP 403"),
+			expectedEdit: true,
+			expectedErr:  "failed to update the description of PR #3: HTTP 403",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExe := new(testutils.MockExecutor)
+			mockExe.On("GH", []string{"pr", "view", "--json", "body", "--jq", ".body"}).Return(tt.body+"\n", nil)
+			mockExe.On("Command", "git", []string{"log", "main..branch1", "--oneline", "--pretty=format:%s"}).
+				Return("Add parser\nFix parser", nil)
+			mockExe.On("Command", "git", []string{"branch"}).Return("main\nbranch1\n", nil)
+			mockExe.On("Command", "git", []string{"fetch", "origin", "main"}).Return("", nil)
+			mockExe.On("Command", "git", []string{"remote", "set-head", "origin", "--auto"}).Return("", nil)
+			mockExe.On("Command", "git", []string{"symbolic-ref", "--short", "refs/remotes/origin/HEAD"}).
+				Return("origin/main", nil)
+			mockExe.On("Command", "git", []string{"diff", "--name-only", "origin/main", "--relative"}).
+				Return("README.md\npkg/parser.go\n", nil)
+			prEdit := mock.MatchedBy(func(args []string) bool {
+				return len(args) > 1 && args[0] == "pr" && args[1] == "edit"
+			})
+			mockExe.On("GH", prEdit).Return("", tt.editErr)
+
+			err := pr.RefreshBody(mockExe, "branch1", "main", "3", "* ✅ Lint checks passed on local machine.")
+
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if !tt.expectedEdit {
+				mockExe.AssertNotCalled(t, "GH", prEdit)
+				return
+			}
+			mockExe.AssertCalled(t, "GH", []string{"pr", "edit", "3", "--body", `## 📝 Description
+
+I rewrote the parser, see the notes below.
+
+<!-- dxp:start:commits -->
+* Fix parser
+<!-- dxp:end:commits -->
+
+## 📋 Checklist
+
+<!-- dxp:start:checks -->
+* ✅ Lint checks passed on local machine.
+<!-- dxp:end:checks -->
+* ✅ This PR adds new tests.
+<!-- dxp:start:docs -->
+* ✅ Documentation Updates: README
+<!-- dxp:end:docs -->
+
+Notes for the reviewer.`})
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package pr
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strconv"
+
+	"github.com/elhub/gh-dxp/pkg/check"
+	"github.com/elhub/gh-dxp/pkg/config"
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+// pullRequestArgRegex matches a pull request number or the URL of a pull request.
+var pullRequestArgRegex = regexp.MustCompile( //nolint: gochecknoglobals // Compiled once.
+	`^(\d+|https://[^/\s]+/[^/\s]+/[^/\s]+/pull/\d+/?)$`)
+
+type checkoutInfo struct {
+	Number              int    `json:"number"`
+	Title               string `json:"title"`
+	HeadRefName         string `json:"headRefName"`
+	BaseRefName         string `json:"baseRefName"`
+	IsCrossRepository   bool   `json:"isCrossRepository"`
+	HeadRepositoryOwner struct {
+		Login string `json:"login"`
+	} `json:"headRepositoryOwner"`
+	Author struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+// ExecuteCheckout checks out the head branch of a pull request, given by its number or URL, and prepares it for
+// review by running the post-checkout steps and, optionally, the check pipeline.
+func ExecuteCheckout(
+	exe ghutil.Executor, settings *config.Settings, pullRequest string, options *CheckoutOptions,
+) error {
+	if !pullRequestArgRegex.MatchString(pullRequest) {
+		return errors.Errorf("invalid pull request %q, use a PR number or URL", pullRequest)
+	}
+
+	res, err := exe.GH("pr", "view", pullRequest, "--json",
+		"number,title,headRefName,baseRefName,isCrossRepository,headRepositoryOwner,author")
+	if err != nil {
+		return errors.Wrap(err, "failed to find pull request "+pullRequest)
+	}
+	var info checkoutInfo
+	if err := json.Unmarshal([]byte(res), &info); err != nil {
+		return errors.Wrap(err, "failed to unmarshal the pull request")
+	}
+	prID := "#" + strconv.Itoa(info.Number)
+
+	branch := info.HeadRefName
+	if info.IsCrossRepository {
+		branch = info.HeadRepositoryOwner.Login + ":" + branch
+	}
+	logger.Infof("Checking out %s (%s) by %s from %s", prID, info.Title, info.Author.Login, branch)
+
+	// gh pr checkout adds the fork as a remote if the branch is not in this repository
+	if _, err := exe.GH("pr", "checkout", pullRequest); err != nil {
+		return errors.Wrap(err, "failed to check out pull request "+prID)
+	}
+
+	if !options.NoSteps {
+		if err := runCheckoutSteps(exe, settings.PRCheckout.Steps); err != nil {
+			return err
+		}
+	}
+
+	if !options.RunChecks {
+		logger.Infof("Checked out %s, use --check to run the checks of pr create", prID)
+		return nil
+	}
+
+	results, err := check.Run(exe, settings, &check.Options{
+		NoLint:     options.NoLint,
+		NoUnit:     options.NoUnit,
+		BaseBranch: info.BaseRefName,
+	})
+	logger.Info("Check results for " + prID + ":")
+	for _, result := range results {
+		logger.Infof("  %s %s: %s", result.Icon(), result.Name, result.Status)
+	}
+	if err != nil {
+		return errors.Wrap(err, "the checks of pull request "+prID+" failed")
+	}
+	return nil
+}
+
+// runCheckoutSteps runs the post-checkout steps in order, and stops at the first step that fails.
+func runCheckoutSteps(exe ghutil.Executor, steps []config.CheckoutStep) error {
+	for i, step := range steps {
+		if step.Run == "" {
+			return errors.Errorf("post-checkout step #%d must define run", i+1)
+		}
+		name := step.Name
+		if name == "" {
+			name = step.Run
+		}
+
+		logger.Infof("Running post-checkout step %s: %s", name, step.Run)
+		if err := exe.CommandContext(context.Background(), "sh", "-c", step.Run); err != nil {
+			return errors.Wrapf(err, "post-checkout step %s failed", name)
+		}
+	}
+	return nil
+}
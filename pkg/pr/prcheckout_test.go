@@ -0,0 +1,146 @@
+package pr_test
+
+import (
+	"testing"
+
+	"github.com/elhub/gh-dxp/pkg/config"
+	"github.com/elhub/gh-dxp/pkg/pr"
+	"github.com/elhub/gh-dxp/pkg/testutils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteCheckout(t *testing.T) {
+	viewArgs := func(pullRequest string) []string {
+		return []string{"pr", "view", pullRequest, "--json",
+			"number,title,headRefName,baseRefName,isCrossRepository,headRepositoryOwner,author"}
+	}
+	prView := `{"number": 42, "title": "Add feature", "headRefName": "feature", "isCrossRepository": true,
+		"headRepositoryOwner": {"login": "someone"}, "author": {"login": "someone"}}`
+	steps := []config.CheckoutStep{
+		{Name: "Install dependencies", Run: "npm ci"},
+		{Run: "make generate"},
+	}
+	checks := []config.Check{{Name: "Unit tests", Run: "make test"}}
+
+	tests := []struct {
+		name        string
+		pullRequest string
+		options     *pr.CheckoutOptions
+		settings    *config.Settings
+		viewErr     error
+		checkoutErr error
+		stepErr     error
+		checkErr    error
+		expectSteps bool
+		expectCheck bool
+		expectedErr string
+	}{
+		{
+			name:        "checks out a pull request by number and runs the steps",
+			pullRequest: "42",
+			options:     &pr.CheckoutOptions{},
+			settings:    &config.Settings{PRCheckout: config.PRCheckout{Steps: steps}},
+			expectSteps: true,
+		},
+		{
+			name:        "checks out a pull request by URL without steps",
+			pullRequest: "https://github.com/elhub/gh-dxp/pull/42",
+			options:     &pr.CheckoutOptions{},
+			settings:    &config.Settings{},
+		},
+		{
+			name:        "skips the steps with NoSteps",
+			pullRequest: "42",
+			options:     &pr.CheckoutOptions{NoSteps: true},
+			settings:    &config.Settings{PRCheckout: config.PRCheckout{Steps: steps}},
+		},
+		{
+			name:        "runs the checks after the steps",
+			pullRequest: "42",
+			options:     &pr.CheckoutOptions{RunChecks: true},
+			settings:    &config.Settings{Checks: checks, PRCheckout: config.PRCheckout{Steps: steps}},
+			expectSteps: true,
+			expectCheck: true,
+		},
+		{
+			name:        "rejects an argument that is not a pull request",
+			pullRequest: "feature-branch",
+			options:     &pr.CheckoutOptions{},
+			settings:    &config.Settings{},
+			expectedErr: `invalid pull request "feature-branch", use a PR number or URL`,
+		},
+		{
+			name:        "fails if the pull request is not found",
+			pullRequest: "42",
+			options:     &pr.CheckoutOptions{},
+			settings:    &config.Settings{},
+			viewErr:     errors.New("not found"),
+			expectedErr: "failed to find pull request 42: not found",
+		},
+		{
+			name:        "fails if the checkout fails",
+			pullRequest: "42",
+			options:     &pr.CheckoutOptions{},
+			settings:    &config.Settings{},
+			checkoutErr: errors.New("local changes"),
+			expectedErr: "failed to check out pull request #42: local changes",
+		},
+		{
+			name:        "fails if a step fails",
+			pullRequest: "42",
+			options:     &pr.CheckoutOptions{RunChecks: true},
+			settings:    &config.Settings{Checks: checks, PRCheckout: config.PRCheckout{Steps: steps[:1]}},
+			stepErr:     errors.New("exit status 1"),
+			expectSteps: true,
+			expectedErr: "post-checkout step Install dependencies failed: exit status 1",
+		},
+		{
+			name:        "fails if a step has no command",
+			pullRequest: "42",
+			options:     &pr.CheckoutOptions{},
+			settings:    &config.Settings{PRCheckout: config.PRCheckout{Steps: []config.CheckoutStep{{Name: "Empty"}}}},
+			expectedErr: "post-checkout step #1 must define run",
+		},
+		{
+			name:        "fails if a required check fails",
+			pullRequest: "42",
+			options:     &pr.CheckoutOptions{RunChecks: true, NoSteps: true},
+			settings:    &config.Settings{Checks: checks},
+			checkErr:    errors.New("exit status 2"),
+			expectCheck: true,
+			expectedErr: "the checks of pull request #42 failed: exit status 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExe := new(testutils.MockExecutor)
+			mockExe.On("GH", viewArgs(tt.pullRequest)).Return(prView, tt.viewErr)
+			mockExe.On("GH", []string{"pr", "checkout", tt.pullRequest}).Return("", tt.checkoutErr)
+			mockExe.On("CommandContext", mock.Anything, "sh", []string{"-c", "npm ci"}).Return(nil, tt.stepErr)
+			mockExe.On("CommandContext", mock.Anything, "sh", []string{"-c", "make generate"}).Return(nil, nil)
+			mockExe.On("CommandContext", mock.Anything, "sh", []string{"-c", "make test"}).Return(nil, tt.checkErr)
+
+			err := pr.ExecuteCheckout(mockExe, tt.settings, tt.pullRequest, tt.options)
+
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tt.expectSteps {
+				mockExe.AssertCalled(t, "CommandContext", mock.Anything, "sh", []string{"-c", "npm ci"})
+			} else {
+				mockExe.AssertNotCalled(t, "CommandContext", mock.Anything, "sh", []string{"-c", "npm ci"})
+			}
+			if tt.expectCheck {
+				mockExe.AssertCalled(t, "CommandContext", mock.Anything, "sh", []string{"-c", "make test"})
+			} else {
+				mockExe.AssertNotCalled(t, "CommandContext", mock.Anything, "sh", []string{"-c", "make test"})
+			}
+		})
+	}
+}
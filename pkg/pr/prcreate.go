@@ -3,12 +3,16 @@ package pr
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/elhub/gh-dxp/pkg/branch"
+	"github.com/elhub/gh-dxp/pkg/check"
 	"github.com/elhub/gh-dxp/pkg/config"
 	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/jira"
 	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/elhub/gh-dxp/pkg/owner"
 	"github.com/pkg/errors"
 )
 
@@ -68,10 +72,11 @@ func ExecuteCreate(exe ghutil.Executor, settings *config.Settings, options *Crea
 	}
 
 	prOpts := &Options{
-		TestRun:       options.TestRun,
-		NoLint:        options.NoLint,
-		NoUnit:        options.NoUnit,
-		CommitMessage: options.CommitMessage,
+		TestRun:        options.TestRun,
+		NoLint:         options.NoLint,
+		NoUnit:         options.NoUnit,
+		NonInteractive: options.NonInteractive,
+		CommitMessage:  options.CommitMessage,
 	}
 
 	pr, err = performPreCreateOperations(exe, settings, pr, prOpts)
@@ -81,7 +86,7 @@ func ExecuteCreate(exe ghutil.Executor, settings *config.Settings, options *Crea
 
 	if prID != "" {
 		// If the PR exists, update it by pushing to the remote
-		return update(exe, pr.branchID, prID)
+		return update(exe, pr, prID, settings.PublishChecks || options.PublishChecks)
 	}
 
 	// If it doesn't exist, create a new PR
@@ -98,7 +103,17 @@ func create(exe ghutil.Executor, options *CreateOptions, settings *config.Settin
 	}
 	s.Stop()
 	logger.Info("Current Branch:" + currentBranch + "\n")
-	newPR, err := createPR(exe, options, settings, pr, options.baseBranch)
+
+	if settings.PublishChecks || options.PublishChecks {
+		publishCheckResults(exe, pr)
+	}
+
+	client, err := jira.NewClient(settings.Jira, nil)
+	if err != nil {
+		return err
+	}
+
+	newPR, err := createPR(exe, options, settings, pr, options.baseBranch, client)
 	if err != nil {
 		return err
 	}
@@ -108,6 +123,11 @@ func create(exe ghutil.Executor, options *CreateOptions, settings *config.Settin
 		return err
 	}
 
+	err = addCodeOwnerReviewers(exe, options, settings)
+	if err != nil {
+		return err
+	}
+
 	s = ghutil.StartSpinner("Processing pull request...", "Pull request "+newPR.Title+" created.")
 	args := []string{"pr", "create", "--title", newPR.Title, "--body", newPR.Body, "--base", options.baseBranch, "--label", pr.label}
 	args = append(args, generatePRArgs(options)...)
@@ -117,8 +137,72 @@ func create(exe ghutil.Executor, options *CreateOptions, settings *config.Settin
 		return errors.Wrap(err, "Failed to create pull request")
 	}
 	s.Stop()
-	logger.Info(strings.Trim(stdOut, "\n"))
+	prURL := strings.Trim(stdOut, "\n")
+	logger.Info(prURL)
+
+	updateIssuesOnCreate(client, settings, newPR, prURL)
+
+	return nil
+}
+
+// addCodeOwnerReviewers adds the code owners of the changed files to the requested reviewers, depending on the
+// reviewer settings. The author is never added, and the total number of reviewers is kept within the configured
+// maximum.
+func addCodeOwnerReviewers(exe ghutil.Executor, options *CreateOptions, settings *config.Settings) error {
+	mode := settings.Reviewers.FromCodeOwners
+	if options.SuggestReviewers && mode != config.ReviewersAuto {
+		mode = config.ReviewersSuggest
+	}
+	if mode != config.ReviewersSuggest && mode != config.ReviewersAuto {
+		return nil
+	}
+
+	limit := settings.Reviewers.Max
+	if limit > 0 && len(options.Reviewers) >= limit {
+		return nil
+	}
+
+	author, err := exe.GH("api", "user", "--jq", ".login")
+	if err != nil {
+		logger.Warn("Could not determine the current user, skipping reviewer suggestions: " + err.Error())
+		return nil
+	}
+
+	suggested, err := owner.SuggestReviewers(exe, options.baseBranch, strings.TrimSpace(author), 0)
+	if err != nil {
+		logger.Warn("Could not determine the code owners of the changes: " + err.Error())
+		return nil
+	}
+
+	var reviewers []string
+	for _, reviewer := range suggested {
+		if limit > 0 && len(options.Reviewers)+len(reviewers) >= limit {
+			break
+		}
+		if !slices.Contains(options.Reviewers, reviewer) {
+			reviewers = append(reviewers, reviewer)
+		}
+	}
+	if len(reviewers) == 0 {
+		return nil
+	}
+
+	if mode == config.ReviewersSuggest {
+		if options.TestRun || options.NonInteractive {
+			logger.Info("Suggested reviewers: " + strings.Join(reviewers, ", "))
+			return nil
+		}
+		confirmed, err := ghutil.AskToConfirm("Request reviews from the code owners " + strings.Join(reviewers, ", ") + "?")
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return nil
+		}
+	}
 
+	logger.Info("Requesting reviews from " + strings.Join(reviewers, ", "))
+	options.Reviewers = append(options.Reviewers, reviewers...)
 	return nil
 }
 
@@ -166,7 +250,7 @@ func generatePRArgs(options *CreateOptions) []string {
 	return args
 }
 
-func update(exe ghutil.Executor, branchID string, prID string) error {
+func update(exe ghutil.Executor, pr PullRequest, prID string, publishChecks bool) error {
 	// Push the current branch to the already existing git remote
 	s := ghutil.StartSpinner("Updating Pull Request #"+prID+"...", "Pull Request #"+prID+" has been updated.")
 	_, err := exe.Command("git", "push")
@@ -176,8 +260,12 @@ func update(exe ghutil.Executor, branchID string, prID string) error {
 	}
 	s.Stop()
 
+	if publishChecks {
+		publishCheckResults(exe, pr)
+	}
+
 	// Fetching this for info
-	stdOut, err := exe.GH("pr", "list", "-H", branchID, "--json", "url", "--jq", ".[].url")
+	stdOut, err := exe.GH("pr", "list", "-H", pr.branchID, "--json", "url", "--jq", ".[].url")
 	if err != nil {
 		return err
 	}
@@ -187,12 +275,38 @@ func update(exe ghutil.Executor, branchID string, prID string) error {
 	return nil
 }
 
+// publishCheckResults records the check results of the pull request as commit statuses on the pushed HEAD. Failing to
+// publish is not fatal, since the branch has already been pushed at this point.
+func publishCheckResults(exe ghutil.Executor, pr PullRequest) {
+	if len(pr.checks) == 0 {
+		return
+	}
+
+	sha, err := exe.Command("git", "rev-parse", "HEAD")
+	if err != nil {
+		logger.Warn("Could not determine HEAD commit, check results were not published: " + err.Error())
+		return
+	}
+	sha = strings.TrimSpace(sha)
+
+	s := ghutil.StartSpinner("Publishing check results...", "Published check results as commit statuses on "+sha)
+	err = check.Publish(exe, sha, pr.checks)
+	if err != nil {
+		ghutil.RemoveFinalMsg(s)
+		s.Stop()
+		logger.Warn("Could not publish check results: " + err.Error())
+		return
+	}
+	s.Stop()
+}
+
 func createPR(
 	exe ghutil.Executor,
 	options *CreateOptions,
 	settings *config.Settings,
 	pr PullRequest,
 	mainID string,
+	client *jira.Client,
 ) (PullRequest, error) {
 	// Get the commit messages between the current branch and the main branch and put them in the PR body.
 	commits, err := branch.GetCommitMessages(exe, mainID, pr.branchID)
@@ -202,13 +316,21 @@ func createPR(
 
 	// Get the title
 	pr.Title = getDefaultTitle(commits)
-	if !options.TestRun {
+	if options.Title != "" {
+		pr.Title = options.Title
+	} else if !options.TestRun && !options.NonInteractive {
 		pr.Title, err = ghutil.AskForString("Title", pr.Title)
 		if err != nil {
 			return pr, err
 		}
 	}
 
+	// Get the issues, from the options or the branch name and commits
+	pr.issues, err = selectIssues(options, client, pr.branchID, commits)
+	if err != nil {
+		return pr, err
+	}
+
 	pr.Body, err = createBody(exe, pr, options, settings, commits)
 	if err != nil {
 		return pr, err
@@ -223,15 +345,7 @@ func createBody(exe ghutil.Executor, pr PullRequest, options *CreateOptions, set
 	body := ""
 
 	// Add a summary of the commits to the PR body
-	commitLines := strings.Split(commits, "\n")
-	commitSummary := ""
-	if len(commitLines) > 1 {
-		var commitSummarySb185 strings.Builder
-		for _, line := range commitLines[1:] {
-			commitSummarySb185.WriteString("* " + line + "\n")
-		}
-		commitSummary += commitSummarySb185.String()
-	}
+	commitSummary := commitSummary(commits)
 
 	bodySurvey := "No description. Do you want to add one?"
 	if body != "" {
@@ -239,7 +353,12 @@ func createBody(exe ghutil.Executor, pr PullRequest, options *CreateOptions, set
 		bodySurvey = "Do you want to change the description?"
 	}
 
-	if !options.TestRun {
+	switch {
+	case options.Body != "":
+		body = "## 📝 Description\n\n" + options.Body + "\n"
+	case options.NonInteractive && commitSummary != "":
+		body = "## 📝 Description\n\n" + managedSection(sectionCommits, commitSummary) + "\n"
+	case !options.TestRun && !options.NonInteractive:
 		var err error
 		body, err = promptForDescription(body, commitSummary, bodySurvey)
 		if err != nil {
@@ -247,18 +366,12 @@ func createBody(exe ghutil.Executor, pr PullRequest, options *CreateOptions, set
 		}
 	}
 
-	issueSection, err := issuesChanges(options, settings)
-	if err != nil {
-		return "", err
-	}
-
-	body = addDocSection(body, issueSection)
+	body = addDocSection(body, issuesChanges(pr, settings))
 
 	// CheckList
 	body = addDocSection(body, "## 📋 Checklist\n")
 
-	body = addDocSection(body, docIsLintedLine(pr, options))
-	body = addDocSection(body, docIsTestedLine(pr, options))
+	body = addDocSection(body, managedSection(sectionChecks, checksChecklist(pr)))
 
 	// New tests checkmark
 	testSection, err := testingChanges(options)
@@ -271,7 +384,7 @@ func createBody(exe ghutil.Executor, pr PullRequest, options *CreateOptions, set
 	if err != nil {
 		return "", err
 	}
-	body = addDocSection(body, docsSection)
+	body = addDocSection(body, managedSection(sectionDocs, docsSection))
 
 	// POSIX - always end with \n
 	// Append a newline to the end of the body if it does not have one
@@ -295,65 +408,26 @@ func promptForDescription(body, commitSummary, bodySurvey string) (string, error
 		return "## 📝 Description\n\n" + editedBody + "\n", nil
 	}
 	if commitSummary != "" {
-		return "## 📝 Description\n\n" + commitSummary, nil
+		return "## 📝 Description\n\n" + managedSection(sectionCommits, commitSummary) + "\n", nil
 	}
 	return body, nil
 }
 
-func docIsTestedLine(pr PullRequest, options *CreateOptions) string {
-	switch {
-	case pr.isTested:
-		return "* ✅ Unit tests passed on local machine."
-	case options.NoUnit:
-		return "* ⛔ **This PR has not been unit tested! The --notest option was used.**"
-	default:
-		return "* ⚠️ **No tests could be run for this PR.**"
-	}
-}
-
-func docIsLintedLine(pr PullRequest, options *CreateOptions) string {
-	switch {
-	case pr.isLinted:
-		return "* ✅ Lint checks passed on local machine."
-	case options.NoLint:
-		return "* ⛔ **This PR has not been linted! The --nolint option was used.**"
-	default:
-		return "* ⛔ **This PR has not been linted! Unspecified lint error!** ⚠️"
-	}
-}
-
-func issuesChanges(options *CreateOptions, settings *config.Settings) (string, error) {
-	// Issue ID(s)
-	// Optionally add the issue ID(s) to the PR body.
-	body := ""
-	var issueIDString string
-	if !options.TestRun && options.Issues == "" {
-		userIssueString, errI := ghutil.AskForString("Issue IDs (separate with commas):", "")
-		if errI != nil {
-			return "", errI
-		}
-		issueIDString = userIssueString
-	} else {
-		issueIDString = options.Issues
+// issuesChanges links the issues of the pull request to Jira.
+func issuesChanges(pr PullRequest, settings *config.Settings) string {
+	if len(pr.issues) == 0 {
+		return ""
 	}
-	if issueIDString != "" {
-		if body != "" {
-			body += "\n"
-		}
 
-		issueIDs := strings.Split(issueIDString, ",")
-		for i, id := range issueIDs {
-			id = strings.TrimSpace(id)
-			issueIDs[i] = fmt.Sprintf("[%s](%s/%s)", id, settings.JiraURL, id)
-		}
-		body += "## 🔗 Issue ID(s): " + strings.Join(issueIDs, ", ") + "\n"
+	issueIDs := make([]string, len(pr.issues))
+	for i, id := range pr.issues {
+		issueIDs[i] = fmt.Sprintf("[%s](%s/%s)", id, settings.JiraURL, id)
 	}
-
-	return body, nil
+	return issueLinePrefix + " " + strings.Join(issueIDs, ", ") + "\n"
 }
 
 func testingChanges(options *CreateOptions) (string, error) {
-	if !options.TestRun {
+	if !options.TestRun && !options.NonInteractive {
 		newTestConfirm, err := ghutil.AskToConfirm("Did you add new tests?")
 		if err != nil {
 			return "", err
@@ -450,6 +524,10 @@ func getNewBranchName(options *CreateOptions) (string, error) {
 		return options.Branch, nil
 	}
 
+	if options.NonInteractive {
+		return "", errors.New("A branch name is required when on the base branch")
+	}
+
 	if !options.TestRun {
 		inputBranchName, err := ghutil.AskForString("You are currently on the base branch. Please specify a temporary branch name: ", "")
 		if err != nil {
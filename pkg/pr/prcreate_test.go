@@ -272,6 +272,9 @@ func TestExecuteCreate(t *testing.T) {
 			mockExe.On("Command", "git", []string{"commit", "-m", "default commit message"}).Return("", nil)
 			mockExe.On("Command", "git", []string{"show-ref", "--verify", "--quiet", "refs/heads/" + tt.currentBranch})
 			mockExe.On("Command", "git", []string{"fetch", "origin", "main"}).Return("", nil)
+			mockExe.On("Command", "git", []string{"log", "--format=", "--raw", "--no-abbrev", "--no-renames", "--diff-filter=AM", "origin/main..HEAD"}).Return("", nil)
+			mockExe.On("Command", "git", []string{"merge-base", "HEAD", "origin/main"}).Return("abc123\n", nil)
+			mockExe.On("Command", "git", []string{"diff", "--unified=0", "--no-color", "--no-ext-diff", "abc123"}).Return("", nil)
 			mockExe.On("CommandContext", mock.Anything, "npx", linterArgs).Return("", tt.expectedLintErr)
 			mockExe.On("Command", "git", []string{"push", "--set-upstream", "origin", tt.currentBranch}).
 				Return(tt.pushBranch, tt.pushBranchErr)
@@ -283,8 +286,12 @@ func TestExecuteCreate(t *testing.T) {
 				Return(tt.prListURL, tt.prListUErr)
 			mockExe.On("GH", []string{"pr", "create", "--title", tt.gitLog, "--body", tt.issueBodySection +
 				"## 📋 Checklist\n\n" +
+				"<!-- dxp:start:checks -->\n" +
 				"* ✅ Lint checks passed on local machine.\n" +
-				"* ⚠️ **No tests could be run for this PR.**\n",
+				"* ⚠️ **No tests could be run for this PR.**\n" +
+				"<!-- dxp:end:checks -->\n" +
+				"<!-- dxp:start:docs -->\n" +
+				"<!-- dxp:end:docs -->\n",
 				"--base", "main", "--label", "Test"}).
 				Return(tt.prCreate, tt.prCreateErr)
 			mockExe.On("GH", []string{"repo", "view", "--json", "defaultBranchRef", "--jq", ".defaultBranchRef.name"}).
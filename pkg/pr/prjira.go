@@ -0,0 +1,159 @@
+package pr
+
+import (
+	"strings"
+
+	"github.com/elhub/gh-dxp/pkg/config"
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/jira"
+	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+// issueLinePrefix starts the line of the PR body that links the issues of the pull request.
+const issueLinePrefix = "## 🔗 Issue ID(s):"
+
+// selectIssues returns the issues of the pull request. The issues in the options are used if there are any.
+// Otherwise, issue keys are detected in the branch name and can be changed when prompted. When prompting with Jira
+// configured, keys in the commit messages are suggested as well; they are never linked without confirmation, since
+// commit messages mention things like UTF-8 or SHA-256. Without Jira, detected keys are only linked if the user
+// confirms them. If Jira is configured, the issues are looked up, and issues that Jira does not know are left out.
+func selectIssues(options *CreateOptions, client *jira.Client, branchID, commits string) ([]string, error) {
+	lookup := issueLookup(client)
+	prompt := !options.TestRun && !options.NonInteractive
+
+	issueIDString := options.Issues
+	if issueIDString == "" {
+		detected := jira.DetectKeys(branchID)
+		if prompt && client != nil {
+			detected = jira.DetectKeys(branchID, commits)
+		}
+
+		if prompt {
+			if len(detected) > 0 {
+				logger.Info("Detected issues:")
+				for _, key := range detected {
+					if description, ok := lookup(key); ok {
+						logger.Info("  " + description)
+					}
+				}
+			}
+
+			userIssueString, err := ghutil.AskForString("Issue IDs (separate with commas):", strings.Join(detected, ", "))
+			if err != nil {
+				return nil, err
+			}
+			issueIDString = userIssueString
+		} else if client != nil {
+			issueIDString = strings.Join(detected, ", ")
+		}
+	}
+
+	var issues []string
+	for _, id := range strings.Split(issueIDString, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if client != nil {
+			description, ok := lookup(id)
+			if !ok {
+				continue
+			}
+			logger.Info("Linking " + description)
+		}
+		issues = append(issues, id)
+	}
+	return issues, nil
+}
+
+// issueLookup returns a function that describes an issue by its key, summary and status, and remembers the issues it
+// has looked up. Without Jira, the issue is described by its key. The function returns false for issues that Jira
+// does not know. If Jira cannot be reached, the issue is described by its key and kept. Problems are reported once.
+func issueLookup(client *jira.Client) func(key string) (string, bool) {
+	type result struct {
+		description string
+		ok          bool
+	}
+	results := map[string]result{}
+
+	return func(key string) (string, bool) {
+		if client == nil {
+			return key, true
+		}
+		if r, found := results[key]; found {
+			return r.description, r.ok
+		}
+
+		var r result
+		issue, err := client.Issue(key)
+		var notFound *jira.IssueNotFoundError
+		switch {
+		case errors.As(err, &notFound):
+			logger.Warn("Leaving out " + key + ": " + err.Error())
+		case err != nil:
+			logger.Warn("Could not look up " + key + ", linking it anyway: " + err.Error())
+			r = result{description: key, ok: true}
+		default:
+			r = result{description: issue.Key + ": " + issue.Summary + " (" + issue.Status + ")", ok: true}
+		}
+		results[key] = r
+		return r.description, r.ok
+	}
+}
+
+// updateIssuesOnCreate moves the issues of a new pull request to the review status and links them to it, as
+// configured. The pull request has already been created, so failures are reported as warnings.
+func updateIssuesOnCreate(client *jira.Client, settings *config.Settings, pr PullRequest, prURL string) {
+	if client == nil {
+		return
+	}
+	for _, key := range pr.issues {
+		if settings.Jira.ReviewTransition != "" {
+			transitionIssue(client, key, settings.Jira.ReviewTransition)
+		}
+		if settings.Jira.RemoteLinks && prURL != "" {
+			if err := client.AddRemoteLink(key, prURL, pr.Title); err != nil {
+				logger.Warn(err.Error())
+				continue
+			}
+			logger.Info("Linked " + key + " to " + prURL)
+		}
+	}
+}
+
+// updateIssuesOnMerge moves the issues linked in the body of a merged pull request to the done status, if configured.
+func updateIssuesOnMerge(settings *config.Settings, body string) {
+	if settings.Jira.DoneTransition == "" {
+		return
+	}
+	client, err := jira.NewClient(settings.Jira, nil)
+	if err != nil {
+		logger.Warn("Not updating the Jira issues: " + err.Error())
+		return
+	}
+	if client == nil {
+		return
+	}
+
+	for _, line := range strings.Split(body, "\n") {
+		if !strings.HasPrefix(line, issueLinePrefix) {
+			continue
+		}
+		for _, key := range jira.DetectKeys(strings.TrimPrefix(line, issueLinePrefix)) {
+			transitionIssue(client, key, settings.Jira.DoneTransition)
+		}
+	}
+}
+
+func transitionIssue(client *jira.Client, key, name string) {
+	moved, err := client.Transition(key, name)
+	switch {
+	case err != nil:
+		logger.Warn(err.Error())
+	case moved:
+		logger.Info("Moved " + key + " to " + name)
+	default:
+		logger.Debug(key + " is already " + name)
+	}
+}
@@ -0,0 +1,200 @@
+package pr_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/elhub/gh-dxp/pkg/config"
+	"github.com/elhub/gh-dxp/pkg/jira"
+	"github.com/elhub/gh-dxp/pkg/pr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jiraStandIn serves TDX-1 in "To Do" and TDX-2 in "Done", and records the requests it receives. If unavailable is
+// set, every request fails.
+type jiraStandIn struct {
+	unavailable bool
+	requests    []string
+}
+
+func newJiraStandIn(t *testing.T, unavailable bool) (*jiraStandIn, *httptest.Server) {
+	t.Setenv("JIRA_API_URL", "")
+	t.Setenv("JIRA_USER", "")
+	t.Setenv("JIRA_API_TOKEN", "")
+
+	fake := &jiraStandIn{unavailable: unavailable}
+	statuses := map[string]string{"TDX-1": "To Do", "TDX-2": "Done"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		fake.requests = append(fake.requests, strings.TrimSpace(r.Method+" "+r.URL.Path+" "+string(body)))
+		if fake.unavailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/"), "/")
+		status, ok := statuses[parts[0]]
+		switch {
+		case !ok:
+			w.WriteHeader(http.StatusNotFound)
+		case len(parts) == 1:
+			_, _ = w.Write([]byte(`{"key": "` + parts[0] + `", "fields": {"summary": "Fix login", "status": {"name": "` +
+				status + `"}}}`))
+		case parts[1] == "transitions" && r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"transitions": [{"id": "21", "name": "Start review", "to": {"name": "In Review"}},
+				{"id": "31", "name": "Done", "to": {"name": "Done"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func TestSelectIssues(t *testing.T) {
+	tests := []struct {
+		name        string
+		withJira    bool
+		unavailable bool
+		issues      string
+		branchID    string
+		commits     string
+		expected    []string
+	}{
+		{
+			name:     "uses the issues in the options without Jira",
+			issues:   "TDX-123, EDIEL-456",
+			expected: []string{"TDX-123", "EDIEL-456"},
+		},
+		{
+			name:     "leaves out issues in the options that Jira does not know",
+			withJira: true,
+			issues:   "TDX-1, TDX-404",
+			expected: []string{"TDX-1"},
+		},
+		{
+			name:     "links validated issues from the branch name, but not from the commits",
+			withJira: true,
+			branchID: "feature/TDX-1-login",
+			commits:  "TDX-2 Add login page\nRead the file as UTF-8",
+			expected: []string{"TDX-1"},
+		},
+		{
+			name:     "does not link detected issues that cannot be validated",
+			branchID: "feature/TDX-1-login",
+			commits:  "TDX-2 Add login page",
+			expected: nil,
+		},
+		{
+			name:        "keeps the issues if Jira is unavailable",
+			withJira:    true,
+			unavailable: true,
+			issues:      "TDX-1,TDX-404",
+			expected:    []string{"TDX-1", "TDX-404"},
+		},
+		{
+			name:        "keeps the issues from the branch name if Jira is unavailable",
+			withJira:    true,
+			unavailable: true,
+			branchID:    "feature/TDX-404-login",
+			commits:     "Hash the files with SHA-256",
+			expected:    []string{"TDX-404"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var client *jira.Client
+			if tt.withJira {
+				_, server := newJiraStandIn(t, tt.unavailable)
+				var err error
+				client, err = jira.NewClient(config.Jira{APIURL: server.URL}, nil)
+				require.NoError(t, err)
+			}
+
+			options := &pr.CreateOptions{NonInteractive: true, Issues: tt.issues}
+			issues, err := pr.SelectIssues(options, client, tt.branchID, tt.commits)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, issues)
+		})
+	}
+}
+
+func TestUpdateIssuesOnCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings config.Jira
+		expected []string
+	}{
+		{
+			name:     "moves the issues to review and links the pull request",
+			settings: config.Jira{ReviewTransition: "In Review", RemoteLinks: true},
+			expected: []string{
+				"GET /rest/api/2/issue/TDX-1",
+				"GET /rest/api/2/issue/TDX-1/transitions",
+				`POST /rest/api/2/issue/TDX-1/transitions {"transition":{"id":"21"}}`,
+				`POST /rest/api/2/issue/TDX-1/remotelink {"globalId":"https://github.com/elhub/web/pull/3",` +
+					`"object":{"url":"https://github.com/elhub/web/pull/3","title":"Add login page"}}`,
+			},
+		},
+		{
+			name:     "does nothing unless configured",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, server := newJiraStandIn(t, false)
+			client, err := jira.NewClient(config.Jira{APIURL: server.URL}, nil)
+			require.NoError(t, err)
+
+			pr.UpdateIssuesOnCreate(client, &config.Settings{Jira: tt.settings}, "Add login page",
+				"https://github.com/elhub/web/pull/3", "TDX-1")
+
+			assert.Equal(t, tt.expected, fake.requests)
+		})
+	}
+}
+
+func TestUpdateIssuesOnMerge(t *testing.T) {
+	body := "## 📝 Summary\nFixes TDX-3 and reads UTF-8\n\n" +
+		"## 🔗 Issue ID(s): [TDX-1](https://jira-mock/browse/TDX-1), [TDX-2](https://jira-mock/browse/TDX-2)\n"
+
+	tests := []struct {
+		name           string
+		doneTransition string
+		expected       []string
+	}{
+		{
+			name:           "moves the linked issues to done",
+			doneTransition: "Done",
+			expected: []string{
+				"GET /rest/api/2/issue/TDX-1",
+				"GET /rest/api/2/issue/TDX-1/transitions",
+				`POST /rest/api/2/issue/TDX-1/transitions {"transition":{"id":"31"}}`,
+				"GET /rest/api/2/issue/TDX-2",
+			},
+		},
+		{
+			name:     "does nothing unless configured",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, server := newJiraStandIn(t, false)
+			settings := &config.Settings{Jira: config.Jira{APIURL: server.URL, DoneTransition: tt.doneTransition}}
+
+			pr.UpdateIssuesOnMerge(settings, body)
+
+			assert.Equal(t, tt.expected, fake.requests)
+		})
+	}
+}
@@ -3,126 +3,183 @@ package pr
 
 import (
 	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
 	"sort"
 	"strconv"
-	"sync"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/cli/go-gh/v2/pkg/term"
 	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/output"
 	"github.com/pkg/errors"
 )
 
-// ExecuteList renders the user's assigned pull requests.
+// ExecuteList renders the user's assigned pull requests as an interactive dashboard, as a table with NoTUI, or as
+// JSON when output fields are given.
 func ExecuteList(exe ghutil.Executor, options *ListOptions) error {
-	var wg sync.WaitGroup
-	prChan := make(chan PullRequestInfo)
-	errChan := make(chan error)
-
-	if options.Mine {
-		if err := retrievePullRequests("--author=@me", exe, prChan, errChan, &wg); err != nil {
-			return err
-		}
-	}
-
-	if options.ReviewRequested {
-		if err := retrievePullRequests("--review-requested=@me", exe, prChan, errChan, &wg); err != nil {
-			return err
-		}
+	if err := options.Output.Validate(ListFields()); err != nil {
+		return err
 	}
-
-	go func() {
-		wg.Wait()
-		close(prChan)
-		close(errChan)
-	}()
-
-	pullRequests, err := drainChannels(prChan, errChan)
+	columns, err := selectColumns(options.Columns)
 	if err != nil {
 		return err
 	}
-	sortPullRequests(pullRequests)
 
-	// Check content of pullRequests
-	// Pretty Print myPullRequests as a Table
-	if !options.TestRun {
-		p := tea.NewProgram(initialModel(pullRequests))
-		if _, err := p.Run(); err != nil {
-			return err
-		}
+	pullRequests, err := fetchPullRequests(exe, options)
+	if err != nil {
+		return err
 	}
 
-	return nil
-}
-
-func retrievePullRequests(searchTerm string, exe ghutil.Executor, prChan chan<- PullRequestInfo, errChan chan<- error, wg *sync.WaitGroup) error {
-	res, err := exe.GH("search", "prs", searchTerm, "--state=open", "--json", "number,repository")
-	if err != nil {
-		return errors.Wrap(err, "failed to search prs for my pull requests")
+	if options.TestRun {
+		return nil
 	}
 
-	var searchResults []searchResult
-	err = json.Unmarshal([]byte(res), &searchResults)
-	if err != nil {
-		return errors.Wrap(err, "failed to unmarshal search results for my pull requests")
+	if options.Output.Enabled() {
+		if pullRequests == nil {
+			pullRequests = []PullRequestInfo{}
+		}
+		return output.Write(os.Stdout, pullRequests, &options.Output)
 	}
 
-	// Fetching the details of each PR is slow, so we do this in parallel
-	for _, sr := range searchResults {
-		wg.Add(1)
-		go fetchPullRequestDetails(exe, sr, prChan, errChan, wg)
+	ui := initialModel(pullRequests, columns)
+	if options.NoTUI {
+		// Fit the table to the terminal, if the output is not piped
+		if width, _, err := term.FromEnv().Size(); err == nil {
+			ui.fitColumns(width)
+		}
+		_, err := lipgloss.Fprintln(os.Stdout, ui.tableView())
+		return err
 	}
 
-	return nil
+	// Errors are shown in the status line of the dashboard, so the commands must not log over it
+	quiet := ghutil.QuietExecutor(exe)
+	ui = ui.interactive(quiet, func() ([]PullRequestInfo, error) {
+		return fetchPullRequests(quiet, options)
+	}, options.RefreshInterval)
+	p := tea.NewProgram(ui)
+	_, err = p.Run()
+	return err
 }
 
-func fetchPullRequestDetails(exe ghutil.Executor, sr searchResult, prChan chan<- PullRequestInfo, errChan chan<- error, wg *sync.WaitGroup) {
-	defer wg.Done()
-	url := "https://github.com/" + sr.Repository.NameWithOwner + "/pull/" + strconv.Itoa(sr.Number)
-	pullRequestDetails, err := exe.GH("pr", "view", url, "--json", "additions,author,createdAt,deletions,headRepository,number,title,reviewDecision")
+// ListFields returns the fields available for the JSON output of pr list.
+func ListFields() []string {
+	return output.Fields(PullRequestInfo{})
+}
 
+// fetchPullRequests retrieves the open pull requests in the views selected by the options, sorted by repository and
+// number. The searches of the views are combined in a single GraphQL query, which returns up to 100 pull requests per
+// search and page. A pull request found by several views is only listed once.
+func fetchPullRequests(exe ghutil.Executor, options *ListOptions) ([]PullRequestInfo, error) {
+	views, err := listViews(exe, options, time.Now())
 	if err != nil {
-		errChan <- errors.Wrap(err, "failed to get pr details")
-		return
+		return nil, err
 	}
 
-	var pullRequest PullRequestInfo
-	err = json.Unmarshal([]byte(pullRequestDetails), &pullRequest)
-	if err != nil {
-		errChan <- errors.Wrap(err, "failed to unmarshal pull request details")
-		return
-	}
+	var pullRequests []PullRequestInfo
+	// The index of each pull request by URL
+	seen := map[string]int{}
 
-	prChan <- pullRequest
-}
+	for slices.ContainsFunc(views, func(view *listView) bool { return !view.done }) {
+		args := []string{"api", "graphql", "-f", "query=" + pullRequestsQuery(views)}
+		for i, view := range views {
+			if view.done {
+				continue
+			}
+			args = append(args, "-f", fmt.Sprintf("q%d=%s", i, view.query))
+			if view.after != "" {
+				args = append(args, "-f", fmt.Sprintf("after%d=%s", i, view.after))
+			}
+		}
+
+		res, err := exe.GH(args...)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to search for my pull requests")
+		}
 
-func drainChannels(prChan <-chan PullRequestInfo, errChan <-chan error) ([]PullRequestInfo, error) {
-	var result []PullRequestInfo
-	var firstErr error
-	var openPR, openErr = true, true
-	for openPR || openErr {
-		select {
-		case pr, ok := <-prChan:
-			if !ok {
-				openPR = false
-			} else {
-				result = append(result, pr)
+		var response pullRequestsResponse
+		if err := json.Unmarshal([]byte(res), &response); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal the pull requests")
+		}
+
+		for i, view := range views {
+			if view.done {
+				continue
 			}
-		case err, ok := <-errChan:
-			if !ok {
-				openErr = false
-			} else if firstErr == nil {
-				firstErr = err
+			result := response.Data["s"+strconv.Itoa(i)]
+			if result == nil {
+				view.done = true
+				continue
 			}
+
+			for _, node := range result.Nodes {
+				// Search results that are not pull requests are skipped
+				if node.URL == "" || (view.keep != nil && !view.keep(node)) {
+					continue
+				}
+				j, ok := seen[node.URL]
+				if !ok {
+					j = len(pullRequests)
+					seen[node.URL] = j
+					pullRequests = append(pullRequests, node.toPullRequestInfo())
+				}
+				pullRequests[j].Views = append(pullRequests[j].Views, view.name)
+			}
+
+			view.done = !result.PageInfo.HasNextPage
+			view.after = result.PageInfo.EndCursor
 		}
 	}
-	return result, firstErr
+
+	sortPullRequests(pullRequests)
+
+	return pullRequests, nil
+}
+
+func (n pullRequestNode) toPullRequestInfo() PullRequestInfo {
+	pr := PullRequestInfo{
+		Additions: n.Additions,
+		Author: prAuthor{
+			ID:    n.Author.ID,
+			IsBot: n.Author.Typename == "Bot",
+			Login: n.Author.Login,
+			Name:  n.Author.Name,
+		},
+		CreatedAt:      n.CreatedAt,
+		Deletions:      n.Deletions,
+		IsDraft:        n.IsDraft,
+		Labels:         []string{},
+		Mergeable:      n.Mergeable,
+		Number:         n.Number,
+		ReviewDecision: n.ReviewDecision,
+		Title:          n.Title,
+		URL:            n.URL,
+		Repository:     n.Repository.NameWithOwner,
+	}
+	if n.HeadRepository != nil {
+		pr.HeadRepository = *n.HeadRepository
+	}
+	for _, label := range n.Labels.Nodes {
+		pr.Labels = append(pr.Labels, label.Name)
+	}
+	// Only the user's own review requests count, not those of their teams
+	for _, request := range n.ReviewRequests.Nodes {
+		pr.ReviewRequested = pr.ReviewRequested || request.RequestedReviewer.IsViewer
+	}
+	if len(n.Commits.Nodes) > 0 && n.Commits.Nodes[0].Commit.StatusCheckRollup != nil {
+		pr.ChecksState = n.Commits.Nodes[0].Commit.StatusCheckRollup.State
+	}
+	return pr
 }
 
 func sortPullRequests(pullRequests []PullRequestInfo) {
 	sort.Slice(pullRequests, func(i, j int) bool {
-		if pullRequests[i].HeadRepository.ID == pullRequests[j].HeadRepository.ID {
+		if pullRequests[i].Repository == pullRequests[j].Repository {
 			return pullRequests[i].Number < pullRequests[j].Number
 		}
-		return pullRequests[i].HeadRepository.Name < pullRequests[j].HeadRepository.Name
+		return pullRequests[i].Repository < pullRequests[j].Repository
 	})
 }
@@ -0,0 +1,222 @@
+package pr
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/table"
+	"charm.land/lipgloss/v2"
+	"github.com/pkg/errors"
+)
+
+// DefaultListColumns are the columns of pr list when none are configured in .devxp.
+var DefaultListColumns = []string{ //nolint: gochecknoglobals // Default configuration.
+	"repository", "number", "title", "author", "review", "changes", "checks", "draft", "mergeable", "waiting", "age",
+}
+
+// Colours used for the status columns.
+const (
+	colorGreen  = "2"
+	colorRed    = "1"
+	colorYellow = "3"
+	colorGrey   = "240"
+)
+
+// listColumn is a column of the pull request list. Columns with a minWidth below their maxWidth are shrunk to fit the
+// terminal, the others always get the width of their widest cell.
+type listColumn struct {
+	title    string
+	minWidth int
+	maxWidth int
+	value    func(pr PullRequestInfo, now time.Time) (text string, color string)
+}
+
+// listColumns returns the definition of each column that can be configured in .devxp.
+func listColumns() map[string]listColumn {
+	return map[string]listColumn{
+		"repository": {title: "Repository", minWidth: 8, maxWidth: 24, value: repositoryColumn},
+		"number":     {title: "ID", minWidth: 4, maxWidth: 4, value: numberColumn},
+		"title":      {title: "Title", minWidth: 15, maxWidth: 60, value: titleColumn},
+		"author":     {title: "Author", minWidth: 8, maxWidth: 20, value: authorColumn},
+		"review":     {title: "Review", minWidth: 6, maxWidth: 17, value: reviewColumn},
+		"changes":    {title: "Changes", minWidth: 7, maxWidth: 7, value: changesColumn},
+		"checks":     {title: "Checks", minWidth: 9, maxWidth: 9, value: checksColumn},
+		"draft":      {title: "Draft", minWidth: 5, maxWidth: 5, value: draftColumn},
+		"mergeable":  {title: "Mergeable", minWidth: 9, maxWidth: 9, value: mergeableColumn},
+		"waiting":    {title: "Waiting", minWidth: 7, maxWidth: 7, value: waitingColumn},
+		"age":        {title: "Age", minWidth: 4, maxWidth: 4, value: ageColumn},
+		"labels":     {title: "Labels", minWidth: 6, maxWidth: 30, value: labelsColumn},
+	}
+}
+
+// selectColumns returns the named columns in the given order, or the default columns if no names are given.
+func selectColumns(names []string) ([]listColumn, error) {
+	if len(names) == 0 {
+		names = DefaultListColumns
+	}
+
+	available := listColumns()
+	columns := make([]listColumn, 0, len(names))
+	for _, name := range names {
+		column, ok := available[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, errors.Errorf("unknown column %q in prList.columns, use one of: %s",
+				name, strings.Join(ListColumns(), ", "))
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// ListColumns returns the names of the columns that can be configured for pr list.
+func ListColumns() []string {
+	return slices.Sorted(maps.Keys(listColumns()))
+}
+
+// tableColumns sizes the columns to fit the width, or to their widest cell if the width is unknown (zero). The widest
+// of the flexible columns is shrunk first, until the table fits or all of them have their minimum width.
+func tableColumns(columns []listColumn, rows []table.Row, width int) []table.Column {
+	widths := make([]int, len(columns))
+	total := 0
+	for i, column := range columns {
+		widths[i] = lipgloss.Width(column.title)
+		for _, row := range rows {
+			widths[i] = max(widths[i], lipgloss.Width(row[i]))
+		}
+		widths[i] = min(max(widths[i], column.minWidth), column.maxWidth)
+		// The table pads each cell with a space on both sides
+		total += widths[i] + 2
+	}
+
+	for width > 0 && total > width {
+		widest := -1
+		for i, column := range columns {
+			if widths[i] > column.minWidth && (widest < 0 || widths[i] > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+
+	cols := make([]table.Column, len(columns))
+	for i, column := range columns {
+		cols[i] = table.Column{Title: column.title, Width: widths[i]}
+	}
+	return cols
+}
+
+func colored(text, color string) string {
+	if color == "" || text == "" {
+		return text
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(text)
+}
+
+func repositoryColumn(pr PullRequestInfo, _ time.Time) (string, string) {
+	if pr.HeadRepository.Name != "" {
+		return pr.HeadRepository.Name, ""
+	}
+	// The head repository of a pull request from a deleted fork is unknown
+	_, name, _ := strings.Cut(pr.Repository, "/")
+	return name, ""
+}
+
+func numberColumn(pr PullRequestInfo, _ time.Time) (string, string) {
+	return "#" + strconv.Itoa(pr.Number), ""
+}
+
+func titleColumn(pr PullRequestInfo, _ time.Time) (string, string) {
+	return pr.Title, ""
+}
+
+func authorColumn(pr PullRequestInfo, _ time.Time) (string, string) {
+	if pr.Author.Name == "" {
+		return pr.Author.Login, ""
+	}
+	return pr.Author.Name, ""
+}
+
+func reviewColumn(pr PullRequestInfo, _ time.Time) (string, string) {
+	switch pr.ReviewDecision {
+	case "APPROVED":
+		return pr.ReviewDecision, colorGreen
+	case "CHANGES_REQUESTED":
+		return pr.ReviewDecision, colorRed
+	case "REVIEW_REQUIRED":
+		return pr.ReviewDecision, colorYellow
+	}
+	return pr.ReviewDecision, ""
+}
+
+func changesColumn(pr PullRequestInfo, _ time.Time) (string, string) {
+	return "+" + strconv.Itoa(pr.Additions) + " -" + strconv.Itoa(pr.Deletions), ""
+}
+
+func checksColumn(pr PullRequestInfo, _ time.Time) (string, string) {
+	switch pr.ChecksState {
+	case "SUCCESS":
+		return "✓ passing", colorGreen
+	case "FAILURE", "ERROR":
+		return "✗ failing", colorRed
+	case "PENDING", "EXPECTED":
+		return "● pending", colorYellow
+	}
+	return "", ""
+}
+
+func draftColumn(pr PullRequestInfo, _ time.Time) (string, string) {
+	if pr.IsDraft {
+		return "draft", colorGrey
+	}
+	return "", ""
+}
+
+func mergeableColumn(pr PullRequestInfo, _ time.Time) (string, string) {
+	switch pr.Mergeable {
+	case "MERGEABLE":
+		return "yes", colorGreen
+	case "CONFLICTING":
+		return "conflicts", colorRed
+	}
+	return "", ""
+}
+
+func waitingColumn(pr PullRequestInfo, _ time.Time) (string, string) {
+	if pr.ReviewRequested {
+		return "you", colorYellow
+	}
+	return "", ""
+}
+
+func ageColumn(pr PullRequestInfo, now time.Time) (string, string) {
+	createdAt, err := time.Parse(time.RFC3339, pr.CreatedAt)
+	if err != nil {
+		return "", ""
+	}
+	return formatAge(now.Sub(createdAt)), ""
+}
+
+func labelsColumn(pr PullRequestInfo, _ time.Time) (string, string) {
+	return strings.Join(pr.Labels, ", "), ""
+}
+
+// formatAge formats the age of a pull request in the largest whole unit, for example 3d.
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", max(int(age.Minutes()), 0))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	case age < 365*24*time.Hour:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+	return fmt.Sprintf("%dy", int(age.Hours()/(365*24)))
+}
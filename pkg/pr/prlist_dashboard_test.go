@@ -0,0 +1,182 @@
+package pr_test
+
+import (
+	"errors"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/elhub/gh-dxp/pkg/pr"
+	"github.com/elhub/gh-dxp/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dashboardPRs() []pr.PullRequestInfo {
+	return []pr.PullRequestInfo{
+		{
+			Number:         1,
+			Title:          "Add login page",
+			Author:         pr.PrAuthor{Login: "alice", Name: "Alice"},
+			HeadRepository: pr.PrRepository{Name: "web"},
+			Repository:     "elhub/web",
+			ReviewDecision: "APPROVED",
+			URL:            "https://github.com/elhub/web/pull/1",
+		},
+		{
+			Number:         2,
+			Title:          "Fix flaky test",
+			Author:         pr.PrAuthor{Login: "bob", Name: "Bob"},
+			HeadRepository: pr.PrRepository{Name: "api"},
+			Repository:     "elhub/api",
+			ReviewDecision: "CHANGES_REQUESTED",
+			URL:            "https://github.com/elhub/api/pull/2",
+		},
+	}
+}
+
+func key(s string) tea.KeyPressMsg {
+	switch s {
+	case "enter":
+		return tea.KeyPressMsg{Code: tea.KeyEnter}
+	case "esc":
+		return tea.KeyPressMsg{Code: tea.KeyEscape}
+	case "down":
+		return tea.KeyPressMsg{Code: tea.KeyDown}
+	}
+	return tea.KeyPressMsg{Code: rune(s[0]), Text: s}
+}
+
+// press sends the keys to the model one by one, typing every rune of keys longer than one character.
+func press(t *testing.T, model tea.Model, keys ...string) (tea.Model, tea.Cmd) {
+	t.Helper()
+	var cmd tea.Cmd
+	for _, k := range keys {
+		if len(k) > 1 && k != "enter" && k != "esc" && k != "down" {
+			for _, r := range k {
+				model, cmd = model.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+			}
+			continue
+		}
+		model, cmd = model.Update(key(k))
+	}
+	return model, cmd
+}
+
+func TestFilterPullRequests(t *testing.T) {
+	tests := []struct {
+		filter   string
+		expected []int
+	}{
+		{filter: "", expected: []int{1, 2}},
+		{filter: "repo:api", expected: []int{2}},
+		{filter: "repo:elhub/web", expected: []int{1}},
+		{filter: "author:alice", expected: []int{1}},
+		{filter: "author:Bob", expected: []int{2}},
+		{filter: "status:changes_requested", expected: []int{2}},
+		{filter: "status:approved flaky", expected: nil},
+		{filter: "login", expected: []int{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			var numbers []int
+			for _, p := range pr.FilterPullRequests(dashboardPRs(), tt.filter) {
+				numbers = append(numbers, p.Number)
+			}
+			assert.Equal(t, tt.expected, numbers)
+		})
+	}
+}
+
+func TestDashboardFilter(t *testing.T) {
+	model := pr.InitialModel(dashboardPRs()).Interactive(new(testutils.MockExecutor), nil)
+
+	updated, _ := press(t, model, "/", "author:bob", "enter")
+
+	ui := updated.(pr.PullRequestUI)
+	require.Len(t, ui.Visible(), 1)
+	assert.Equal(t, 2, ui.Visible()[0].Number)
+	assert.Contains(t, ui.View().Content, "filter: author:bob")
+
+	updated, _ = press(t, ui, "esc")
+	assert.Len(t, updated.(pr.PullRequestUI).Visible(), 2)
+}
+
+func TestDashboardActions(t *testing.T) {
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("GH", []string{"pr", "review", "https://github.com/elhub/api/pull/2", "--approve"}).Return("", nil)
+	mockExe.On("GH", []string{"pr", "review", "https://github.com/elhub/web/pull/1", "--request-changes", "--body",
+		"please add tests"}).Return("", nil)
+	mockExe.On("GH", []string{"pr", "view", "https://github.com/elhub/web/pull/1", "--web"}).Return("", nil)
+	mockExe.On("Command", "gh", []string{"pr", "diff", "https://github.com/elhub/web/pull/1", "--color", "never"}).
+		Return("+added line\n", nil)
+	mockExe.On("Command", "gh", []string{"pr", "checks", "https://github.com/elhub/web/pull/1"}).
+		Return("build  fail  1m\n", errors.New("exit status 1"))
+	mockExe.On("GH", []string{"repo", "view", "--json", "nameWithOwner", "--jq", ".nameWithOwner"}).Return("elhub/web\n", nil)
+	mockExe.On("GH", []string{"pr", "checkout", "1"}).Return("", nil)
+
+	refreshed := 0
+	fetch := func() ([]pr.PullRequestInfo, error) {
+		refreshed++
+		return dashboardPRs()[:1], nil
+	}
+	var model tea.Model = pr.InitialModel(dashboardPRs()).Interactive(mockExe, fetch)
+
+	// Approving reports the result and reloads the pull requests
+	model, cmd := press(t, model, "down", "a")
+	model, cmd = model.Update(cmd())
+	assert.Equal(t, "Approved #2", model.(pr.PullRequestUI).Status())
+	model, _ = model.Update(cmd())
+	assert.Equal(t, 1, refreshed)
+	assert.Len(t, model.(pr.PullRequestUI).Visible(), 1)
+
+	// Requesting changes asks for a comment
+	model, cmd = press(t, model, "x", "please add tests", "enter")
+	model, _ = model.Update(cmd())
+	assert.Equal(t, "Requested changes on #1", model.(pr.PullRequestUI).Status())
+
+	model, cmd = press(t, model, "enter")
+	model, _ = model.Update(cmd())
+	assert.Equal(t, "Opened #1 in the browser", model.(pr.PullRequestUI).Status())
+
+	model, cmd = press(t, model, "c")
+	model, _ = model.Update(cmd())
+	assert.Equal(t, "Checked out #1", model.(pr.PullRequestUI).Status())
+
+	// The diff and the checks are shown in the detail view, even if the command fails
+	model, cmd = press(t, model, "d")
+	model, _ = model.Update(cmd())
+	assert.Contains(t, model.View().Content, "+added line")
+
+	model, cmd = press(t, model, "esc", "s")
+	model, _ = model.Update(cmd())
+	assert.Contains(t, model.View().Content, "build  fail")
+
+	mockExe.AssertExpectations(t)
+
+	_, cmd = press(t, model, "esc", "q")
+	assert.IsType(t, tea.QuitMsg{}, cmd())
+}
+
+func TestDashboardCheckoutInOtherRepository(t *testing.T) {
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("GH", []string{"repo", "view", "--json", "nameWithOwner", "--jq", ".nameWithOwner"}).Return("elhub/other\n", nil)
+
+	model, cmd := press(t, pr.InitialModel(dashboardPRs()).Interactive(mockExe, nil), "c")
+	model, _ = model.Update(cmd())
+
+	assert.Equal(t, "Checkout failed: run pr list in a clone of elhub/web to check out #1", model.(pr.PullRequestUI).Status())
+	mockExe.AssertNotCalled(t, "GH", []string{"pr", "checkout", "1"})
+}
+
+func TestDashboardShowsCommandErrors(t *testing.T) {
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("GH", []string{"pr", "review", "https://github.com/elhub/web/pull/1", "--approve"}).
+		Return("failed to create review: Can not approve your own pull request\n", errors.New("exit status 1"))
+
+	model, cmd := press(t, pr.InitialModel(dashboardPRs()).Interactive(mockExe, nil), "a")
+	model, _ = model.Update(cmd())
+
+	assert.Equal(t, "Approved #1 failed: failed to create review: Can not approve your own pull request",
+		model.(pr.PullRequestUI).Status())
+}
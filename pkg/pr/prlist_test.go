@@ -1,79 +1,286 @@
 package pr_test
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/elhub/gh-dxp/pkg/output"
 	"github.com/elhub/gh-dxp/pkg/pr"
 	"github.com/elhub/gh-dxp/pkg/testutils"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
-func TestExecuteList(t *testing.T) {
-	prDetails := "{\"additions\":1,\"author\":{\"id\":\"U_kgDOCXKgZ\",\"is_bot\":false," +
-		"\"login\":\"my-user-statnett\",\"name\":\"Stat Nett\"},\"createdAt\":" +
-		"\"2024-08-13T08:23:12Z\",\"deletions\":1,\"headRepository\":{\"id\":\"R_kgDOMCGoz\",\"name\"" +
-		":\"gh-xyz\"},\"number\":1,\"reviewDecision\":\"\",\"title\":\"the title of this here pr\"}"
+const (
+	userAuthor = `{"__typename":"User","id":"U_1","login":"my-user","name":"My User"}`
+	// requestedFromMe is the review request of a pull request waiting for the user's review.
+	requestedFromMe = `,"reviewRequests":{"nodes":[{"requestedReviewer":{}},{"requestedReviewer":{"isViewer":true}}]}`
+)
+
+// prNode returns a pull request in the GraphQL search response. The extra fields override the defaults.
+func prNode(repository string, number int, author string, extra string) string {
+	return fmt.Sprintf(`{"number":%[2]d,"title":"PR %[2]d","url":"https://github.com/%[1]s/pull/%[2]d",`+
+		`"createdAt":"2024-08-13T08:23:12Z","additions":1,"deletions":2,"reviewDecision":"APPROVED",`+
+		`"isDraft":false,"mergeable":"MERGEABLE","author":%[3]s,`+
+		`"repository":{"nameWithOwner":"%[1]s"},"headRepository":{"id":"R_%[1]s","name":"%[1]s"},`+
+		`"labels":{"nodes":[]},"commits":{"nodes":[{"commit":{"statusCheckRollup":{"state":"SUCCESS"}}}]}%[4]s}`,
+		repository, number, author, extra)
+}
+
+func searchPage(hasNextPage bool, cursor string, nodes ...string) string {
+	return fmt.Sprintf(`{"pageInfo":{"hasNextPage":%t,"endCursor":%q},"nodes":[%s]}`,
+		hasNextPage, cursor, strings.Join(nodes, ","))
+}
+
+// searchResponse returns the GraphQL response with the page of each search alias.
+func searchResponse(pages map[string]string) string {
+	var fields []string
+	for alias, page := range pages {
+		fields = append(fields, fmt.Sprintf("%q:%s", alias, page))
+	}
+	return `{"data":{` + strings.Join(fields, ",") + `}}`
+}
+
+// searchArgs matches the GraphQL search with the given variables, for example q0=is:pr or after0=cursor. The query
+// must include exactly the searches of the qN variables.
+func searchArgs(variables ...string) interface{} {
+	return mock.MatchedBy(func(args []string) bool {
+		if len(args) < 4 || args[0] != "api" || args[1] != "graphql" || !strings.HasPrefix(args[3], "query=") {
+			return false
+		}
+		var expected []string
+		searches := 0
+		for _, variable := range variables {
+			expected = append(expected, "-f", variable)
+			if name, _, _ := strings.Cut(variable, "="); strings.HasPrefix(name, "q") {
+				searches++
+				if !strings.Contains(args[3], "s"+name[1:]+": search(query: $"+name) {
+					return false
+				}
+			}
+		}
+		return strings.Count(args[3], ": search(") == searches && assert.ObjectsAreEqual(expected, args[4:])
+	})
+}
 
+const (
+	mineQuery            = "is:pr is:open author:@me"
+	reviewRequestedQuery = "is:pr is:open review-requested:@me"
+)
+
+func TestExecuteList(t *testing.T) {
 	tests := []struct {
-		name                   string
-		expectedErr            error
-		authorSearchResponse   string
-		prDetailResponse       string
-		reviewerSearchResponse string
+		name            string
+		mine            bool
+		reviewRequested bool
+		variables       []string
+		response        string
+		responseErr     error
+		expectedErr     error
 	}{
 		{
-			name:                   "User is author of a PR",
-			authorSearchResponse:   "[{\"number\":1,\"repository\":{\"name\":\"gh-xyz\",\"nameWithOwner\":\"elhub/gh-xyz\"}}]",
-			reviewerSearchResponse: "[]",
-			prDetailResponse:       prDetails,
+			name:            "User is author of a PR",
+			mine:            true,
+			reviewRequested: true,
+			variables:       []string{"q0=" + mineQuery, "q1=" + reviewRequestedQuery},
+			response: searchResponse(map[string]string{
+				"s0": searchPage(false, "", prNode("elhub/gh-xyz", 1, userAuthor, "")),
+				"s1": searchPage(false, ""),
+			}),
 		},
 		{
-			name:                   "User has one review requested",
-			authorSearchResponse:   "[]",
-			reviewerSearchResponse: "[{\"number\":1,\"repository\":{\"name\":\"gh-xyz\",\"nameWithOwner\":\"elhub/gh-xyz\"}}]",
-			prDetailResponse:       prDetails,
+			name:            "User has one review requested",
+			reviewRequested: true,
+			variables:       []string{"q0=" + reviewRequestedQuery},
+			response: searchResponse(map[string]string{
+				"s0": searchPage(false, "", prNode("elhub/gh-xyz", 1, userAuthor, "")),
+			}),
 		},
 		{
-			name:                   "There are no PR's assigned to the user",
-			reviewerSearchResponse: "[]",
-			authorSearchResponse:   "[]",
+			name:            "There are no PR's assigned to the user",
+			mine:            true,
+			reviewRequested: true,
+			variables:       []string{"q0=" + mineQuery, "q1=" + reviewRequestedQuery},
+			response:        searchResponse(map[string]string{"s0": searchPage(false, ""), "s1": searchPage(false, "")}),
 		},
 		{
-			name:                 "Author search fails",
-			authorSearchResponse: "[]",
-			expectedErr:          errors.New("something went wrong during author search"),
+			name:            "Search fails",
+			mine:            true,
+			reviewRequested: true,
+			variables:       []string{"q0=" + mineQuery, "q1=" + reviewRequestedQuery},
+			responseErr:     errors.New("something went wrong during the search"),
+			expectedErr:     errors.New("failed to search for my pull requests: something went wrong during the search"),
+		},
+		{
+			name:      "Invalid response",
+			mine:      true,
+			variables: []string{"q0=" + mineQuery},
+			response:  "not json",
+			expectedErr: errors.New(
+				"failed to unmarshal the pull requests: invalid character 'o' in literal null (expecting 'u')"),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockExe := new(testutils.MockExecutor)
+			mockExe.On("GH", searchArgs(tt.variables...)).Return(tt.response, tt.responseErr).Once()
 
-			mockExe.On("GH", []string{"search", "prs", "--author=@me", "--state=open", "--json", "number,repository"}).Return(
-				tt.authorSearchResponse, tt.expectedErr,
-			)
-			mockExe.On("GH", []string{"search", "prs", "--review-requested=@me", "--state=open", "--json", "number,repository"}).Return(
-				tt.reviewerSearchResponse, tt.expectedErr,
-			)
-
-			mockExe.On(
-				"GH",
-				[]string{"pr", "view", "https://github.com/elhub/gh-xyz/pull/1",
-					"--json", "additions,author,createdAt,deletions,headRepository,number,title,reviewDecision"},
-			).Return(tt.prDetailResponse, tt.expectedErr)
-
-			opts := &pr.ListOptions{TestRun: true, Mine: true, ReviewRequested: true}
+			opts := &pr.ListOptions{TestRun: true, Mine: tt.mine, ReviewRequested: tt.reviewRequested}
 
 			err := pr.ExecuteList(mockExe, opts)
 
 			if tt.expectedErr != nil {
 				require.Error(t, err)
-				assert.True(t, errors.As(err, &tt.expectedErr))
+				assert.Equal(t, tt.expectedErr.Error(), err.Error())
 			} else {
 				require.NoError(t, err)
 			}
+			mockExe.AssertExpectations(t)
 		})
 	}
 }
+
+func TestFetchPullRequests(t *testing.T) {
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("GH", searchArgs("q0="+mineQuery, "q1="+reviewRequestedQuery)).Return(searchResponse(map[string]string{
+		"s0": searchPage(true, "A1", prNode("elhub/web", 2, userAuthor, `,"isDraft":true`+requestedFromMe)),
+		"s1": searchPage(false, "", prNode("elhub/api", 7, userAuthor, requestedFromMe),
+			prNode("elhub/web", 2, userAuthor, requestedFromMe)),
+	}), nil).Once()
+	mockExe.On("GH", searchArgs("q0="+mineQuery, "after0=A1")).Return(searchResponse(map[string]string{
+		"s0": searchPage(false, "", prNode("elhub/web", 1, `{"__typename":"Bot","id":"B_1","login":"renovate"}`,
+			`,"headRepository":null,"labels":{"nodes":[{"name":"Build"}]},`+
+				`"commits":{"nodes":[{"commit":{"statusCheckRollup":null}}]}`)),
+	}), nil).Once()
+
+	pullRequests, err := pr.FetchPullRequests(mockExe, &pr.ListOptions{Mine: true, ReviewRequested: true})
+
+	require.NoError(t, err)
+	mockExe.AssertExpectations(t)
+	require.Len(t, pullRequests, 3)
+
+	assert.Equal(t, "elhub/api", pullRequests[0].Repository)
+	assert.Equal(t, 7, pullRequests[0].Number)
+	assert.Equal(t, "SUCCESS", pullRequests[0].ChecksState)
+	assert.Equal(t, "MERGEABLE", pullRequests[0].Mergeable)
+	assert.Equal(t, pr.PrAuthor{ID: "U_1", Login: "my-user", Name: "My User"}, pullRequests[0].Author)
+	assert.Equal(t, pr.PrRepository{ID: "R_elhub/api", Name: "elhub/api"}, pullRequests[0].HeadRepository)
+	assert.Empty(t, pullRequests[0].Labels)
+	assert.True(t, pullRequests[0].ReviewRequested)
+
+	// The pull request listed by both searches is only included once.
+	assert.Equal(t, 2, pullRequests[2].Number)
+	assert.True(t, pullRequests[2].IsDraft)
+	assert.True(t, pullRequests[2].ReviewRequested)
+	assert.Equal(t, []string{"mine", "review-requested"}, pullRequests[2].Views)
+
+	bot := pullRequests[1]
+	assert.Equal(t, 1, bot.Number)
+	assert.Equal(t, pr.PrAuthor{ID: "B_1", IsBot: true, Login: "renovate"}, bot.Author)
+	assert.Equal(t, pr.PrRepository{}, bot.HeadRepository)
+	assert.Equal(t, []string{"Build"}, bot.Labels)
+	assert.Empty(t, bot.ChecksState)
+	assert.False(t, bot.ReviewRequested)
+}
+
+func TestFetchPullRequestViews(t *testing.T) {
+	reviewed := func(reviewedOid, headOid string) string {
+		return fmt.Sprintf(`,"headRefOid":%q,"viewerLatestReview":{"commit":{"oid":%q}}`, headOid, reviewedOid)
+	}
+	stale := time.Now().AddDate(0, 0, -30).Format(time.DateOnly)
+
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("GH", []string{"api", "--paginate", "user/teams", "--jq", `.[] | .organization.login + "/" + .slug`}).
+		Return("elhub/devxp\nelhub/platform\n", nil).Once()
+	mockExe.On("GH", searchArgs(
+		"q0=is:pr is:open team-review-requested:elhub/devxp",
+		"q1=is:pr is:open team-review-requested:elhub/platform",
+		"q2=is:pr is:open org:elhub",
+		"q3=is:pr is:open repo:other/tool",
+		"q4=is:pr is:open reviewed-by:@me -author:@me",
+		"q5=is:pr is:open involves:@me created:<"+stale,
+	)).Return(searchResponse(map[string]string{
+		"s0": searchPage(false, "", prNode("elhub/web", 1, userAuthor, "")),
+		"s1": searchPage(false, ""),
+		"s2": searchPage(false, "", prNode("elhub/web", 1, userAuthor, ""),
+			prNode("elhub/web", 3, userAuthor, requestedFromMe)),
+		"s3": searchPage(false, "", prNode("other/tool", 4, userAuthor, "")),
+		"s4": searchPage(false, "",
+			prNode("elhub/web", 5, userAuthor, reviewed("abc", "def")),
+			prNode("elhub/web", 6, userAuthor, reviewed("abc", "abc"))),
+		"s5": searchPage(false, "", prNode("elhub/web", 3, userAuthor, "")),
+	}), nil).Once()
+
+	pullRequests, err := pr.FetchPullRequests(mockExe, &pr.ListOptions{
+		TeamReview:         true,
+		Orgs:               []string{"elhub"},
+		Repos:              []string{"other/tool"},
+		ReviewedNewCommits: true,
+		StaleDays:          30,
+	})
+
+	require.NoError(t, err)
+	mockExe.AssertExpectations(t)
+
+	views := map[int][]string{}
+	for _, pullRequest := range pullRequests {
+		views[pullRequest.Number] = pullRequest.Views
+	}
+	assert.Equal(t, map[int][]string{
+		1: {"team:elhub/devxp", "org:elhub"},
+		3: {"org:elhub", "stale"},
+		4: {"repo:other/tool"},
+		5: {"reviewed"},
+	}, views)
+	// The review request is found without the review-requested view
+	for _, pullRequest := range pullRequests {
+		assert.Equal(t, pullRequest.Number == 3, pullRequest.ReviewRequested, "#%d", pullRequest.Number)
+	}
+}
+
+func TestFetchPullRequestViewErrors(t *testing.T) {
+	t.Run("Invalid repository", func(t *testing.T) {
+		_, err := pr.FetchPullRequests(new(testutils.MockExecutor), &pr.ListOptions{Repos: []string{"tool"}})
+
+		require.Error(t, err)
+		assert.Equal(t, `invalid repository "tool", use the format <owner>/<repo>`, err.Error())
+	})
+
+	t.Run("Teams cannot be listed", func(t *testing.T) {
+		mockExe := new(testutils.MockExecutor)
+		mockExe.On("GH", []string{"api", "--paginate", "user/teams", "--jq", `.[] | .organization.login + "/" + .slug`}).
+			Return("", errors.New("missing read:org scope"))
+
+		_, err := pr.FetchPullRequests(mockExe, &pr.ListOptions{TeamReview: true})
+
+		require.Error(t, err)
+		assert.Equal(t, "failed to list your teams: missing read:org scope", err.Error())
+	})
+}
+
+func TestExecuteListValidatesJSONFields(t *testing.T) {
+	mockExe := new(testutils.MockExecutor)
+	opts := &pr.ListOptions{
+		Mine:   true,
+		Output: output.Options{Fields: []string{"number", "milestone"}},
+	}
+
+	err := pr.ExecuteList(mockExe, opts)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Unknown JSON field: \"milestone\"")
+	assert.Contains(t, err.Error(), "  repository\n")
+	mockExe.AssertNotCalled(t, "GH", mock.Anything)
+}
+
+func TestListFields(t *testing.T) {
+	assert.Equal(t, []string{
+		"additions", "author", "checksState", "createdAt", "deletions", "headRepository",
+		"isDraft", "labels", "mergeable", "number", "repository", "reviewDecision", "reviewRequested", "title", "url", "views",
+	}, pr.ListFields())
+}
@@ -2,10 +2,38 @@
 package pr
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"charm.land/bubbles/v2/table"
+	"charm.land/bubbles/v2/textinput"
+	"charm.land/bubbles/v2/viewport"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
-	"strconv"
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/pkg/errors"
+)
+
+const dashboardHelp = "↑/↓ navigate • enter open • c checkout • d diff • s checks • a approve • x request changes • " +
+	"/ filter • esc clear filter • r refresh • q quit"
+
+// The messages the dashboard receives when loading pull requests and running actions in the background.
+type (
+	pullRequestsLoadedMsg struct {
+		pullRequests []PullRequestInfo
+		err          error
+	}
+	refreshTickMsg struct{}
+	actionDoneMsg  struct {
+		status string
+		err    error
+	}
+	detailLoadedMsg struct {
+		name    string
+		content string
+	}
 )
 
 func baseStyle() lipgloss.Style {
@@ -14,31 +42,14 @@ func baseStyle() lipgloss.Style {
 		BorderForeground(lipgloss.Color("240"))
 }
 
-func initialModel(pullRequests []PullRequestInfo) PullRequestUI {
-	rows := []table.Row{}
-	var repoNameLen = 12
-	var titleLen = 40
-	var changesLen = 8
-	cols := []table.Column{
-		{Title: "Repository", Width: repoNameLen},
-		{Title: "ID", Width: 4},
-		{Title: "Title", Width: titleLen},
-		{Title: "Author", Width: 20},
-		{Title: "Status", Width: 16},
-		{Title: "Changes", Width: changesLen},
-	}
-	for _, pr := range pullRequests {
-		changes := "+" + strconv.Itoa(pr.Additions) + " -" + strconv.Itoa(pr.Deletions)
-		rows = append(rows, table.Row{
-			pr.HeadRepository.Name,
-			"#" + strconv.Itoa(pr.Number),
-			pr.Title,
-			pr.Author.Name,
-			pr.ReviewDecision,
-			changes,
-		})
+// initialModel shows the pull requests in the given columns, or in the default columns if none are given.
+func initialModel(pullRequests []PullRequestInfo, columns []listColumn) PullRequestUI {
+	if len(columns) == 0 {
+		columns, _ = selectColumns(nil)
 	}
 
+	rows := tableRows(columns, pullRequests)
+	cols := tableColumns(columns, rows, 0)
 	totalWidth := 0
 	for _, col := range cols {
 		totalWidth += col.Width + 2
@@ -67,21 +78,378 @@ func initialModel(pullRequests []PullRequestInfo) PullRequestUI {
 
 	t.SetStyles(s)
 	return PullRequestUI{
-		table: t,
+		table:        t,
+		columns:      columns,
+		pullRequests: pullRequests,
+		visible:      pullRequests,
+		input:        textinput.New(),
+		detail:       viewport.New(viewport.WithWidth(totalWidth), viewport.WithHeight(20)),
 	}
 }
 
+func tableRows(columns []listColumn, pullRequests []PullRequestInfo) []table.Row {
+	now := time.Now()
+	rows := []table.Row{}
+	for _, pr := range pullRequests {
+		row := make(table.Row, len(columns))
+		for i, column := range columns {
+			row[i] = colored(column.value(pr, now))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// fitColumns sizes the columns to the width of the terminal, shrinking the flexible columns if the table is too wide.
+func (ui *PullRequestUI) fitColumns(width int) {
+	// Leave room for the border around the table
+	cols := tableColumns(ui.columns, ui.table.Rows(), max(width-2, 0))
+	totalWidth := 0
+	for _, col := range cols {
+		totalWidth += col.Width + 2
+	}
+	ui.table.SetColumns(cols)
+	ui.table.SetWidth(totalWidth)
+}
+
+// interactive turns the static table into a dashboard that can act on the pull requests and reload them.
+func (ui PullRequestUI) interactive(
+	exe ghutil.Executor, fetch func() ([]PullRequestInfo, error), refreshInterval time.Duration,
+) PullRequestUI {
+	ui.exe = exe
+	ui.fetch = fetch
+	ui.refreshInterval = refreshInterval
+	ui.refreshedAt = time.Now()
+	return ui
+}
+
 // Init is the initial command for the Bubble Tea program.
 func (ui PullRequestUI) Init() tea.Cmd {
-	return nil
+	return ui.scheduleRefresh()
+}
+
+// Update handles messages and updates the model accordingly. The static table quits on the first key press.
+func (ui PullRequestUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		ui.width, ui.height = msg.Width, msg.Height
+		ui.fitColumns(ui.width)
+		ui.resize()
+		return ui, nil
+	case pullRequestsLoadedMsg:
+		if msg.err != nil {
+			ui.status = "Refresh failed: " + msg.err.Error()
+		} else {
+			ui.pullRequests = msg.pullRequests
+			ui.refreshedAt = time.Now()
+			ui.applyFilter()
+		}
+		return ui, nil
+	case refreshTickMsg:
+		return ui, tea.Batch(ui.load(), ui.scheduleRefresh())
+	case actionDoneMsg:
+		if msg.err != nil {
+			ui.status = msg.status + " failed: " + msg.err.Error()
+			return ui, nil
+		}
+		ui.status = msg.status
+		return ui, ui.load()
+	case detailLoadedMsg:
+		ui.showDetail = true
+		ui.detailName = msg.name
+		ui.detail.SetContent(msg.content)
+		ui.detail.GotoTop()
+		ui.resize()
+		return ui, nil
+	case tea.KeyPressMsg:
+		if ui.exe == nil {
+			return ui, tea.Quit
+		}
+		return ui.handleKey(msg)
+	}
+
+	return ui, nil
+}
+
+func (ui PullRequestUI) handleKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key == "ctrl+c" {
+		return ui, tea.Quit
+	}
+
+	if ui.inputMode != inputNone {
+		return ui.handleInputKey(msg)
+	}
+
+	if ui.showDetail {
+		switch key {
+		case "esc", "q":
+			ui.showDetail = false
+			return ui, nil
+		}
+		var cmd tea.Cmd
+		ui.detail, cmd = ui.detail.Update(msg)
+		return ui, cmd
+	}
+
+	pr, selected := ui.selected()
+	switch key {
+	case "q":
+		return ui, tea.Quit
+	case "esc":
+		ui.filter = ""
+		ui.applyFilter()
+		return ui, nil
+	case "/":
+		ui.inputMode = inputFilter
+		ui.input.Prompt = "Filter: "
+		ui.input.SetValue(ui.filter)
+		return ui, ui.input.Focus()
+	case "r":
+		ui.status = "Refreshing..."
+		return ui, ui.load()
+	}
+
+	if !selected {
+		var cmd tea.Cmd
+		ui.table, cmd = ui.table.Update(msg)
+		return ui, cmd
+	}
+
+	switch key {
+	case "enter", "o":
+		return ui, ui.run("Opened #"+strconv.Itoa(pr.Number)+" in the browser", "pr", "view", pr.URL, "--web")
+	case "c":
+		return ui, ui.checkout(pr)
+	case "d":
+		return ui, ui.showOutput("Diff of #"+strconv.Itoa(pr.Number), "pr", "diff", pr.URL, "--color", "never")
+	case "s":
+		return ui, ui.showOutput("Checks of #"+strconv.Itoa(pr.Number), "pr", "checks", pr.URL)
+	case "a":
+		return ui, ui.run("Approved #"+strconv.Itoa(pr.Number), "pr", "review", pr.URL, "--approve")
+	case "x":
+		ui.inputMode = inputRequestChanges
+		ui.input.Prompt = "Changes requested on #" + strconv.Itoa(pr.Number) + ": "
+		ui.input.SetValue("")
+		return ui, ui.input.Focus()
+	}
+
+	var cmd tea.Cmd
+	ui.table, cmd = ui.table.Update(msg)
+	return ui, cmd
+}
+
+func (ui PullRequestUI) handleInputKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		ui.inputMode = inputNone
+		ui.input.Blur()
+		return ui, nil
+	case "enter":
+		mode := ui.inputMode
+		value := strings.TrimSpace(ui.input.Value())
+		ui.inputMode = inputNone
+		ui.input.Blur()
+
+		if mode == inputFilter {
+			ui.filter = value
+			ui.applyFilter()
+			return ui, nil
+		}
+		pr, ok := ui.selected()
+		if !ok || value == "" {
+			ui.status = "A comment is required to request changes"
+			return ui, nil
+		}
+		return ui, ui.run("Requested changes on #"+strconv.Itoa(pr.Number),
+			"pr", "review", pr.URL, "--request-changes", "--body", value)
+	}
+
+	var cmd tea.Cmd
+	ui.input, cmd = ui.input.Update(msg)
+	return ui, cmd
+}
+
+// selected returns the pull request under the cursor.
+func (ui PullRequestUI) selected() (PullRequestInfo, bool) {
+	cursor := ui.table.Cursor()
+	if cursor < 0 || cursor >= len(ui.visible) {
+		return PullRequestInfo{}, false
+	}
+	return ui.visible[cursor], true
+}
+
+// applyFilter shows the pull requests matching the filter and keeps the cursor within the rows.
+func (ui *PullRequestUI) applyFilter() {
+	ui.visible = filterPullRequests(ui.pullRequests, ui.filter)
+	ui.table.SetRows(tableRows(ui.columns, ui.visible))
+	ui.fitColumns(ui.width)
+	if ui.table.Cursor() >= len(ui.visible) {
+		ui.table.SetCursor(max(len(ui.visible)-1, 0))
+	}
+	ui.resize()
+}
+
+// filterPullRequests returns the pull requests matching all terms of the filter. The terms repo:, author: and
+// status: match the repository, the author's login or name and the review decision; other terms match the title.
+func filterPullRequests(pullRequests []PullRequestInfo, filter string) []PullRequestInfo {
+	terms := strings.Fields(strings.ToLower(filter))
+	if len(terms) == 0 {
+		return pullRequests
+	}
+
+	var matching []PullRequestInfo
+	for _, pr := range pullRequests {
+		if matchesAllTerms(pr, terms) {
+			matching = append(matching, pr)
+		}
+	}
+	return matching
+}
+
+func matchesAllTerms(pr PullRequestInfo, terms []string) bool {
+	for _, term := range terms {
+		field, value, found := strings.Cut(term, ":")
+		var candidates []string
+		switch {
+		case found && field == "repo":
+			candidates = []string{pr.HeadRepository.Name, pr.Repository}
+		case found && field == "author":
+			candidates = []string{pr.Author.Login, pr.Author.Name}
+		case found && field == "status":
+			candidates = []string{strings.ReplaceAll(pr.ReviewDecision, "_", " "), pr.ReviewDecision}
+			value = strings.ReplaceAll(value, "_", " ")
+		default:
+			candidates = []string{pr.Title}
+			value = term
+		}
+
+		matched := false
+		for _, candidate := range candidates {
+			if strings.Contains(strings.ToLower(candidate), value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// resize fits the table and the detail view into the window.
+func (ui *PullRequestUI) resize() {
+	if ui.height == 0 {
+		return
+	}
+	// Leave room for the header, the borders, the input and status lines and the help
+	available := max(ui.height-8, 3)
+	ui.table.SetHeight(min(len(ui.visible)+1, available))
+	ui.detail.SetWidth(ui.width)
+	ui.detail.SetHeight(available)
 }
 
-// Update handles messages and updates the model accordingly.
-func (ui PullRequestUI) Update(_ tea.Msg) (tea.Model, tea.Cmd) {
-	return ui, tea.Quit
+// load reloads the pull requests in the background.
+func (ui PullRequestUI) load() tea.Cmd {
+	if ui.fetch == nil {
+		return nil
+	}
+	fetch := ui.fetch
+	return func() tea.Msg {
+		pullRequests, err := fetch()
+		return pullRequestsLoadedMsg{pullRequests: pullRequests, err: err}
+	}
+}
+
+func (ui PullRequestUI) scheduleRefresh() tea.Cmd {
+	if ui.fetch == nil || ui.refreshInterval <= 0 {
+		return nil
+	}
+	return tea.Tick(ui.refreshInterval, func(time.Time) tea.Msg { return refreshTickMsg{} })
+}
+
+// run runs a gh command in the background and reports the status when it is done.
+func (ui PullRequestUI) run(status string, args ...string) tea.Cmd {
+	exe := ui.exe
+	return func() tea.Msg {
+		out, err := exe.GH(args...)
+		return actionDoneMsg{status: status, err: commandError(out, err)}
+	}
+}
+
+// commandError returns the error output of a failed gh command as the error, since the dashboard does not log it.
+func commandError(out string, err error) error {
+	if err == nil || strings.TrimSpace(out) == "" {
+		return err
+	}
+	return errors.New(strings.Join(strings.Fields(out), " "))
+}
+
+// showOutput runs a gh command in the background and shows its output in the detail view. Commands like gh pr checks
+// fail when a check fails, so the output is shown regardless of the exit code. The command is run through Command,
+// since GH does not return the standard output of a failed command.
+func (ui PullRequestUI) showOutput(name string, args ...string) tea.Cmd {
+	exe := ui.exe
+	return func() tea.Msg {
+		out, err := exe.Command("gh", args...)
+		if err != nil && strings.TrimSpace(out) == "" {
+			out = err.Error()
+		}
+		return detailLoadedMsg{name: name, content: out}
+	}
+}
+
+// checkout checks out the pull request if the current directory is a clone of its repository.
+func (ui PullRequestUI) checkout(pr PullRequestInfo) tea.Cmd {
+	exe := ui.exe
+	return func() tea.Msg {
+		status := "Checked out #" + strconv.Itoa(pr.Number)
+		current, err := exe.GH("repo", "view", "--json", "nameWithOwner", "--jq", ".nameWithOwner")
+		if err != nil {
+			return actionDoneMsg{status: "Checkout", err: commandError(current, err)}
+		}
+		if strings.TrimSpace(current) != pr.Repository {
+			return actionDoneMsg{status: "Checkout", err: fmt.Errorf("run pr list in a clone of %s to check out #%d",
+				pr.Repository, pr.Number)}
+		}
+		out, err := exe.GH("pr", "checkout", strconv.Itoa(pr.Number))
+		return actionDoneMsg{status: status, err: commandError(out, err)}
+	}
+}
+
+func (ui PullRequestUI) tableView() string {
+	return baseStyle().Render(ui.table.View())
 }
 
 // View renders the UI.
 func (ui PullRequestUI) View() tea.View {
-	return tea.NewView(baseStyle().Render(ui.table.View()) + "\n  ")
+	if ui.exe == nil {
+		return tea.NewView(ui.tableView() + "\n  ")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Pull requests: %d of %d", len(ui.visible), len(ui.pullRequests))
+	if ui.filter != "" {
+		fmt.Fprintf(&b, " • filter: %s", ui.filter)
+	}
+	fmt.Fprintf(&b, " • refreshed %s\n", ui.refreshedAt.Format("15:04:05"))
+
+	if ui.showDetail {
+		b.WriteString(ui.detailName + " (esc to close)\n")
+		b.WriteString(ui.detail.View() + "\n")
+	} else {
+		b.WriteString(ui.tableView() + "\n")
+	}
+
+	if ui.inputMode != inputNone {
+		b.WriteString(ui.input.View() + "\n")
+	} else {
+		b.WriteString(ui.status + "\n")
+	}
+	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(dashboardHelp))
+
+	v := tea.NewView(b.String())
+	v.AltScreen = true
+	return v
 }
@@ -3,9 +3,13 @@ package pr_test
 import (
 	"strings"
 	"testing"
+	"time"
 
+	"charm.land/bubbles/v2/table"
 	tea "charm.land/bubbletea/v2"
 	"github.com/elhub/gh-dxp/pkg/pr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestInitialModel(t *testing.T) {
@@ -184,3 +188,80 @@ func TestChangesFormatting(t *testing.T) {
 		}
 	}
 }
+
+func TestInitialModelWithColumns(t *testing.T) {
+	testPRs := []pr.PullRequestInfo{
+		{
+			Number:          7,
+			Title:           "Bump dependencies",
+			Author:          pr.PrAuthor{Login: "renovate", IsBot: true},
+			Repository:      "elhub/web",
+			CreatedAt:       time.Now().Add(-75 * time.Hour).Format(time.RFC3339),
+			ChecksState:     "FAILURE",
+			IsDraft:         true,
+			Labels:          []string{"Build", "dependencies"},
+			Mergeable:       "CONFLICTING",
+			ReviewRequested: true,
+		},
+	}
+
+	model, err := pr.InitialModelWithColumns(testPRs,
+		[]string{"waiting", "repository", "author", "age", "checks", "draft", "mergeable", "labels"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []table.Row{
+		{"you", "web", "renovate", "3d", "✗ failing", "draft", "conflicts", "Build, dependencies"},
+	}, model.Rows())
+	view := model.View().Content
+	assert.Contains(t, view, "Waiting")
+	assert.NotContains(t, view, "Title")
+}
+
+func TestInitialModelWithUnknownColumn(t *testing.T) {
+	_, err := pr.InitialModelWithColumns(nil, []string{"title", "milestone"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown column "milestone" in prList.columns, use one of: age, author,`)
+}
+
+func TestFitColumns(t *testing.T) {
+	testPRs := []pr.PullRequestInfo{
+		{
+			Number:         1,
+			Title:          "A rather long title that describes the changes of this pull request in detail",
+			Author:         pr.PrAuthor{Name: "User"},
+			HeadRepository: pr.PrRepository{Name: "a-repository-with-a-long-name"},
+		},
+	}
+
+	model, err := pr.InitialModelWithColumns(testPRs, []string{"repository", "number", "title"})
+	require.NoError(t, err)
+
+	// The flexible columns get their widest cell, up to their maximum width
+	assert.Equal(t, []int{24, 4, 60}, model.ColumnWidths())
+
+	// The widest column is shrunk first, and the border and cell padding are taken into account
+	model.FitColumns(60)
+	assert.Equal(t, []int{24, 4, 24}, model.ColumnWidths())
+
+	// Columns are not shrunk below their minimum width
+	model.FitColumns(20)
+	assert.Equal(t, []int{8, 4, 15}, model.ColumnWidths())
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		age      time.Duration
+		expected string
+	}{
+		{-time.Minute, "0m"},
+		{45 * time.Minute, "45m"},
+		{5 * time.Hour, "5h"},
+		{75 * time.Hour, "3d"},
+		{800 * 24 * time.Hour, "2y"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, pr.FormatAge(tt.age))
+	}
+}
@@ -0,0 +1,100 @@
+package pr
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+const (
+	viewMine            = "mine"
+	viewReviewRequested = "review-requested"
+	viewReviewed        = "reviewed"
+	viewStale           = "stale"
+)
+
+// listViews returns the searches for the views selected by the options.
+func listViews(exe ghutil.Executor, options *ListOptions, now time.Time) ([]*listView, error) {
+	var views []*listView
+
+	if options.Mine {
+		views = append(views, &listView{name: viewMine, query: "is:pr is:open author:@me"})
+	}
+	if options.ReviewRequested {
+		views = append(views, &listView{name: viewReviewRequested, query: "is:pr is:open review-requested:@me"})
+	}
+
+	if options.TeamReview {
+		teams, err := retrieveTeams(exe)
+		if err != nil {
+			return nil, err
+		}
+		if len(teams) == 0 {
+			logger.Warn("You are not a member of any team, so there are no team review requests to list")
+		}
+		for _, team := range teams {
+			views = append(views, &listView{
+				name:  "team:" + team,
+				query: "is:pr is:open team-review-requested:" + team,
+			})
+		}
+	}
+
+	for _, org := range options.Orgs {
+		views = append(views, &listView{name: "org:" + org, query: "is:pr is:open org:" + org})
+	}
+	for _, repo := range options.Repos {
+		if owner, name, found := strings.Cut(repo, "/"); !found || owner == "" || name == "" {
+			return nil, errors.Errorf("invalid repository %q, use the format <owner>/<repo>", repo)
+		}
+		views = append(views, &listView{name: "repo:" + repo, query: "is:pr is:open repo:" + repo})
+	}
+
+	if options.ReviewedNewCommits {
+		views = append(views, &listView{
+			name:  viewReviewed,
+			query: "is:pr is:open reviewed-by:@me -author:@me",
+			keep:  hasCommitsAfterReview,
+		})
+	}
+
+	if options.StaleDays > 0 {
+		before := now.AddDate(0, 0, -options.StaleDays).Format(time.DateOnly)
+		views = append(views, &listView{name: viewStale, query: "is:pr is:open involves:@me created:<" + before})
+	}
+
+	return views, nil
+}
+
+// retrieveTeams lists the teams of the current user as <org>/<team>.
+func retrieveTeams(exe ghutil.Executor) ([]string, error) {
+	res, err := exe.GH("api", "--paginate", "user/teams", "--jq", `.[] | .organization.login + "/" + .slug`)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list your teams")
+	}
+	return strings.Fields(res), nil
+}
+
+// hasCommitsAfterReview returns true if the head of the pull request is not the commit the user last reviewed.
+func hasCommitsAfterReview(node pullRequestNode) bool {
+	review := node.ViewerLatestReview
+	return review != nil && review.Commit != nil && review.Commit.Oid != node.HeadRefOid
+}
+
+// pullRequestsQuery combines the searches of the views into one query, with the alias s<i> for the i-th view.
+func pullRequestsQuery(views []*listView) string {
+	var params, searches []string
+	for i, view := range views {
+		if view.done {
+			continue
+		}
+		params = append(params, fmt.Sprintf("$q%[1]d: String!, $after%[1]d: String", i))
+		searches = append(searches,
+			fmt.Sprintf("  s%[1]d: search(query: $q%[1]d, type: ISSUE, first: 100, after: $after%[1]d) { ...pullRequests }", i))
+	}
+	return "query(" + strings.Join(params, ", ") + ") {\n" + strings.Join(searches, "\n") + "\n}\n" + pullRequestFields
+}
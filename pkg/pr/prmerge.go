@@ -6,12 +6,13 @@ import (
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/elhub/gh-dxp/pkg/config"
 	"github.com/elhub/gh-dxp/pkg/ghutil"
 	"github.com/elhub/gh-dxp/pkg/logger"
 )
 
-// ExecuteMerge merges a pull request on the current branch.
-func ExecuteMerge(exe ghutil.Executor, options *MergeOptions) error {
+// ExecuteMerge merges a pull request on the current branch, and moves its Jira issues to done if configured.
+func ExecuteMerge(exe ghutil.Executor, settings *config.Settings, options *MergeOptions) error {
 	// Get branchID
 	currentBranch, errBranch := exe.Command("git", "branch", "--show-current")
 	if errBranch != nil {
@@ -64,5 +65,7 @@ func ExecuteMerge(exe ghutil.Executor, options *MergeOptions) error {
 	logger.Info("Deleted local " + branchID + " and switched to branch main")
 	logger.Info("Deleted remote branch " + branchID)
 
+	updateIssuesOnMerge(settings, prBody)
+
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/elhub/gh-dxp/pkg/config"
 	"github.com/elhub/gh-dxp/pkg/pr"
 	"github.com/elhub/gh-dxp/pkg/testutils"
 	"github.com/stretchr/testify/assert"
@@ -74,7 +75,7 @@ func TestExecuteMerge(t *testing.T) {
 			mockExe.On("GH", []string{"pr", "view", "--json", "body", "--jq", ".body"}).Return(tt.prBody, tt.prBodyErr)
 			mockExe.On("GH", []string{"pr", "merge", "--squash", "--delete-branch", "--subject", tt.prTitle, "--body", tt.prBody}).Return(tt.prMerge, tt.prMergeErr)
 
-			err := pr.ExecuteMerge(mockExe, &pr.MergeOptions{
+			err := pr.ExecuteMerge(mockExe, &config.Settings{}, &pr.MergeOptions{
 				AutoConfirm: true,
 			})
 
@@ -87,3 +88,22 @@ func TestExecuteMerge(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteMergeMovesIssuesToDone(t *testing.T) {
+	fake, server := newJiraStandIn(t, false)
+	body := "## 🔗 Issue ID(s): [TDX-1](https://jira-mock/browse/TDX-1)"
+
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("Command", "git", []string{"branch", "--show-current"}).Return("branch1\n", nil)
+	mockExe.On("GH", []string{"pr", "list", "-H", "branch1", "--json", "number", "--jq", ".[].number"}).Return("3", nil)
+	mockExe.On("GH", []string{"pr", "view", "--json", "title", "--jq", ".title"}).Return("PR title", nil)
+	mockExe.On("GH", []string{"pr", "view", "--json", "body", "--jq", ".body"}).Return(body, nil)
+	mockExe.On("GH", []string{"pr", "merge", "--squash", "--delete-branch", "--subject", "PR title", "--body", body}).
+		Return("pull request merged", nil)
+
+	settings := &config.Settings{Jira: config.Jira{APIURL: server.URL, DoneTransition: "Done"}}
+	err := pr.ExecuteMerge(mockExe, settings, &pr.MergeOptions{AutoConfirm: true})
+
+	require.NoError(t, err)
+	assert.Contains(t, fake.requests, `POST /rest/api/2/issue/TDX-1/transitions {"transition":{"id":"31"}}`)
+}
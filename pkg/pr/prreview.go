@@ -0,0 +1,284 @@
+package pr
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+const pendingReviewQuery = `query($id: ID!) {
+  node(id: $id) { ... on PullRequest { reviews(states: PENDING, first: 1) { nodes { id } } } }
+}`
+
+const addReviewMutation = `mutation($pullRequestId: ID!, $commitOID: GitObjectID) {
+  addPullRequestReview(input: {pullRequestId: $pullRequestId, commitOID: $commitOID}) { pullRequestReview { id } }
+}`
+
+const addReviewThreadMutation = `mutation(
+  $reviewId: ID!, $path: String!, $line: Int!, $side: DiffSide!, $body: String!
+) {
+  addPullRequestReviewThread(input: {
+    pullRequestReviewId: $reviewId, path: $path, line: $line, side: $side, body: $body
+  }) { thread { id } }
+}`
+
+const submitReviewMutation = `mutation($reviewId: ID!, $event: PullRequestReviewEvent!, $body: String) {
+  submitPullRequestReview(input: {pullRequestReviewId: $reviewId, event: $event, body: $body}) {
+    pullRequestReview { url }
+  }
+}`
+
+// ExecuteReview reviews a pull request, given by its number or URL, or the pull request of the current branch. With
+// one of the approve, comment or request changes options, the review is submitted right away. Otherwise, the diff is
+// shown file by file, and the line comments added to it are submitted together in a single review.
+func ExecuteReview(exe ghutil.Executor, pullRequest string, options *ReviewOptions) error {
+	event, err := reviewEvent(options)
+	if err != nil {
+		return err
+	}
+	if event != "" {
+		if err := validateReview(event, options.Body, nil); err != nil {
+			return err
+		}
+	}
+
+	target, err := resolveReviewTarget(exe, pullRequest)
+	if err != nil {
+		return err
+	}
+	prID := "#" + strconv.Itoa(target.Number)
+
+	if event != "" {
+		return submitReview(exe, target, event, options.Body, nil)
+	}
+
+	diff, err := exe.GH("pr", "diff", target.URL, "--color", "never")
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch the diff of pull request "+prID)
+	}
+	files := parseDiff(diff)
+	if len(files) == 0 {
+		return errors.New("pull request " + prID + " has no changes to review")
+	}
+
+	if options.TestRun {
+		return nil
+	}
+
+	model, err := tea.NewProgram(initialReviewModel(target, files)).Run()
+	if err != nil {
+		return err
+	}
+	ui, ok := model.(ReviewUI)
+	if !ok {
+		return errors.New("unexpected review model")
+	}
+
+	if ui.event == "" {
+		if len(ui.comments) == 0 {
+			logger.Info("Quit the review of " + prID + " without submitting it")
+			return nil
+		}
+		// Keep the comments on GitHub, so they are not lost
+		if err := submitReview(exe, target, "", "", ui.comments); err != nil {
+			return err
+		}
+		logger.Infof("Saved %d comments on %s in a pending review, submit it with gh dxp pr review or on GitHub",
+			len(ui.comments), prID)
+		return nil
+	}
+	return submitReview(exe, target, ui.event, ui.body, ui.comments)
+}
+
+// reviewEvent returns the event selected by the options, or an empty string if the diff should be reviewed first.
+func reviewEvent(options *ReviewOptions) (string, error) {
+	var events []string
+	if options.Approve {
+		events = append(events, reviewEventApprove)
+	}
+	if options.Comment {
+		events = append(events, reviewEventComment)
+	}
+	if options.RequestChanges {
+		events = append(events, reviewEventRequestChanges)
+	}
+
+	switch len(events) {
+	case 0:
+		if options.Body != "" {
+			return "", errors.New("use --body with --approve, --comment or --request-changes")
+		}
+		return "", nil
+	case 1:
+		return events[0], nil
+	}
+	return "", errors.New("only one of --approve, --comment or --request-changes may be used")
+}
+
+// validateReview checks that the review has the body or comments that GitHub requires for the event.
+func validateReview(event, body string, comments []reviewComment) error {
+	switch {
+	case event == reviewEventRequestChanges && body == "":
+		return errors.New("a comment is required to request changes")
+	case event == reviewEventComment && body == "" && len(comments) == 0:
+		return errors.New("a comment is required to submit a review without approving")
+	}
+	return nil
+}
+
+// resolveReviewTarget finds the pull request given by its number or URL, or the pull request of the current branch.
+func resolveReviewTarget(exe ghutil.Executor, pullRequest string) (reviewTarget, error) {
+	var target reviewTarget
+
+	if pullRequest == "" {
+		currentBranch, err := exe.Command("git", "branch", "--show-current")
+		if err != nil {
+			return target, err
+		}
+		branchID := strings.TrimSpace(currentBranch)
+
+		pullRequest, err = CheckForExistingPR(exe, branchID)
+		if err != nil {
+			return target, err
+		}
+		if pullRequest == "" {
+			return target, errors.New("No PR found for branch " + branchID)
+		}
+	} else if !pullRequestArgRegex.MatchString(pullRequest) {
+		return target, errors.Errorf("invalid pull request %q, use a PR number or URL", pullRequest)
+	}
+
+	res, err := exe.GH("pr", "view", pullRequest, "--json", "id,number,title,url,headRefOid")
+	if err != nil {
+		return target, errors.Wrap(err, "failed to find pull request "+pullRequest)
+	}
+	if err := json.Unmarshal([]byte(res), &target); err != nil {
+		return target, errors.Wrap(err, "failed to unmarshal the pull request")
+	}
+	return target, nil
+}
+
+// submitReview adds the comments to the pending review of the user, which is created if there is none, and submits
+// it with the event. Without an event, the review is left pending.
+func submitReview(exe ghutil.Executor, target reviewTarget, event, body string, comments []reviewComment) error {
+	prID := "#" + strconv.Itoa(target.Number)
+
+	reviewID, err := exe.GH("api", "graphql", "-f", "query="+pendingReviewQuery, "-f", "id="+target.ID,
+		"--jq", `.data.node.reviews.nodes[0].id // ""`)
+	if err != nil {
+		return errors.Wrap(err, "failed to find your pending review of "+prID)
+	}
+	reviewID = strings.TrimSpace(reviewID)
+
+	if reviewID == "" {
+		reviewID, err = exe.GH("api", "graphql", "-f", "query="+addReviewMutation, "-f", "pullRequestId="+target.ID,
+			"-f", "commitOID="+target.HeadRefOid, "--jq", ".data.addPullRequestReview.pullRequestReview.id")
+		if err != nil {
+			return errors.Wrap(err, "failed to start a review of "+prID)
+		}
+		reviewID = strings.TrimSpace(reviewID)
+	}
+
+	for _, comment := range comments {
+		_, err := exe.GH("api", "graphql", "-f", "query="+addReviewThreadMutation, "-f", "reviewId="+reviewID,
+			"-f", "path="+comment.Path, "-F", "line="+strconv.Itoa(comment.Line), "-f", "side="+comment.Side,
+			"-f", "body="+comment.Body)
+		if err != nil {
+			return errors.Wrapf(err, "failed to comment on %s:%d", comment.Path, comment.Line)
+		}
+	}
+
+	if event == "" {
+		return nil
+	}
+
+	args := []string{"api", "graphql", "-f", "query=" + submitReviewMutation, "-f", "reviewId=" + reviewID,
+		"-f", "event=" + event}
+	if body != "" {
+		args = append(args, "-f", "body="+body)
+	}
+	if _, err := exe.GH(args...); err != nil {
+		return errors.Wrap(err, "failed to submit the review of "+prID)
+	}
+
+	switch event {
+	case reviewEventApprove:
+		logger.Info("Approved " + prID)
+	case reviewEventRequestChanges:
+		logger.Info("Requested changes on " + prID)
+	default:
+		logger.Info("Commented on " + prID)
+	}
+	return nil
+}
+
+// parseDiff splits a unified diff into its files, and numbers the lines as in the old and new versions of the files.
+func parseDiff(diff string) []diffFile {
+	var files []diffFile
+	var current *diffFile
+	oldLine, newLine := 0, 0
+	inHunk := false
+
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			files = append(files, diffFile{})
+			current = &files[len(files)-1]
+			// The path of the new file, which is used if the diff has no hunks, for example for binary files
+			if _, path, found := strings.Cut(line, " b/"); found {
+				current.path = path
+			}
+			inHunk = false
+		case current == nil:
+			continue
+		case !inHunk && strings.HasPrefix(line, "+++ "):
+			if path := strings.TrimPrefix(line, "+++ "); path != "/dev/null" {
+				current.path = strings.TrimPrefix(path, "b/")
+			}
+		case !inHunk && strings.HasPrefix(line, "--- "):
+			if path := strings.TrimPrefix(line, "--- "); path != "/dev/null" {
+				current.path = strings.TrimPrefix(path, "a/")
+			}
+		case strings.HasPrefix(line, "@@ "):
+			oldLine, newLine = parseHunkHeader(line)
+			current.lines = append(current.lines, diffLine{kind: '@', text: line})
+			inHunk = true
+		case !inHunk || line == "":
+			continue
+		case line[0] == '+':
+			current.lines = append(current.lines, diffLine{kind: '+', text: line[1:], newLine: newLine})
+			newLine++
+		case line[0] == '-':
+			current.lines = append(current.lines, diffLine{kind: '-', text: line[1:], oldLine: oldLine})
+			oldLine++
+		case line[0] == ' ':
+			current.lines = append(current.lines, diffLine{kind: ' ', text: line[1:], oldLine: oldLine, newLine: newLine})
+			oldLine++
+			newLine++
+		}
+	}
+	return files
+}
+
+// parseHunkHeader returns the first old and new line numbers of a hunk header like @@ -10,6 +10,8 @@.
+func parseHunkHeader(header string) (int, int) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 {
+		return 0, 0
+	}
+	return hunkStart(fields[1]), hunkStart(fields[2])
+}
+
+func hunkStart(field string) int {
+	start, _, _ := strings.Cut(field[1:], ",")
+	n, err := strconv.Atoi(start)
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,269 @@
+package pr_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/elhub/gh-dxp/pkg/pr"
+	"github.com/elhub/gh-dxp/pkg/testutils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const reviewDiff = `diff --git a/pkg/app/app.go b/pkg/app/app.go
+index 1111111..2222222 100644
+--- a/pkg/app/app.go
++++ b/pkg/app/app.go
+@@ -10,4 +10,5 @@ func main() {
+ 	start()
+-	stop()
++	run()
++	stop(true)
+ }
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+--- a/old.txt
++++ /dev/null
+@@ -1,2 +0,0 @@
+-first
+-second
+diff --git a/logo.png b/logo.png
+new file mode 100644
+Binary files /dev/null and b/logo.png differ
+`
+
+func TestParseDiff(t *testing.T) {
+	files := pr.ParseDiff(reviewDiff)
+
+	assert.Equal(t, []string{
+		"@ 0 0 @@ -10,4 +10,5 @@ func main() {",
+		"  10 10 \tstart()",
+		"- 11 0 \tstop()",
+		"+ 0 11 \trun()",
+		"+ 0 12 \tstop(true)",
+		"  12 13 }",
+	}, files["pkg/app/app.go"])
+	assert.Equal(t, []string{
+		"@ 0 0 @@ -1,2 +0,0 @@",
+		"- 1 0 first",
+		"- 2 0 second",
+	}, files["old.txt"])
+	assert.Equal(t, []string{}, files["logo.png"])
+	assert.Len(t, files, 3)
+}
+
+func TestReviewUI(t *testing.T) {
+	t.Run("collects line comments and approves", func(t *testing.T) {
+		ui := pr.InitialReviewModel(42, reviewDiff)
+
+		// Comment on the hunk header is not possible
+		model, _ := press(t, ui, "c")
+		assert.Equal(t, "Move to a changed or unchanged line to comment on it", model.(pr.ReviewUI).Status())
+
+		// Comment on the removed line and the first added line
+		model, _ = press(t, model, "j", "j", "c", "Why?", "enter", "j", "c", "Nice", "enter")
+		// Replace the comment on the added line
+		model, _ = press(t, model, "c", "Very nice", "enter")
+		// Comment on the first line of the next file, and delete it again
+		model, _ = press(t, model, "n", "j", "c", "Gone", "enter", "d")
+
+		model, cmd := press(t, model, "a", "LGTM", "enter")
+		review := model.(pr.ReviewUI)
+
+		assert.Equal(t, []pr.ReviewComment{
+			{Path: "pkg/app/app.go", Line: 11, Side: "LEFT", Body: "Why?"},
+			{Path: "pkg/app/app.go", Line: 11, Side: "RIGHT", Body: "Very nice"},
+		}, review.Comments())
+		assert.Equal(t, "APPROVE", review.Event())
+		assert.Equal(t, "LGTM", review.Body())
+		assert.NotNil(t, cmd)
+	})
+
+	t.Run("requires a comment to request changes", func(t *testing.T) {
+		model, _ := press(t, pr.InitialReviewModel(42, reviewDiff), "x", "enter")
+		review := model.(pr.ReviewUI)
+
+		assert.Empty(t, review.Event())
+		assert.Equal(t, "Cannot submit the review: a comment is required to request changes", review.Status())
+
+		model, _ = press(t, review, "x", "Please add tests", "enter")
+		assert.Equal(t, "REQUEST_CHANGES", model.(pr.ReviewUI).Event())
+	})
+
+	t.Run("submits a comment review with line comments only", func(t *testing.T) {
+		model, _ := press(t, pr.InitialReviewModel(42, reviewDiff), "j", "c", "Typo", "enter", "m", "enter")
+		review := model.(pr.ReviewUI)
+
+		assert.Equal(t, "COMMENT", review.Event())
+		assert.Empty(t, review.Body())
+		assert.Len(t, review.Comments(), 1)
+	})
+
+	t.Run("quits without submitting", func(t *testing.T) {
+		model, cmd := press(t, pr.InitialReviewModel(42, reviewDiff), "q")
+
+		assert.Empty(t, model.(pr.ReviewUI).Event())
+		assert.NotNil(t, cmd)
+	})
+}
+
+// graphqlCall matches a gh api graphql call containing the operation and the fields.
+func graphqlCall(operation string, fields ...string) any {
+	return mock.MatchedBy(func(args []string) bool {
+		if len(args) < 4 || args[0] != "api" || args[1] != "graphql" || !strings.Contains(args[3], operation) {
+			return false
+		}
+		for _, field := range fields {
+			if !slices.Contains(args, field) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestSubmitReview(t *testing.T) {
+	comments := []pr.ReviewComment{
+		{Path: "pkg/app/app.go", Line: 11, Side: "LEFT", Body: "Why?"},
+		{Path: "pkg/app/app.go", Line: 12, Side: "RIGHT", Body: "Nice"},
+	}
+
+	t.Run("creates a review, adds the comments and submits it", func(t *testing.T) {
+		mockExe := new(testutils.MockExecutor)
+		mockExe.On("GH", graphqlCall("reviews(states: PENDING", "id=PR_1")).Return("\n", nil)
+		mockExe.On("GH", graphqlCall("addPullRequestReview(", "pullRequestId=PR_1", "commitOID=abc123")).
+			Return("PRR_1\n", nil)
+		mockExe.On("GH", graphqlCall("addPullRequestReviewThread", "reviewId=PRR_1", "line=11", "side=LEFT")).
+			Return("", nil)
+		mockExe.On("GH", graphqlCall("addPullRequestReviewThread", "reviewId=PRR_1", "line=12", "side=RIGHT")).
+			Return("", nil)
+		mockExe.On("GH", graphqlCall("submitPullRequestReview", "reviewId=PRR_1", "event=APPROVE", "body=LGTM")).
+			Return("", nil)
+
+		err := pr.SubmitReview(mockExe, "PR_1", 42, "APPROVE", "LGTM", comments)
+
+		require.NoError(t, err)
+		mockExe.AssertNumberOfCalls(t, "GH", 5)
+	})
+
+	t.Run("adds the comments to the pending review and leaves it pending", func(t *testing.T) {
+		mockExe := new(testutils.MockExecutor)
+		mockExe.On("GH", graphqlCall("reviews(states: PENDING", "id=PR_1")).Return("PRR_9\n", nil)
+		mockExe.On("GH", graphqlCall("addPullRequestReviewThread", "reviewId=PRR_9")).Return("", nil)
+
+		err := pr.SubmitReview(mockExe, "PR_1", 42, "", "", comments)
+
+		require.NoError(t, err)
+		mockExe.AssertNumberOfCalls(t, "GH", 3)
+	})
+
+	t.Run("fails if a comment cannot be added", func(t *testing.T) {
+		mockExe := new(testutils.MockExecutor)
+		mockExe.On("GH", graphqlCall("reviews(states: PENDING")).Return("PRR_9\n", nil)
+		mockExe.On("GH", graphqlCall("addPullRequestReviewThread")).Return("", errors.New("line is outside the diff"))
+
+		err := pr.SubmitReview(mockExe, "PR_1", 42, "COMMENT", "", comments)
+
+		require.EqualError(t, err, "failed to comment on pkg/app/app.go:11: line is outside the diff")
+	})
+}
+
+func TestExecuteReview(t *testing.T) {
+	prView := `{"id": "PR_1", "number": 42, "title": "Add feature", "url": "https://github.com/elhub/gh-dxp/pull/42",
+		"headRefOid": "abc123"}`
+	viewArgs := []string{"pr", "view", "42", "--json", "id,number,title,url,headRefOid"}
+
+	tests := []struct {
+		name        string
+		pullRequest string
+		options     *pr.ReviewOptions
+		prList      string
+		diff        string
+		expectedErr string
+	}{
+		{
+			name:    "approves the pull request of the current branch",
+			options: &pr.ReviewOptions{Approve: true},
+			prList:  "42\n",
+		},
+		{
+			name:        "requests changes on a pull request by number",
+			pullRequest: "42",
+			options:     &pr.ReviewOptions{RequestChanges: true, Body: "Please add tests"},
+		},
+		{
+			name:        "fetches the diff before showing it",
+			pullRequest: "42",
+			options:     &pr.ReviewOptions{TestRun: true},
+			diff:        reviewDiff,
+		},
+		{
+			name:        "fails if the pull request has no changes",
+			pullRequest: "42",
+			options:     &pr.ReviewOptions{TestRun: true},
+			expectedErr: "pull request #42 has no changes to review",
+		},
+		{
+			name:        "fails if the current branch has no pull request",
+			options:     &pr.ReviewOptions{Approve: true},
+			expectedErr: "No PR found for branch feature",
+		},
+		{
+			name:        "requires a comment to request changes",
+			pullRequest: "42",
+			options:     &pr.ReviewOptions{RequestChanges: true},
+			expectedErr: "a comment is required to request changes",
+		},
+		{
+			name:        "allows only one event",
+			pullRequest: "42",
+			options:     &pr.ReviewOptions{Approve: true, Comment: true},
+			expectedErr: "only one of --approve, --comment or --request-changes may be used",
+		},
+		{
+			name:        "requires an event with a body",
+			pullRequest: "42",
+			options:     &pr.ReviewOptions{Body: "Hello"},
+			expectedErr: "use --body with --approve, --comment or --request-changes",
+		},
+		{
+			name:        "rejects an argument that is not a pull request",
+			pullRequest: "main",
+			options:     &pr.ReviewOptions{Approve: true},
+			expectedErr: `invalid pull request "main", use a PR number or URL`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExe := new(testutils.MockExecutor)
+			mockExe.On("Command", "git", []string{"branch", "--show-current"}).Return("feature\n", nil)
+			mockExe.On("GH", []string{"pr", "list", "-H", "feature", "--json", "number", "--jq", ".[].number"}).
+				Return(tt.prList, nil)
+			mockExe.On("GH", viewArgs).Return(prView, nil)
+			mockExe.On("GH", []string{"pr", "diff", "https://github.com/elhub/gh-dxp/pull/42", "--color", "never"}).
+				Return(tt.diff, nil)
+			mockExe.On("GH", graphqlCall("reviews(states: PENDING")).Return("PRR_1\n", nil)
+			mockExe.On("GH", graphqlCall("submitPullRequestReview")).Return("", nil)
+
+			err := pr.ExecuteReview(mockExe, tt.pullRequest, tt.options)
+
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				mockExe.AssertNotCalled(t, "GH", graphqlCall("submitPullRequestReview"))
+				return
+			}
+			require.NoError(t, err)
+			if tt.options.Approve {
+				mockExe.AssertCalled(t, "GH", graphqlCall("submitPullRequestReview", "event=APPROVE"))
+			}
+			if tt.options.RequestChanges {
+				mockExe.AssertCalled(t, "GH",
+					graphqlCall("submitPullRequestReview", "event=REQUEST_CHANGES", "body=Please add tests"))
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package pr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const reviewHelp = "↑/↓ line • ←/→ file • c comment • d delete comment • a approve • x request changes • " +
+	"m comment • q quit"
+
+// initialReviewModel shows the first file of the diff.
+func initialReviewModel(target reviewTarget, files []diffFile) ReviewUI {
+	return ReviewUI{
+		target: target,
+		files:  files,
+		input:  textinput.New(),
+	}
+}
+
+// Init is the initial command for the Bubble Tea program.
+func (ui ReviewUI) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the model accordingly.
+func (ui ReviewUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		ui.width, ui.height = msg.Width, msg.Height
+		ui.scroll()
+		return ui, nil
+	case tea.KeyPressMsg:
+		if msg.String() == "ctrl+c" {
+			return ui, tea.Quit
+		}
+		if ui.inputMode != reviewInputNone {
+			return ui.handleInputKey(msg)
+		}
+		return ui.handleKey(msg)
+	}
+	return ui, nil
+}
+
+func (ui ReviewUI) handleKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	lines := ui.files[ui.file].lines
+
+	switch msg.String() {
+	case "q":
+		return ui, tea.Quit
+	case "up", "k":
+		ui.cursor = max(ui.cursor-1, 0)
+	case "down", "j":
+		ui.cursor = min(ui.cursor+1, max(len(lines)-1, 0))
+	case "pgup":
+		ui.cursor = max(ui.cursor-ui.pageSize(), 0)
+	case "pgdown":
+		ui.cursor = min(ui.cursor+ui.pageSize(), max(len(lines)-1, 0))
+	case "right", "n":
+		ui.showFile(min(ui.file+1, len(ui.files)-1))
+	case "left", "p":
+		ui.showFile(max(ui.file-1, 0))
+	case "c":
+		if _, ok := ui.commentAt(); !ok {
+			ui.status = "Move to a changed or unchanged line to comment on it"
+			return ui, nil
+		}
+		return ui, ui.prompt(reviewInputLineComment, "Comment: ")
+	case "d":
+		if i := ui.commentIndex(); i >= 0 {
+			ui.comments = append(ui.comments[:i], ui.comments[i+1:]...)
+			ui.status = "Deleted the comment"
+		}
+	case "a":
+		return ui, ui.prompt(reviewInputApprove, "Approve with a comment (optional): ")
+	case "x":
+		return ui, ui.prompt(reviewInputRequestChanges, "Request changes: ")
+	case "m":
+		return ui, ui.prompt(reviewInputComment, "Review comment: ")
+	}
+
+	ui.scroll()
+	return ui, nil
+}
+
+func (ui ReviewUI) handleInputKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		ui.inputMode = reviewInputNone
+		ui.input.Blur()
+		return ui, nil
+	case "enter":
+		mode := ui.inputMode
+		value := strings.TrimSpace(ui.input.Value())
+		ui.inputMode = reviewInputNone
+		ui.input.Blur()
+
+		if mode == reviewInputLineComment {
+			ui.addComment(value)
+			return ui, nil
+		}
+
+		event := map[reviewInputMode]string{
+			reviewInputApprove:        reviewEventApprove,
+			reviewInputComment:        reviewEventComment,
+			reviewInputRequestChanges: reviewEventRequestChanges,
+		}[mode]
+		if err := validateReview(event, value, ui.comments); err != nil {
+			ui.status = "Cannot submit the review: " + err.Error()
+			return ui, nil
+		}
+		ui.event = event
+		ui.body = value
+		return ui, tea.Quit
+	}
+
+	var cmd tea.Cmd
+	ui.input, cmd = ui.input.Update(msg)
+	return ui, cmd
+}
+
+func (ui *ReviewUI) prompt(mode reviewInputMode, prompt string) tea.Cmd {
+	ui.inputMode = mode
+	ui.input.Prompt = prompt
+	ui.input.SetValue("")
+	return ui.input.Focus()
+}
+
+func (ui *ReviewUI) showFile(file int) {
+	if file != ui.file {
+		ui.file = file
+		ui.cursor = 0
+		ui.offset = 0
+	}
+}
+
+// addComment adds a comment on the line under the cursor, or replaces the comment that is already there.
+func (ui *ReviewUI) addComment(body string) {
+	comment, ok := ui.commentAt()
+	if !ok || body == "" {
+		return
+	}
+	comment.Body = body
+
+	if i := ui.commentIndex(); i >= 0 {
+		ui.comments[i] = comment
+		ui.status = "Updated the comment"
+		return
+	}
+	ui.comments = append(ui.comments, comment)
+	ui.status = fmt.Sprintf("%d comments will be submitted with the review", len(ui.comments))
+}
+
+// commentAt returns a comment without a body for the line under the cursor, if the line can be commented on.
+func (ui ReviewUI) commentAt() (reviewComment, bool) {
+	file := ui.files[ui.file]
+	if ui.cursor >= len(file.lines) {
+		return reviewComment{}, false
+	}
+	line := file.lines[ui.cursor]
+	switch line.kind {
+	case '-':
+		return reviewComment{Path: file.path, Line: line.oldLine, Side: "LEFT"}, true
+	case '+', ' ':
+		return reviewComment{Path: file.path, Line: line.newLine, Side: "RIGHT"}, true
+	}
+	return reviewComment{}, false
+}
+
+// commentIndex returns the index of the comment on the line under the cursor, or -1 if there is none.
+func (ui ReviewUI) commentIndex() int {
+	at, ok := ui.commentAt()
+	if !ok {
+		return -1
+	}
+	for i, comment := range ui.comments {
+		if comment.Path == at.Path && comment.Line == at.Line && comment.Side == at.Side {
+			return i
+		}
+	}
+	return -1
+}
+
+// pageSize is the number of diff lines that fit in the window, leaving room for the header, input and help lines.
+func (ui ReviewUI) pageSize() int {
+	if ui.height == 0 {
+		return 20
+	}
+	return max(ui.height-4, 1)
+}
+
+// scroll keeps the cursor within the visible lines.
+func (ui *ReviewUI) scroll() {
+	page := ui.pageSize()
+	if ui.cursor < ui.offset {
+		ui.offset = ui.cursor
+	}
+	if ui.cursor >= ui.offset+page {
+		ui.offset = ui.cursor - page + 1
+	}
+}
+
+// View renders the UI.
+func (ui ReviewUI) View() tea.View {
+	file := ui.files[ui.file]
+	var b strings.Builder
+	fmt.Fprintf(&b, "#%d %s • %s (file %d of %d) • %d comments\n", ui.target.Number, ui.target.Title, file.path,
+		ui.file+1, len(ui.files), len(ui.comments))
+
+	commented := map[string]string{}
+	for _, comment := range ui.comments {
+		if comment.Path == file.path {
+			commented[comment.Side+strconv.Itoa(comment.Line)] = comment.Body
+		}
+	}
+
+	page := ui.pageSize()
+	shown := 0
+	for i := ui.offset; i < len(file.lines) && shown < page; i++ {
+		line := file.lines[i]
+		b.WriteString(ui.renderLine(line, i == ui.cursor) + "\n")
+		shown++
+
+		side, number := "RIGHT", line.newLine
+		if line.kind == '-' {
+			side, number = "LEFT", line.oldLine
+		}
+		if body, ok := commented[side+strconv.Itoa(number)]; ok && line.kind != '@' && shown < page {
+			b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("        💬 "+body) + "\n")
+			shown++
+		}
+	}
+	if len(file.lines) == 0 {
+		b.WriteString("No changes to show, for example because the file is binary\n")
+	}
+
+	if ui.inputMode != reviewInputNone {
+		b.WriteString(ui.input.View() + "\n")
+	} else {
+		b.WriteString(ui.status + "\n")
+	}
+	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(reviewHelp))
+
+	v := tea.NewView(b.String())
+	v.AltScreen = true
+	return v
+}
+
+// renderLine renders a diff line with its line number, coloured by whether it was added or removed.
+func (ui ReviewUI) renderLine(line diffLine, selected bool) string {
+	marker := "  "
+	if selected {
+		marker = "▶ "
+	}
+
+	var number int
+	var color string
+	switch line.kind {
+	case '@':
+		return marker + lipgloss.NewStyle().Foreground(lipgloss.Color("6")).Render(line.text)
+	case '+':
+		number, color = line.newLine, "2"
+	case '-':
+		number, color = line.oldLine, "1"
+	default:
+		number = line.newLine
+	}
+
+	text := fmt.Sprintf("%5d %c%s", number, line.kind, strings.ReplaceAll(line.text, "\t", "    "))
+	if ui.width > 0 {
+		text = ansi.Truncate(text, max(ui.width-ansi.StringWidth(marker), 1), "…")
+	}
+	if color != "" {
+		text = lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(text)
+	}
+	return marker + text
+}
@@ -0,0 +1,116 @@
+package pr_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elhub/gh-dxp/pkg/config"
+	"github.com/elhub/gh-dxp/pkg/pr"
+	"github.com/elhub/gh-dxp/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddCodeOwnerReviewers(t *testing.T) {
+	tests := []struct {
+		name      string
+		reviewers config.Reviewers
+		existing  []string
+		expected  []string
+		noCalls   bool
+	}{
+		{
+			name:     "disabled by default",
+			existing: []string{"carol"},
+			expected: []string{"carol"},
+			noCalls:  true,
+		},
+		{
+			name:      "auto adds code owners except the author",
+			reviewers: config.Reviewers{FromCodeOwners: config.ReviewersAuto},
+			expected:  []string{"elhub/api", "elhub/devxp"},
+		},
+		{
+			name:      "auto respects the maximum including explicit reviewers",
+			reviewers: config.Reviewers{FromCodeOwners: config.ReviewersAuto, Max: 2},
+			existing:  []string{"elhub/devxp"},
+			expected:  []string{"elhub/devxp", "elhub/api"},
+		},
+		{
+			name:      "maximum already reached",
+			reviewers: config.Reviewers{FromCodeOwners: config.ReviewersAuto, Max: 1},
+			existing:  []string{"carol"},
+			expected:  []string{"carol"},
+			noCalls:   true,
+		},
+		{
+			name:      "suggest only logs in test runs",
+			reviewers: config.Reviewers{FromCodeOwners: config.ReviewersSuggest},
+			expected:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(root, ".github"), 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(root, ".github", "CODEOWNERS"),
+				[]byte("* @elhub/devxp\npkg/api/ @elhub/api @alice\n"), 0o600))
+
+			mockExe := new(testutils.MockExecutor)
+			mockExe.On("GH", []string{"api", "user", "--jq", ".login"}).Return("alice\n", nil)
+			mockExe.On("Command", "git", []string{"rev-parse", "--show-toplevel"}).Return(root+"\n", nil)
+			mockExe.On("Command", "git", []string{"branch"}).Return("main\nfeature\n", nil)
+			mockExe.On("Command", "git", []string{"fetch", "origin", "main"}).Return("", nil)
+			mockExe.On("Command", "git", []string{"remote", "set-head", "origin", "--auto"}).Return("", nil)
+			mockExe.On("Command", "git", []string{"symbolic-ref", "--short", "refs/remotes/origin/HEAD"}).Return("origin/main\n", nil)
+			mockExe.On("Command", "git", []string{"diff", "--name-only", "origin/main", "--relative"}).
+				Return("pkg/api/a.go\npkg/api/b.go\nMakefile\n", nil)
+
+			options := &pr.CreateOptions{TestRun: true, Reviewers: tt.existing}
+			err := pr.AddCodeOwnerReviewers(mockExe, options, &config.Settings{Reviewers: tt.reviewers})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, options.Reviewers)
+			if tt.noCalls {
+				mockExe.AssertNotCalled(t, "GH", []string{"api", "user", "--jq", ".login"})
+			}
+		})
+	}
+}
+
+func TestAddCodeOwnerReviewersFlagEnablesSuggestions(t *testing.T) {
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("GH", []string{"api", "user", "--jq", ".login"}).Return("", assert.AnError)
+
+	options := &pr.CreateOptions{TestRun: true, SuggestReviewers: true}
+	err := pr.AddCodeOwnerReviewers(mockExe, options, &config.Settings{})
+
+	require.NoError(t, err)
+	assert.Empty(t, options.Reviewers)
+	mockExe.AssertExpectations(t)
+}
+
+func TestAddCodeOwnerReviewersComparesWithBaseBranch(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".github"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".github", "CODEOWNERS"),
+		[]byte("* @elhub/devxp\npkg/api/ @elhub/api\n"), 0o600))
+
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("GH", []string{"api", "user", "--jq", ".login"}).Return("alice\n", nil)
+	mockExe.On("Command", "git", []string{"rev-parse", "--show-toplevel"}).Return(root+"\n", nil)
+	mockExe.On("Command", "git", []string{"fetch", "origin", "release/1.0"}).Return("", nil)
+	mockExe.On("Command", "git", []string{"diff", "--name-only", "origin/release/1.0...HEAD", "--relative"}).
+		Return("pkg/api/a.go\n", nil)
+
+	options := &pr.CreateOptions{TestRun: true}
+	options.SetBaseBranch("release/1.0")
+	settings := &config.Settings{Reviewers: config.Reviewers{FromCodeOwners: config.ReviewersAuto}}
+	err := pr.AddCodeOwnerReviewers(mockExe, options, settings)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"elhub/api"}, options.Reviewers)
+	mockExe.AssertExpectations(t)
+}
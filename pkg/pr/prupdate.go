@@ -52,5 +52,13 @@ func ExecuteUpdate(exe ghutil.Executor, settings *config.Settings, options *Upda
 		return err
 	}
 
-	return update(exe, pr.branchID, prID)
+	err = update(exe, pr, prID, settings.PublishChecks || options.PublishChecks)
+	if err != nil {
+		return err
+	}
+
+	if options.RefreshBody {
+		return refreshBody(exe, pr, prID)
+	}
+	return nil
 }
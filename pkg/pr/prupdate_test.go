@@ -195,6 +195,9 @@ func TestExecuteUpdate(t *testing.T) {
 			mockExe.On("Command", "git", []string{"branch"}).Return(tt.existingBranches, nil)
 			mockExe.On("Command", "git", []string{"add", "-u"}).Return("", nil)
 			mockExe.On("Command", "git", []string{"fetch", "origin", "main"}).Return("", nil)
+			mockExe.On("Command", "git", []string{"log", "--format=", "--raw", "--no-abbrev", "--no-renames", "--diff-filter=AM", "origin/main..HEAD"}).Return("", nil)
+			mockExe.On("Command", "git", []string{"merge-base", "HEAD", "origin/main"}).Return("abc123\n", nil)
+			mockExe.On("Command", "git", []string{"diff", "--unified=0", "--no-color", "--no-ext-diff", "abc123"}).Return("", nil)
 			mockExe.On("Command", "git", []string{"commit", "-m", "default commit message"}).Return("", nil)
 			mockExe.On("Command", "git", []string{"show-ref", "--verify", "--quiet", "refs/heads/" + tt.currentBranch})
 			mockExe.On("CommandContext", mock.Anything, "npx", linterArgs).Return("", tt.expectedLintErr)
@@ -229,3 +232,39 @@ func TestExecuteUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteUpdate_PublishChecks(t *testing.T) {
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("Command", "git", []string{"branch", "--show-current"}).Return("branch1", nil)
+	mockExe.On("Command", "git", []string{"status", "--porcelain"}).Return("", nil)
+	mockExe.On("Command", "git", []string{"log", "--oneline", "origin/main.."}).Return("abc123 commit 1", nil)
+	mockExe.On("Command", "git", []string{"push"}).Return("", nil)
+	mockExe.On("Command", "git", []string{"rev-parse", "HEAD"}).Return("abc123\n", nil)
+	mockExe.On("Command", "git", []string{"log", "--format=", "--raw", "--no-abbrev", "--no-renames", "--diff-filter=AM", "origin/main..HEAD"}).Return("", nil)
+	mockExe.On("Command", "git", []string{"merge-base", "HEAD", "origin/main"}).Return("def456\n", nil)
+	mockExe.On("Command", "git", []string{"diff", "--unified=0", "--no-color", "--no-ext-diff", "def456"}).Return("", nil)
+	mockExe.On("GH", []string{"pr", "list", "-H", "branch1", "--json", "number", "--jq", ".[].number"}).Return("3", nil)
+	mockExe.On("GH", []string{"pr", "list", "-H", "branch1", "--json", "url", "--jq", ".[].url"}).
+		Return("https://github.com/elhub/demo/pull/3", nil)
+	mockExe.On("GH", []string{"pr", "view", "--json", "baseRefName", "--jq", ".baseRefName"}).Return("main", nil)
+	mockExe.On("GH", []string{"api", "--method", "POST", "repos/{owner}/{repo}/statuses/abc123",
+		"-f", "state=error", "-f", "context=dxp/lint", "-f", "description=The --nolint option was used"}).Return("", nil)
+	mockExe.On("GH", []string{"api", "--method", "POST", "repos/{owner}/{repo}/statuses/abc123",
+		"-f", "state=error", "-f", "context=dxp/renovate", "-f", "description=The --nolint option was used"}).Return("", nil)
+	mockExe.On("GH", []string{"api", "--method", "POST", "repos/{owner}/{repo}/statuses/abc123",
+		"-f", "state=success", "-f", "context=dxp/secrets", "-f", "description=No secrets found in the changes"}).Return("", nil)
+	mockExe.On("GH", []string{"api", "--method", "POST", "repos/{owner}/{repo}/statuses/abc123",
+		"-f", "state=error", "-f", "context=dxp/test", "-f", "description=The --nounit option was used"}).Return("", nil)
+
+	err := pr.ExecuteUpdate(mockExe,
+		&config.Settings{},
+		&pr.UpdateOptions{
+			TestRun:       true,
+			NoLint:        true,
+			NoUnit:        true,
+			PublishChecks: true,
+		})
+
+	require.NoError(t, err)
+	mockExe.AssertExpectations(t)
+}
@@ -0,0 +1,72 @@
+// Package renovate provides functionality to validate renovate config and inspect renovate updates.
+package renovate
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/pkg/errors"
+)
+
+// PackageJSON is the npm manifest, which can hold the renovate config under the "renovate" key.
+const PackageJSON = "package.json"
+
+// ConfigFiles lists the locations renovate reads its config from, in the order renovate looks for them.
+var ConfigFiles = []string{ //nolint: gochecknoglobals // Constant list of renovate config locations.
+	"renovate.json",
+	"renovate.json5",
+	".github/renovate.json",
+	".github/renovate.json5",
+	".gitlab/renovate.json",
+	".gitlab/renovate.json5",
+	".renovaterc",
+	".renovaterc.json",
+	".renovaterc.json5",
+	PackageJSON,
+}
+
+// DetectConfigFile returns the renovate config file used by the repository in the current directory, or an empty
+// string if the repository has no renovate config. package.json is only considered if it has a "renovate" key.
+func DetectConfigFile() string {
+	for _, file := range ConfigFiles {
+		if !ghutil.FileExists(file) {
+			continue
+		}
+		if file != PackageJSON {
+			return file
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		if config, err := ParseConfig(file, data); err == nil && config != nil {
+			return file
+		}
+	}
+	return ""
+}
+
+// ParseConfig parses the contents of a renovate config file. JSON5 syntax is accepted for all files, and for
+// package.json the "renovate" key is returned (nil if it is not present).
+func ParseConfig(file string, data []byte) (map[string]any, error) {
+	var config map[string]any
+	if err := json.Unmarshal([]byte(normalizeJSON5(string(data))), &config); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", file)
+	}
+
+	if file != PackageJSON {
+		return config, nil
+	}
+
+	renovateConfig, ok := config["renovate"]
+	if !ok {
+		return nil, nil
+	}
+	section, ok := renovateConfig.(map[string]any)
+	if !ok {
+		return nil, errors.New("the renovate key in package.json must be an object")
+	}
+	return section, nil
+}
@@ -0,0 +1,96 @@
+package renovate_test
+
+import (
+	"testing"
+
+	"github.com/elhub/gh-dxp/pkg/renovate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectConfigFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected string
+	}{
+		{
+			name:     "no config",
+			files:    map[string]string{"main.go": "package main"},
+			expected: "",
+		},
+		{
+			name:     "gitlab config",
+			files:    map[string]string{".gitlab/renovate.json": "{}"},
+			expected: ".gitlab/renovate.json",
+		},
+		{
+			name: "renovate precedence order",
+			files: map[string]string{
+				".renovaterc":           "{}",
+				".github/renovate.json": "{}",
+			},
+			expected: ".github/renovate.json",
+		},
+		{
+			name:     "package.json without renovate key",
+			files:    map[string]string{"package.json": `{"name": "app"}`},
+			expected: "",
+		},
+		{
+			name:     "package.json with renovate key",
+			files:    map[string]string{"package.json": `{"name": "app", "renovate": {}}`},
+			expected: "package.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			for name, content := range tt.files {
+				writeFile(t, name, content)
+			}
+
+			assert.Equal(t, tt.expected, renovate.DetectConfigFile())
+		})
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	t.Run("json5 syntax", func(t *testing.T) {
+		config, err := renovate.ParseConfig("renovate.json5", []byte(`
+// Renovate config
+{
+  /* presets */
+  extends: ['config:recommended', "github>elhub/renovate-config"],
+  labels: ['it\'s "quoted"',],
+  prHourlyLimit: 2,
+}
+`))
+
+		require.NoError(t, err)
+		assert.Equal(t, []any{"config:recommended", "github>elhub/renovate-config"}, config["extends"])
+		assert.Equal(t, []any{`it's "quoted"`}, config["labels"])
+		assert.InDelta(t, 2.0, config["prHourlyLimit"], 0)
+	})
+
+	t.Run("url in string is not a comment", func(t *testing.T) {
+		config, err := renovate.ParseConfig("renovate.json", []byte(`{"registryUrls": ["https://example.com"]}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, []any{"https://example.com"}, config["registryUrls"])
+	})
+
+	t.Run("package.json", func(t *testing.T) {
+		config, err := renovate.ParseConfig("package.json", []byte(`{"renovate": {"automerge": true}}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"automerge": true}, config)
+	})
+
+	t.Run("invalid syntax", func(t *testing.T) {
+		_, err := renovate.ParseConfig("renovate.json", []byte(`{"extends": [}`))
+
+		require.Error(t, err)
+	})
+}
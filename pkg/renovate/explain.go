@@ -0,0 +1,234 @@
+// Package renovate provides functionality to validate renovate config and inspect renovate updates.
+package renovate
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// maxPresetDepth limits how deep nested presets are resolved, guarding against presets that extend each other.
+const maxPresetDepth = 5
+
+// Explain prints a diff between the resolved renovate config on the base branch and the resolved config in the
+// working tree, so the effect of changes to the config or to the presets it extends can be reviewed.
+func Explain(exe ghutil.Executor, configFile string) error {
+	baseRef, err := exe.Command("git", "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	if err != nil {
+		return errors.Wrap(err, "failed to determine the base branch")
+	}
+	baseRef = strings.TrimSpace(baseRef)
+
+	baseConfig := map[string]any{}
+	if data, showErr := exe.Command("git", "show", baseRef+":"+configFile); showErr == nil {
+		parsed, parseErr := ParseConfig(configFile, []byte(data))
+		if parseErr != nil {
+			logger.Warn("Could not parse the renovate config on " + baseRef + ": " + parseErr.Error())
+		} else if parsed != nil {
+			baseConfig = parsed
+		}
+	}
+
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", configFile)
+	}
+	currentConfig, err := ParseConfig(configFile, data)
+	if err != nil {
+		return err
+	}
+
+	diff, err := DiffResolvedConfig(baseRef+":"+configFile, configFile,
+		ResolveConfig(exe, baseConfig), ResolveConfig(exe, currentConfig))
+	if err != nil {
+		return err
+	}
+
+	if diff == "" {
+		logger.Info("The resolved renovate config is unchanged")
+		return nil
+	}
+	logger.Info("Changes to the resolved renovate config:")
+	_, err = os.Stdout.Write([]byte(diff))
+	return err
+}
+
+// DiffResolvedConfig returns a unified diff between two resolved renovate configs, or an empty string if they are
+// equal.
+func DiffResolvedConfig(fromName, toName string, from, to map[string]any) (string, error) {
+	fromJSON, err := json.MarshalIndent(from, "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to format the resolved config")
+	}
+	toJSON, err := json.MarshalIndent(to, "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to format the resolved config")
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(fromJSON) + "\n"),
+		B:        difflib.SplitLines(string(toJSON) + "\n"),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  3,
+	})
+}
+
+// ResolveConfig merges the presets a renovate config extends into the config itself. Presets hosted on GitHub
+// (github> and local>) are fetched through the GitHub API; other presets, such as renovate's built-in ones, cannot be
+// resolved locally and are kept in the extends list of the result.
+func ResolveConfig(exe ghutil.Executor, config map[string]any) map[string]any {
+	return resolveConfig(exe, config, 0)
+}
+
+func resolveConfig(exe ghutil.Executor, config map[string]any, depth int) map[string]any {
+	resolved := map[string]any{}
+	var unresolved []any
+
+	for _, preset := range extendsOf(config) {
+		if depth >= maxPresetDepth {
+			unresolved = append(unresolved, preset)
+			continue
+		}
+		presetConfig, err := fetchPreset(exe, preset)
+		if err != nil {
+			if !errors.Is(err, errUnsupportedPreset) {
+				logger.Warn("Could not resolve preset " + preset + ": " + err.Error())
+			}
+			unresolved = append(unresolved, preset)
+			continue
+		}
+		resolved = mergeConfig(resolved, resolveConfig(exe, presetConfig, depth+1))
+	}
+
+	own := map[string]any{}
+	for key, value := range config {
+		if key != "extends" {
+			own[key] = value
+		}
+	}
+	resolved = mergeConfig(resolved, own)
+
+	if existing, ok := resolved["extends"].([]any); ok {
+		unresolved = append(slices.Clone(existing), unresolved...)
+	}
+	if len(unresolved) > 0 {
+		resolved["extends"] = unresolved
+	}
+	return resolved
+}
+
+func extendsOf(config map[string]any) []string {
+	var presets []string
+	switch extends := config["extends"].(type) {
+	case string:
+		presets = append(presets, extends)
+	case []any:
+		for _, preset := range extends {
+			if name, ok := preset.(string); ok {
+				presets = append(presets, name)
+			}
+		}
+	}
+	return presets
+}
+
+// mergeConfig merges source into target the way renovate merges presets: objects are merged recursively,
+// packageRules are appended, and any other value in source replaces the one in target.
+func mergeConfig(target, source map[string]any) map[string]any {
+	for key, value := range source {
+		switch v := value.(type) {
+		case map[string]any:
+			if existing, ok := target[key].(map[string]any); ok {
+				target[key] = mergeConfig(existing, v)
+				continue
+			}
+			target[key] = mergeConfig(map[string]any{}, v)
+		case []any:
+			if existing, ok := target[key].([]any); ok && key == "packageRules" {
+				// Copy the rules, since the existing slice may be shared with another resolved preset
+				target[key] = append(slices.Clone(existing), v...)
+				continue
+			}
+			target[key] = v
+		default:
+			target[key] = v
+		}
+	}
+	return target
+}
+
+var errUnsupportedPreset = errors.New("preset cannot be resolved locally")
+
+// presetLocation is the location of a preset hosted in a GitHub repository.
+type presetLocation struct {
+	repo string
+	file string
+	ref  string
+}
+
+// parsePreset parses preset names of the form github>owner/repo[//path/file][:name][#ref].
+func parsePreset(preset string) (presetLocation, error) {
+	var name string
+	for _, prefix := range []string{"github>", "local>"} {
+		if after, ok := strings.CutPrefix(preset, prefix); ok {
+			name = after
+		}
+	}
+	if name == "" {
+		return presetLocation{}, errUnsupportedPreset
+	}
+
+	location := presetLocation{file: "default.json"}
+	name, location.ref, _ = strings.Cut(name, "#")
+
+	if repo, file, ok := strings.Cut(name, "//"); ok {
+		location.repo = repo
+		location.file = file
+	} else if repo, presetName, ok := strings.Cut(name, ":"); ok {
+		location.repo = repo
+		location.file = presetName
+	} else {
+		location.repo = name
+	}
+
+	if path.Ext(location.file) == "" {
+		location.file += ".json"
+	}
+	if strings.Count(location.repo, "/") != 1 {
+		return presetLocation{}, errors.Errorf("invalid repository in preset %s", preset)
+	}
+	return location, nil
+}
+
+func fetchPreset(exe ghutil.Executor, preset string) (map[string]any, error) {
+	location, err := parsePreset(preset)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := "repos/" + location.repo + "/contents/" + location.file
+	if location.ref != "" {
+		endpoint += "?ref=" + location.ref
+	}
+	data, err := exe.GH("api", endpoint, "-H", "Accept: application/vnd.github.raw")
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := ParseConfig(location.file, []byte(data))
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return map[string]any{}, nil
+	}
+	return config, nil
+}
@@ -0,0 +1,82 @@
+package renovate_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/elhub/gh-dxp/pkg/renovate"
+	"github.com/elhub/gh-dxp/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveConfig(t *testing.T) {
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("GH", []string{
+		"api", "repos/elhub/renovate-config/contents/default.json", "-H", "Accept: application/vnd.github.raw",
+	}).Return(`{"extends": ["github>elhub/renovate-config:go#v1"], "labels": ["deps"], "packageRules": [{"automerge": true}]}`, nil)
+	mockExe.On("GH", []string{
+		"api", "repos/elhub/renovate-config/contents/go.json?ref=v1", "-H", "Accept: application/vnd.github.raw",
+	}).Return(`{gomod: {enabled: true}}`, nil)
+	mockExe.On("GH", []string{
+		"api", "repos/elhub/missing/contents/default.json", "-H", "Accept: application/vnd.github.raw",
+	}).Return("", errors.New("HTTP 404"))
+
+	resolved := renovate.ResolveConfig(mockExe, map[string]any{
+		"extends":      []any{"config:recommended", "github>elhub/renovate-config", "github>elhub/missing"},
+		"labels":       []any{"renovate"},
+		"packageRules": []any{map[string]any{"matchPackageNames": []any{"foo"}}},
+	})
+
+	assert.Equal(t, []any{"config:recommended", "github>elhub/missing"}, resolved["extends"])
+	assert.Equal(t, []any{"renovate"}, resolved["labels"])
+	assert.Equal(t, map[string]any{"enabled": true}, resolved["gomod"])
+	assert.Equal(t, []any{
+		map[string]any{"automerge": true},
+		map[string]any{"matchPackageNames": []any{"foo"}},
+	}, resolved["packageRules"])
+	mockExe.AssertExpectations(t)
+}
+
+func TestResolveConfigStopsAtMaxDepth(t *testing.T) {
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("GH", []string{
+		"api", "repos/elhub/loop/contents/default.json", "-H", "Accept: application/vnd.github.raw",
+	}).Return(`{"extends": ["github>elhub/loop"]}`, nil)
+
+	resolved := renovate.ResolveConfig(mockExe, map[string]any{"extends": []any{"github>elhub/loop"}})
+
+	assert.Equal(t, []any{"github>elhub/loop"}, resolved["extends"])
+	mockExe.AssertNumberOfCalls(t, "GH", 5)
+}
+
+func TestDiffResolvedConfig(t *testing.T) {
+	diff, err := renovate.DiffResolvedConfig("origin/main:renovate.json", "renovate.json",
+		map[string]any{"automerge": false, "labels": []any{"deps"}},
+		map[string]any{"automerge": true, "labels": []any{"deps"}})
+
+	require.NoError(t, err)
+	assert.Contains(t, diff, "--- origin/main:renovate.json")
+	assert.Contains(t, diff, "+++ renovate.json")
+	assert.Contains(t, diff, `-  "automerge": false,`)
+	assert.Contains(t, diff, `+  "automerge": true,`)
+
+	diff, err = renovate.DiffResolvedConfig("a", "b", map[string]any{"x": 1}, map[string]any{"x": 1})
+
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+}
+
+func TestRunWithExplain(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeFile(t, "renovate.json", `{"automerge": true}`)
+
+	mockExe := new(testutils.MockExecutor)
+	mockChangedFiles(mockExe, "renovate.json")
+	mockExe.On("Command", "git", []string{"show", "origin/main:renovate.json"}).Return(`{"automerge": false}`, nil)
+
+	err := renovate.Run(mockExe, nil, &renovate.Options{Explain: true})
+
+	require.NoError(t, err)
+	mockExe.AssertExpectations(t)
+}
@@ -0,0 +1,9 @@
+package renovate
+
+import "time"
+
+func SetNow(t time.Time) func() {
+	old := now
+	now = func() time.Time { return t }
+	return func() { now = old }
+}
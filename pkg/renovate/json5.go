@@ -0,0 +1,113 @@
+// Package renovate provides functionality to validate renovate config and inspect renovate updates.
+package renovate
+
+import (
+	"strings"
+	"unicode"
+)
+
+// normalizeJSON5 converts a JSON5 document into plain JSON so it can be parsed with encoding/json. It supports the
+// JSON5 features commonly used in renovate configs: comments, trailing commas, single-quoted strings and unquoted
+// object keys. Plain JSON is returned unchanged.
+func normalizeJSON5(input string) string {
+	var out strings.Builder
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case c == '"' || c == '\'':
+			i = copyString(runes, i, &out)
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			if i < len(runes) {
+				out.WriteRune('\n')
+			}
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i+1 < len(runes) && (runes[i] != '*' || runes[i+1] != '/') {
+				i++
+			}
+			i++
+		case c == '}' || c == ']':
+			trimTrailingComma(&out)
+			out.WriteRune(c)
+		case isIdentifierStart(c) && isKey(runes, i):
+			j := i
+			for j < len(runes) && isIdentifierPart(runes[j]) {
+				j++
+			}
+			out.WriteString(`"` + string(runes[i:j]) + `"`)
+			i = j - 1
+		default:
+			out.WriteRune(c)
+		}
+	}
+
+	return out.String()
+}
+
+// copyString writes the string literal starting at runes[start] as a double-quoted JSON string and returns the index
+// of its closing quote.
+func copyString(runes []rune, start int, out *strings.Builder) int {
+	quote := runes[start]
+	out.WriteRune('"')
+
+	i := start + 1
+	for ; i < len(runes) && runes[i] != quote; i++ {
+		c := runes[i]
+		switch {
+		case c == '\\' && i+1 < len(runes):
+			i++
+			if runes[i] == '\'' {
+				out.WriteRune('\'')
+			} else {
+				out.WriteRune('\\')
+				out.WriteRune(runes[i])
+			}
+		case c == '"':
+			out.WriteString(`\"`)
+		default:
+			out.WriteRune(c)
+		}
+	}
+
+	out.WriteRune('"')
+	return i
+}
+
+// trimTrailingComma removes a comma (and the whitespace after it) at the end of the output.
+func trimTrailingComma(out *strings.Builder) {
+	s := out.String()
+	trimmed := strings.TrimRightFunc(s, unicode.IsSpace)
+	if strings.HasSuffix(trimmed, ",") {
+		out.Reset()
+		out.WriteString(strings.TrimSuffix(trimmed, ","))
+	}
+}
+
+// isKey reports whether the identifier starting at runes[i] is an unquoted object key, i.e., it is followed by a colon.
+func isKey(runes []rune, i int) bool {
+	if i > 0 && isIdentifierPart(runes[i-1]) {
+		return false
+	}
+
+	j := i
+	for j < len(runes) && isIdentifierPart(runes[j]) {
+		j++
+	}
+	for j < len(runes) && unicode.IsSpace(runes[j]) {
+		j++
+	}
+	return j < len(runes) && runes[j] == ':'
+}
+
+func isIdentifierStart(c rune) bool {
+	return unicode.IsLetter(c) || c == '_' || c == '$'
+}
+
+func isIdentifierPart(c rune) bool {
+	return isIdentifierStart(c) || unicode.IsDigit(c)
+}
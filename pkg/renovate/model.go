@@ -1,7 +1,48 @@
-// Package renovate provides functionality to validate renovate configuration files.
+// Package renovate provides functionality to validate renovate config and inspect renovate updates.
 package renovate
 
+import "time"
+
+// DefaultValidatorVersion is the renovate version used to validate the config if none is configured.
+const DefaultValidatorVersion = "43.78.0"
+
 // Options represents the options for the renovate command.
 type Options struct {
-	Force bool
+	Force        bool
+	Explain      bool
+	StrictRemote bool
+}
+
+// StatusOptions represents the options for the renovate status command.
+type StatusOptions struct {
+	Org          string
+	Repo         string
+	Author       string
+	ApprovePatch bool
+	AutoConfirm  bool
+}
+
+// UpdatePR is an open pull request created by renovate.
+type UpdatePR struct {
+	Repository  string
+	Number      int
+	Title       string
+	URL         string
+	CreatedAt   time.Time
+	IsDraft     bool
+	Manager     string
+	Package     string
+	Version     string
+	UpdateType  string
+	ChecksState string
+}
+
+// Dashboard is an open renovate Dependency Dashboard issue.
+type Dashboard struct {
+	Repository       string
+	Number           int
+	URL              string
+	AwaitingApproval int
+	RateLimited      int
+	Errored          int
 }
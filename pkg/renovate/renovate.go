@@ -1,4 +1,4 @@
-// Package renovate provides functionality to validate renovate configuration files.
+// Package renovate provides functionality to validate renovate config and inspect renovate updates.
 package renovate
 
 import (
@@ -11,8 +11,14 @@ import (
 )
 
 // Run executes the renovate validation process.
-func Run(exe ghutil.Executor, _ *config.Settings, opts *Options) error {
-	renovateConfigChanged, err := isRenovateConfigUpdated(exe)
+func Run(exe ghutil.Executor, settings *config.Settings, opts *Options) error {
+	configFile := DetectConfigFile()
+	if configFile == "" {
+		logger.Info("Did not find a renovate config, skipping...")
+		return nil
+	}
+
+	renovateConfigChanged, err := isRenovateConfigUpdated(exe, configFile)
 	if err != nil {
 		logger.Info("The validation process returned an error looking for renovate config file: " + err.Error() + "\n")
 		return err
@@ -23,19 +29,42 @@ func Run(exe ghutil.Executor, _ *config.Settings, opts *Options) error {
 		return nil
 	}
 
-	args := []string{"npx", "--package", "renovate@43.78.0", "renovate-config-validator", "--strict"}
-	ctx := context.Background()
-	err = exe.CommandContext(ctx, args[0], args[1:]...)
+	if opts.StrictRemote {
+		args := validatorArgs(settings, configFile)
+		ctx := context.Background()
+		err = exe.CommandContext(ctx, args[0], args[1:]...)
+	} else {
+		err = ValidateFile(configFile)
+	}
 
 	if err != nil {
 		logger.Info("The validation process returned an error: " + err.Error() + "\n")
 		return err
 	}
+
+	if opts.Explain {
+		return Explain(exe, configFile)
+	}
 	return nil
 }
 
+// validatorArgs returns the command used to validate the given config file. The validator finds the config in
+// package.json on its own, but needs the path for all other files so it does not fall back to its default locations.
+func validatorArgs(settings *config.Settings, configFile string) []string {
+	version := DefaultValidatorVersion
+	if settings != nil && settings.RenovateVersion != "" {
+		version = settings.RenovateVersion
+	}
+
+	args := []string{"npx", "--package", "renovate@" + version, "renovate-config-validator", "--strict"}
+	if configFile != PackageJSON {
+		args = append(args, configFile)
+	}
+	return args
+}
+
 // isRenovateConfigUpdated checks if the renovate config file has been updated compared to the main branch.
-func isRenovateConfigUpdated(exe ghutil.Executor) (bool, error) {
+func isRenovateConfigUpdated(exe ghutil.Executor, configFile string) (bool, error) {
 	changedFiles, err := ghutil.GetChangedFiles(exe)
 	if err != nil {
 		return false, err
@@ -46,8 +75,5 @@ func isRenovateConfigUpdated(exe ghutil.Executor) (bool, error) {
 		return false, nil
 	}
 
-	if slices.Contains(changedFiles, ".github/renovate.json") {
-		return true, nil
-	}
-	return false, nil
+	return slices.Contains(changedFiles, configFile), nil
 }
@@ -1,6 +1,8 @@
 package renovate_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/elhub/gh-dxp/pkg/config"
@@ -12,59 +14,139 @@ import (
 
 const MainBranch = "origin/main"
 
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+	require.NoError(t, os.WriteFile(name, []byte(content), 0o600))
+}
+
+func mockChangedFiles(mockExe *testutils.MockExecutor, filesChanged string) {
+	mockExe.On("Command", "git", []string{"branch"}).Return("main\ndifferentBranch\n", nil)
+	mockExe.On("Command", "git", []string{"fetch", "origin", "main"}).Return("", nil)
+	mockExe.On("Command", "git", []string{"remote", "set-head", "origin", "--auto"}).Return("", nil)
+	mockExe.On("Command", "git", []string{"symbolic-ref", "--short", "refs/remotes/origin/HEAD"}).Return("origin/main\n", nil)
+	mockExe.On("Command", "git", []string{"diff", "--name-only", MainBranch, "--relative"}).Return(filesChanged, nil)
+}
+
 func TestRenovateValidationError(t *testing.T) {
 	test := []struct {
 		name          string
+		configFile    string
+		configContent string
 		filesChanged  string
 		forceValidate bool
-		expectedRun   bool
+		strictRemote  bool
+		version       string
+		expectedArgs  []string
 	}{
 		{
 			name:          "Test run on renovate config change",
+			configFile:    ".github/renovate.json",
+			configContent: `{}`,
 			filesChanged:  ".github/renovate.json",
-			forceValidate: false,
-			expectedRun:   true,
+			strictRemote:  true,
+			expectedArgs: []string{
+				"--package", "renovate@43.78.0", "renovate-config-validator", "--strict", ".github/renovate.json",
+			},
 		},
 		{
 			name:          "Test don't run with no renovate config changes",
+			configFile:    ".github/renovate.json",
+			configContent: `{}`,
 			filesChanged:  "file1.txt\nfile2.txt",
-			forceValidate: false,
-			expectedRun:   false,
 		},
 		{
 			name:          "Test run with no renovate config changes and --force used",
+			configFile:    ".github/renovate.json",
+			configContent: `{}`,
 			filesChanged:  "file1.txt\nfile2.txt",
 			forceValidate: true,
-			expectedRun:   true,
+			strictRemote:  true,
+			expectedArgs: []string{
+				"--package", "renovate@43.78.0", "renovate-config-validator", "--strict", ".github/renovate.json",
+			},
+		},
+		{
+			name:          "Test offline validation on renovate config change",
+			configFile:    "renovate.json",
+			configContent: `{"extends": ["config:recommended"]}`,
+			filesChanged:  "renovate.json",
+		},
+		{
+			name:          "Test run on renovate.json5 change with configured version",
+			configFile:    "renovate.json5",
+			configContent: `{ extends: ['config:recommended'], }`,
+			filesChanged:  "renovate.json5",
+			version:       "44.1.0",
+			strictRemote:  true,
+			expectedArgs: []string{
+				"--package", "renovate@44.1.0", "renovate-config-validator", "--strict", "renovate.json5",
+			},
+		},
+		{
+			name:          "Test run on package.json change with renovate key",
+			configFile:    "package.json",
+			configContent: `{"name": "app", "renovate": {"extends": ["config:recommended"]}}`,
+			filesChanged:  "package.json",
+			strictRemote:  true,
+			expectedArgs: []string{
+				"--package", "renovate@43.78.0", "renovate-config-validator", "--strict",
+			},
 		},
 	}
 
 	for _, tt := range test {
 		t.Run(tt.name, func(t *testing.T) {
-			mockExe := new(testutils.MockExecutor)
-			mockExe.On("Command", "git", []string{"branch"}).Return("main\ndifferentBranch\n", nil)
-			mockExe.On("Command", "git", []string{"fetch", "origin", "main"}).Return("", nil)
-			mockExe.On("Command", "git", []string{"remote", "set-head", "origin", "--auto"}).Return("", nil)
-			mockExe.On("Command", "git", []string{"symbolic-ref", "--short", "refs/remotes/origin/HEAD"}).Return("origin/main", nil)
-			mockExe.On("Command", "git", []string{"diff", "--name-only", MainBranch, "--relative"}).Return(tt.filesChanged, nil)
+			t.Chdir(t.TempDir())
+			writeFile(t, tt.configFile, tt.configContent)
 
-			renovateArgs := []string{
-				"--package", "renovate@43.78.0", "renovate-config-validator", "--strict",
-			}
+			mockExe := new(testutils.MockExecutor)
+			mockChangedFiles(mockExe, tt.filesChanged)
 
-			if tt.expectedRun {
-				mockExe.On("CommandContext", mock.Anything, "npx", renovateArgs).Return(nil, nil)
+			if tt.expectedArgs != nil {
+				mockExe.On("CommandContext", mock.Anything, "npx", tt.expectedArgs).Return(nil, nil)
 			}
 
-			err := renovate.Run(mockExe, &config.Settings{}, &renovate.Options{Force: tt.forceValidate})
+			err := renovate.Run(mockExe, &config.Settings{RenovateVersion: tt.version}, &renovate.Options{
+				Force:        tt.forceValidate,
+				StrictRemote: tt.strictRemote,
+			})
 
 			require.NoError(t, err)
 
-			if !tt.expectedRun {
-				mockExe.AssertNotCalled(t, "CommandContext", mock.Anything, "npx", renovateArgs)
+			if tt.expectedArgs == nil {
+				mockExe.AssertNotCalled(t, "CommandContext", mock.Anything, "npx", mock.Anything)
 			}
 
 			mockExe.AssertExpectations(t)
 		})
 	}
 }
+
+func TestRunOfflineValidationError(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeFile(t, ".renovaterc", `{"stabilityDays": 3}`)
+
+	mockExe := new(testutils.MockExecutor)
+	mockChangedFiles(mockExe, "main.go")
+
+	err := renovate.Run(mockExe, &config.Settings{}, &renovate.Options{Force: true})
+
+	var invalidErr *renovate.InvalidConfigError
+	require.ErrorAs(t, err, &invalidErr)
+	require.Len(t, invalidErr.Problems, 1)
+	require.Equal(t, "stabilityDays", invalidErr.Problems[0].Path)
+	mockExe.AssertNotCalled(t, "CommandContext", mock.Anything, "npx", mock.Anything)
+}
+
+func TestRunWithoutRenovateConfig(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeFile(t, "package.json", `{"name": "app"}`)
+
+	mockExe := new(testutils.MockExecutor)
+
+	err := renovate.Run(mockExe, &config.Settings{}, &renovate.Options{Force: true})
+
+	require.NoError(t, err)
+	mockExe.AssertNotCalled(t, "CommandContext", mock.Anything, "npx", mock.Anything)
+}
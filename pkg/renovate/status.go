@@ -0,0 +1,405 @@
+// Package renovate provides functionality to validate renovate config and inspect renovate updates.
+package renovate
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+// DefaultAuthor is the GitHub login of the renovate app.
+const DefaultAuthor = "app/renovate"
+
+const searchQuery = `query($searchQuery: String!, $after: String) {
+  search(query: $searchQuery, type: ISSUE, first: 100, after: $after) {
+    pageInfo { hasNextPage endCursor }
+    nodes {
+      ... on PullRequest {
+        number title url body createdAt isDraft headRefName
+        repository { nameWithOwner }
+        commits(last: 1) { nodes { commit { statusCheckRollup { state } } } }
+      }
+      ... on Issue {
+        number title url body
+        repository { nameWithOwner }
+      }
+    }
+  }
+}`
+
+var now = time.Now //nolint: gochecknoglobals // Replaced in tests.
+
+var (
+	titlePrefixRegex = regexp.MustCompile(`^[a-zA-Z]+(\([^)]*\))?!?:\s*`)
+	titleRegex       = regexp.MustCompile(
+		`(?i)^update (dependency |module |helm release |terraform |plugin |image )?(\S+)( action| docker tag| docker digest| orb| digest)?( to (\S+))?`)
+	updateTypeRegex = regexp.MustCompile(`\|\s*(major|minor|patch|digest|pin|pinDigest|lockFileMaintenance|replacement)\s*\|`)
+
+	// managers are the renovate managers that can start the topic of a branch, such as renovate/npm-lodash-4.x. Longer
+	// names come first, so that gradle-wrapper is matched before gradle.
+	managers = sortedByLength([]string{
+		"ansible", "ansible-galaxy", "argocd", "asdf", "azure-pipelines", "batect", "bazel", "bazel-module",
+		"bitbucket-pipelines", "buildkite", "bun", "bundler", "cargo", "circleci", "cloudbuild", "cocoapods", "composer",
+		"conan", "cpanfile", "devcontainer", "docker-compose", "dockerfile", "droneci", "flux", "git-submodules",
+		"github-actions", "gitlabci", "gitlabci-include", "gomod", "gradle", "gradle-wrapper", "helm-requirements",
+		"helm-values", "helmfile", "helmsman", "helmv3", "homebrew", "jenkins", "kubernetes", "kustomize", "leiningen",
+		"maven", "maven-wrapper", "mix", "nix", "npm", "nuget", "nvm", "pep621", "pip-compile", "pip_requirements",
+		"pip_setup", "pipenv", "poetry", "pre-commit", "pub", "sbt", "setup-cfg", "swift", "terraform",
+		"terraform-version", "terragrunt", "tflint-plugin", "travis", "woodpecker",
+	})
+)
+
+func sortedByLength(names []string) []string {
+	sort.SliceStable(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	return names
+}
+
+type searchNode struct {
+	Number     int       `json:"number"`
+	Title      string    `json:"title"`
+	URL        string    `json:"url"`
+	Body       string    `json:"body"`
+	CreatedAt  time.Time `json:"createdAt"`
+	IsDraft    bool      `json:"isDraft"`
+	HeadRef    string    `json:"headRefName"`
+	Repository struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"repository"`
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					State string `json:"state"`
+				} `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+}
+
+type searchResponse struct {
+	Data struct {
+		Search struct {
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Nodes []searchNode `json:"nodes"`
+		} `json:"search"`
+	} `json:"data"`
+}
+
+// ExecuteStatus shows the open renovate pull requests and Dependency Dashboards of a repository or an organization,
+// grouped by manager and package, and optionally approves and auto-merges pending patch updates.
+func ExecuteStatus(exe ghutil.Executor, opts *StatusOptions) error {
+	scope, err := searchScope(exe, opts)
+	if err != nil {
+		return err
+	}
+	author := opts.Author
+	if author == "" {
+		author = DefaultAuthor
+	}
+
+	s := ghutil.StartSpinner("Searching for renovate pull requests...", "Found renovate pull requests.")
+	prs, err := FindUpdatePRs(exe, scope, author)
+	if err != nil {
+		ghutil.RemoveFinalMsg(s)
+		s.Stop()
+		return err
+	}
+	dashboards, err := FindDashboards(exe, scope, author)
+	if err != nil {
+		ghutil.RemoveFinalMsg(s)
+		s.Stop()
+		return err
+	}
+	s.Stop()
+
+	logger.Info(FormatStatus(scope, prs, dashboards))
+
+	if opts.ApprovePatch {
+		return approvePatchUpdates(exe, prs, opts.AutoConfirm)
+	}
+	return nil
+}
+
+// searchScope returns the search qualifier limiting the search to the requested organization or repository. The
+// current repository is used if neither is given.
+func searchScope(exe ghutil.Executor, opts *StatusOptions) (string, error) {
+	if opts.Org != "" {
+		return "org:" + opts.Org, nil
+	}
+	if opts.Repo != "" {
+		return "repo:" + opts.Repo, nil
+	}
+
+	repo, err := exe.GH("repo", "view", "--json", "nameWithOwner", "--jq", ".nameWithOwner")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to determine the current repository, use --repo or --org")
+	}
+	return "repo:" + strings.TrimSpace(repo), nil
+}
+
+// FindUpdatePRs returns the open pull requests created by the given author within the search scope.
+func FindUpdatePRs(exe ghutil.Executor, scope, author string) ([]UpdatePR, error) {
+	nodes, err := search(exe, fmt.Sprintf("is:pr is:open author:%s %s", author, scope))
+	if err != nil {
+		return nil, err
+	}
+
+	prs := make([]UpdatePR, 0, len(nodes))
+	for _, node := range nodes {
+		pr := UpdatePR{
+			Repository: node.Repository.NameWithOwner,
+			Number:     node.Number,
+			Title:      node.Title,
+			URL:        node.URL,
+			CreatedAt:  node.CreatedAt,
+			IsDraft:    node.IsDraft,
+			UpdateType: updateType(node.Body),
+		}
+		pr.Manager, pr.Package, pr.Version = parseTitle(node.Title)
+		if manager, ok := managerFromBranch(node.HeadRef); ok {
+			pr.Manager = manager
+		}
+		if commits := node.Commits.Nodes; len(commits) > 0 && commits[0].Commit.StatusCheckRollup != nil {
+			pr.ChecksState = commits[0].Commit.StatusCheckRollup.State
+		}
+		prs = append(prs, pr)
+	}
+	return prs, nil
+}
+
+// FindDashboards returns the open Dependency Dashboard issues created by the given author within the search scope.
+func FindDashboards(exe ghutil.Executor, scope, author string) ([]Dashboard, error) {
+	nodes, err := search(exe, fmt.Sprintf(`is:issue is:open author:%s in:title "Dependency Dashboard" %s`, author, scope))
+	if err != nil {
+		return nil, err
+	}
+
+	dashboards := make([]Dashboard, 0, len(nodes))
+	for _, node := range nodes {
+		dashboards = append(dashboards, Dashboard{
+			Repository:       node.Repository.NameWithOwner,
+			Number:           node.Number,
+			URL:              node.URL,
+			AwaitingApproval: strings.Count(node.Body, "- [ ] <!-- approve-branch="),
+			RateLimited:      strings.Count(node.Body, "- [ ] <!-- unlimit-branch="),
+			Errored:          strings.Count(node.Body, "- [ ] <!-- retry-branch="),
+		})
+	}
+	return dashboards, nil
+}
+
+func search(exe ghutil.Executor, query string) ([]searchNode, error) {
+	var nodes []searchNode
+	after := ""
+	for {
+		args := []string{"api", "graphql", "-f", "query=" + searchQuery, "-f", "searchQuery=" + query}
+		if after != "" {
+			args = append(args, "-f", "after="+after)
+		}
+
+		out, err := exe.GH(args...)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to search GitHub")
+		}
+
+		var response searchResponse
+		if err := json.Unmarshal([]byte(out), &response); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal search results")
+		}
+
+		result := response.Data.Search
+		nodes = append(nodes, result.Nodes...)
+		if !result.PageInfo.HasNextPage {
+			return nodes, nil
+		}
+		after = result.PageInfo.EndCursor
+	}
+}
+
+// managerFromBranch returns the manager that starts the topic of a renovate branch, such as npm in
+// renovate/npm-lodash-4.x, if there is one. Branches only have this form if the additionalBranchPrefix of renovate
+// includes the manager.
+func managerFromBranch(branch string) (string, bool) {
+	_, topic, found := strings.Cut(branch, "/")
+	if !found {
+		return "", false
+	}
+	for _, manager := range managers {
+		rest, ok := strings.CutPrefix(topic, manager)
+		if ok && (strings.HasPrefix(rest, "-") || strings.HasPrefix(rest, "/")) {
+			return manager, true
+		}
+	}
+	return "", false
+}
+
+// parseTitle derives the manager, package and new version from a renovate PR title such as
+// "chore(deps): update module github.com/pkg/errors to v0.9.2" or "Update actions/checkout action to v4". The manager
+// is guessed from the words of the title, so it is only used if the branch does not name the manager.
+func parseTitle(title string) (string, string, string) {
+	title = titlePrefixRegex.ReplaceAllString(title, "")
+	match := titleRegex.FindStringSubmatch(title)
+	if match == nil {
+		return "other", title, ""
+	}
+
+	kind := strings.TrimSpace(strings.ToLower(match[1]))
+	suffix := strings.TrimSpace(strings.ToLower(match[3]))
+
+	manager := "dependency"
+	switch {
+	case kind == "module":
+		manager = "gomod"
+	case kind == "helm release":
+		manager = "helm"
+	case kind == "terraform":
+		manager = "terraform"
+	case kind == "plugin":
+		manager = "plugin"
+	case kind == "image", strings.HasPrefix(suffix, "docker"):
+		manager = "docker"
+	case suffix == "action":
+		manager = "github-actions"
+	case suffix == "orb":
+		manager = "circleci"
+	case kind == "" && suffix == "":
+		return "group", title, ""
+	}
+
+	return manager, match[2], match[5]
+}
+
+func updateType(body string) string {
+	match := updateTypeRegex.FindStringSubmatch(body)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// IsFailing reports whether the checks of the pull request have failed.
+func (pr UpdatePR) IsFailing() bool {
+	return pr.ChecksState == "FAILURE" || pr.ChecksState == "ERROR"
+}
+
+// FormatStatus renders the renovate pull requests grouped by manager and package, followed by the Dependency
+// Dashboards.
+func FormatStatus(scope string, prs []UpdatePR, dashboards []Dashboard) string {
+	var report strings.Builder
+
+	failing := 0
+	for _, pr := range prs {
+		if pr.IsFailing() {
+			failing++
+		}
+	}
+	fmt.Fprintf(&report, "Renovate in %s: %d open pull requests, %d with failing checks\n", scope, len(prs), failing)
+
+	sorted := make([]UpdatePR, len(prs))
+	copy(sorted, prs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Manager != sorted[j].Manager {
+			return sorted[i].Manager < sorted[j].Manager
+		}
+		if sorted[i].Package != sorted[j].Package {
+			return sorted[i].Package < sorted[j].Package
+		}
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	w := tabwriter.NewWriter(&report, 0, 0, 2, ' ', 0)
+	manager := ""
+	for _, pr := range sorted {
+		if pr.Manager != manager {
+			manager = pr.Manager
+			fmt.Fprintf(w, "\n%s\n", manager)
+		}
+
+		var notes []string
+		if pr.IsDraft {
+			notes = append(notes, "draft")
+		}
+		if pr.IsFailing() {
+			notes = append(notes, "❌ failing checks")
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s#%d\t%s\n", pr.Package, pr.Version, pr.UpdateType, age(pr.CreatedAt),
+			pr.Repository, pr.Number, strings.Join(notes, ", "))
+	}
+	_ = w.Flush()
+
+	if len(dashboards) > 0 {
+		report.WriteString("\nDependency Dashboards\n")
+		for _, d := range dashboards {
+			fmt.Fprintf(&report, "  %s#%d: %d awaiting approval, %d rate-limited, %d errored (%s)\n",
+				d.Repository, d.Number, d.AwaitingApproval, d.RateLimited, d.Errored, d.URL)
+		}
+	}
+
+	return report.String()
+}
+
+// age formats the time since t as a short duration such as "5h" or "3d".
+func age(t time.Time) string {
+	d := now().Sub(t)
+	if d < 24*time.Hour {
+		return strconv.Itoa(int(d.Hours())) + "h"
+	}
+	return strconv.Itoa(int(d.Hours()/24)) + "d"
+}
+
+// approvePatchUpdates approves and enables auto-merge for all patch updates whose checks have not failed.
+func approvePatchUpdates(exe ghutil.Executor, prs []UpdatePR, autoConfirm bool) error {
+	var patches []UpdatePR
+	for _, pr := range prs {
+		if pr.UpdateType == "patch" && !pr.IsFailing() && !pr.IsDraft {
+			patches = append(patches, pr)
+		}
+	}
+
+	if len(patches) == 0 {
+		logger.Info("No patch updates to approve")
+		return nil
+	}
+
+	if !autoConfirm {
+		confirmed, err := ghutil.AskToConfirm(fmt.Sprintf("Approve and auto-merge %d patch updates?", len(patches)))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return nil
+		}
+	}
+
+	var failed []string
+	for _, pr := range patches {
+		if _, err := exe.GH("pr", "review", pr.URL, "--approve"); err != nil {
+			logger.Warn("Failed to approve " + pr.URL + ": " + err.Error())
+			failed = append(failed, pr.URL)
+			continue
+		}
+		if _, err := exe.GH("pr", "merge", pr.URL, "--auto", "--squash"); err != nil {
+			logger.Warn("Failed to enable auto-merge for " + pr.URL + ": " + err.Error())
+			failed = append(failed, pr.URL)
+			continue
+		}
+		logger.Info("Approved and enabled auto-merge for " + pr.URL)
+	}
+
+	if len(failed) > 0 {
+		return errors.Errorf("failed to approve and auto-merge %d of %d patch updates", len(failed), len(patches))
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package renovate_test
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elhub/gh-dxp/pkg/renovate"
+	"github.com/elhub/gh-dxp/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func searchArgs(query string) any {
+	return mock.MatchedBy(func(args []string) bool {
+		return len(args) >= 6 && args[0] == "api" && args[1] == "graphql" && args[5] == "searchQuery="+query
+	})
+}
+
+func searchResponse(t *testing.T, nodes ...map[string]any) string {
+	t.Helper()
+	response := map[string]any{
+		"data": map[string]any{
+			"search": map[string]any{
+				"pageInfo": map[string]any{"hasNextPage": false},
+				"nodes":    nodes,
+			},
+		},
+	}
+	out, err := json.Marshal(response)
+	require.NoError(t, err)
+	return string(out)
+}
+
+func prNode(number int, title, updateType, checks string, createdAt time.Time) map[string]any {
+	return map[string]any{
+		"number":     number,
+		"title":      title,
+		"url":        "https://github.com/elhub/app/pull/" + strings.TrimSpace(string(rune('0'+number))),
+		"body":       "| Package | Update | Change |\n|---|---|---|\n| foo | " + updateType + " | `1.0.0` -> `1.0.1` |",
+		"createdAt":  createdAt.Format(time.RFC3339),
+		"isDraft":    false,
+		"repository": map[string]any{"nameWithOwner": "elhub/app"},
+		"commits": map[string]any{"nodes": []any{
+			map[string]any{"commit": map[string]any{"statusCheckRollup": map[string]any{"state": checks}}},
+		}},
+	}
+}
+
+func TestExecuteStatus(t *testing.T) {
+	current := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	defer renovate.SetNow(current)()
+
+	prQuery := "is:pr is:open author:app/renovate repo:elhub/app"
+	dashboardQuery := `is:issue is:open author:app/renovate in:title "Dependency Dashboard" repo:elhub/app`
+
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("GH", []string{"repo", "view", "--json", "nameWithOwner", "--jq", ".nameWithOwner"}).Return("elhub/app\n", nil)
+	mockExe.On("GH", searchArgs(prQuery)).Return(searchResponse(t,
+		prNode(1, "chore(deps): update module github.com/pkg/errors to v0.9.2", "patch", "SUCCESS", current.Add(-72*time.Hour)),
+		prNode(2, "Update actions/checkout action to v5", "major", "FAILURE", current.Add(-5*time.Hour)),
+		prNode(3, "fix(deps): update dependency lodash to v4.17.22", "patch", "FAILURE", current.Add(-48*time.Hour)),
+	), nil)
+	mockExe.On("GH", searchArgs(dashboardQuery)).Return(searchResponse(t, map[string]any{
+		"number":     4,
+		"title":      "Dependency Dashboard",
+		"url":        "https://github.com/elhub/app/issues/4",
+		"body":       "- [ ] <!-- approve-branch=renovate/foo -->foo\n- [ ] <!-- unlimit-branch=renovate/bar -->bar\n",
+		"repository": map[string]any{"nameWithOwner": "elhub/app"},
+	}), nil)
+	mockExe.On("GH", []string{"pr", "review", "https://github.com/elhub/app/pull/1", "--approve"}).Return("", nil)
+	mockExe.On("GH", []string{"pr", "merge", "https://github.com/elhub/app/pull/1", "--auto", "--squash"}).Return("", nil)
+
+	err := renovate.ExecuteStatus(mockExe, &renovate.StatusOptions{ApprovePatch: true, AutoConfirm: true})
+
+	require.NoError(t, err)
+	mockExe.AssertExpectations(t)
+	mockExe.AssertNotCalled(t, "GH", []string{"pr", "review", "https://github.com/elhub/app/pull/3", "--approve"})
+}
+
+func TestFindUpdatePRs(t *testing.T) {
+	created := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("GH", searchArgs("is:pr is:open author:app/renovate org:elhub")).Return(searchResponse(t,
+		prNode(1, "chore(deps): update module github.com/pkg/errors to v0.9.2", "patch", "SUCCESS", created),
+		prNode(2, "Update actions/checkout action to v5", "major", "FAILURE", created),
+		prNode(3, "Update all non-major dependencies", "minor", "PENDING", created),
+		prNode(4, "Update postgres Docker tag to v17", "major", "SUCCESS", created),
+		withBranch(prNode(5, "fix(deps): update dependency lodash to v4.17.22", "patch", "SUCCESS", created),
+			"renovate/npm-lodash-4.x"),
+		withBranch(prNode(6, "Update dependency org.slf4j:slf4j-api to v2.0.17", "patch", "SUCCESS", created),
+			"renovate/gradle-wrapper-8.x"),
+		withBranch(prNode(7, "Update dependency requests to v2.32.4", "patch", "SUCCESS", created),
+			"renovate/requests-2.x"),
+	), nil)
+
+	prs, err := renovate.FindUpdatePRs(mockExe, "org:elhub", renovate.DefaultAuthor)
+
+	require.NoError(t, err)
+	require.Len(t, prs, 7)
+	assert.Equal(t, []string{"gomod", "github.com/pkg/errors", "v0.9.2", "patch"},
+		[]string{prs[0].Manager, prs[0].Package, prs[0].Version, prs[0].UpdateType})
+	assert.Equal(t, []string{"github-actions", "actions/checkout", "v5"},
+		[]string{prs[1].Manager, prs[1].Package, prs[1].Version})
+	assert.True(t, prs[1].IsFailing())
+	assert.Equal(t, []string{"group", "Update all non-major dependencies"}, []string{prs[2].Manager, prs[2].Package})
+	assert.Equal(t, []string{"docker", "postgres"}, []string{prs[3].Manager, prs[3].Package})
+	assert.False(t, prs[2].IsFailing())
+	assert.Equal(t, []string{"npm", "lodash"}, []string{prs[4].Manager, prs[4].Package})
+	assert.Equal(t, "gradle-wrapper", prs[5].Manager)
+	assert.Equal(t, "dependency", prs[6].Manager)
+}
+
+func withBranch(node map[string]any, branch string) map[string]any {
+	node["headRefName"] = branch
+	return node
+}
+
+func TestFormatStatus(t *testing.T) {
+	current := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	defer renovate.SetNow(current)()
+
+	report := renovate.FormatStatus("org:elhub", []renovate.UpdatePR{
+		{Repository: "elhub/b", Number: 2, Manager: "npm", Package: "lodash", Version: "v4", UpdateType: "major",
+			CreatedAt: current.Add(-50 * time.Hour), ChecksState: "FAILURE"},
+		{Repository: "elhub/a", Number: 7, Manager: "gomod", Package: "golang.org/x/net", Version: "v0.40.0",
+			UpdateType: "minor", CreatedAt: current.Add(-3 * time.Hour)},
+	}, []renovate.Dashboard{{Repository: "elhub/a", Number: 1, URL: "https://github.com/elhub/a/issues/1", AwaitingApproval: 3}})
+
+	lines := strings.Split(report, "\n")
+	assert.Equal(t, "Renovate in org:elhub: 2 open pull requests, 1 with failing checks", lines[0])
+	gomod := slices.Index(lines, "gomod")
+	npm := slices.Index(lines, "npm")
+	require.NotEqual(t, -1, gomod)
+	assert.Less(t, gomod, npm)
+	assert.Contains(t, lines[gomod+1], "golang.org/x/net")
+	assert.Contains(t, lines[gomod+1], "3h")
+	assert.Contains(t, lines[npm+1], "2d")
+	assert.Contains(t, lines[npm+1], "❌ failing checks")
+	assert.Contains(t, report, "elhub/a#1: 3 awaiting approval, 0 rate-limited, 0 errored")
+}
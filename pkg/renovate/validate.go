@@ -0,0 +1,277 @@
+// Package renovate provides functionality to validate renovate config and inspect renovate updates.
+package renovate
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+//go:embed renovate-schema.json
+var schemaJSON []byte
+
+// schemaProperty is the part of a JSON schema property used by the offline validator.
+type schemaProperty struct {
+	Type               any                       `json:"type"`
+	Enum               []string                  `json:"enum"`
+	Items              *schemaProperty           `json:"items"`
+	Properties         map[string]schemaProperty `json:"properties"`
+	DeprecationMessage string                    `json:"deprecationMessage"`
+}
+
+// types returns the JSON types allowed for the property.
+func (p schemaProperty) types() []string {
+	switch t := p.Type.(type) {
+	case string:
+		return []string{t}
+	case []any:
+		var types []string
+		for _, name := range t {
+			if s, ok := name.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// Problem is a single issue found in a renovate config.
+type Problem struct {
+	Path    string
+	Message string
+}
+
+func (p Problem) String() string {
+	return p.Path + ": " + p.Message
+}
+
+// InvalidConfigError is returned when the offline validation finds problems in the renovate config.
+type InvalidConfigError struct {
+	File     string
+	Problems []Problem
+}
+
+func (e *InvalidConfigError) Error() string {
+	lines := make([]string, 0, len(e.Problems))
+	for _, problem := range e.Problems {
+		lines = append(lines, "  "+problem.String())
+	}
+	return fmt.Sprintf("%s is not a valid renovate config:\n%s", e.File, strings.Join(lines, "\n"))
+}
+
+// presetHosts are the prefixes of presets hosted in a repository, which must be followed by owner/repo.
+var presetHosts = []string{ //nolint: gochecknoglobals // Constant list of preset hosts.
+	"github>", "gitlab>", "gitea>", "forgejo>", "bitbucket>", "bitbucket-server>", "local>",
+}
+
+// deprecatedPresets maps renovate's removed or renamed built-in presets to their replacements.
+var deprecatedPresets = map[string]string{ //nolint: gochecknoglobals // Constant map of presets.
+	"config:base":   "config:recommended",
+	"config:js-app": "config:recommended",
+	"config:js-lib": "config:recommended",
+}
+
+var (
+	hostedPresetRegex = regexp.MustCompile(`^[^/\s:#]+/[^/\s:#]+(//[^\s#:]+|:[^\s#]+)?(#\S+)?$`)
+	presetRegex       = regexp.MustCompile(`^(@[^/\s]+/)?[^\s>]*(:[^\s]+)?$`)
+)
+
+// ValidateFile validates the renovate config in the given file without network access. It checks the syntax, that
+// all options are known and have the right type, that no deprecated options are used, and that the presets in
+// extends are well-formed. An *InvalidConfigError is returned if any problems are found.
+func ValidateFile(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", file)
+	}
+
+	config, err := ParseConfig(file, data)
+	if err != nil {
+		return &InvalidConfigError{File: file, Problems: []Problem{{Path: file, Message: err.Error()}}}
+	}
+
+	problems, err := ValidateConfig(config)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return &InvalidConfigError{File: file, Problems: problems}
+	}
+	return nil
+}
+
+// ValidateConfig validates a parsed renovate config against the embedded schema and returns the problems found,
+// sorted by path.
+func ValidateConfig(config map[string]any) ([]Problem, error) {
+	var schema schemaProperty
+	if err := json.Unmarshal(schemaJSON, &schema); err != nil {
+		return nil, errors.Wrap(err, "failed to read the embedded renovate schema")
+	}
+
+	problems := validateObject("", config, schema.Properties, schema.Properties)
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Path < problems[j].Path
+	})
+	return problems, nil
+}
+
+// validateObject validates the options of a config object. Package rules accept their own match options as well as
+// all regular options, so those are passed in as fallback.
+func validateObject(path string, config map[string]any, properties, fallback map[string]schemaProperty) []Problem {
+	var problems []Problem
+
+	for key, value := range config {
+		keyPath := joinPath(path, key)
+
+		property, ok := properties[key]
+		if !ok {
+			property, ok = fallback[key]
+		}
+		if !ok {
+			problems = append(problems, Problem{Path: keyPath, Message: "unknown configuration option"})
+			continue
+		}
+
+		if property.DeprecationMessage != "" {
+			problems = append(problems, Problem{
+				Path:    keyPath,
+				Message: "deprecated configuration option. " + property.DeprecationMessage,
+			})
+			continue
+		}
+
+		problems = append(problems, validateValue(keyPath, value, property, fallback)...)
+
+		if key == "extends" {
+			problems = append(problems, validateExtends(keyPath, value)...)
+		}
+	}
+
+	return problems
+}
+
+func validateValue(path string, value any, property schemaProperty, fallback map[string]schemaProperty) []Problem {
+	types := property.types()
+	actual := jsonType(value)
+	if len(types) > 0 && !slices.Contains(types, actual) && (actual != "integer" || !slices.Contains(types, "number")) {
+		return []Problem{{
+			Path:    path,
+			Message: fmt.Sprintf("must be of type %s, got %s", strings.Join(types, " or "), actual),
+		}}
+	}
+
+	if s, ok := value.(string); ok && len(property.Enum) > 0 && !slices.Contains(property.Enum, s) {
+		return []Problem{{
+			Path:    path,
+			Message: fmt.Sprintf("invalid value %q, must be one of %s", s, strings.Join(property.Enum, ", ")),
+		}}
+	}
+
+	items, ok := value.([]any)
+	if !ok || property.Items == nil {
+		return nil
+	}
+
+	var problems []Problem
+	for i, item := range items {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		if object, isObject := item.(map[string]any); isObject && property.Items.Properties != nil {
+			problems = append(problems, validateObject(itemPath, object, property.Items.Properties, fallback)...)
+			continue
+		}
+		problems = append(problems, validateValue(itemPath, item, schemaProperty{Type: property.Items.Type}, fallback)...)
+	}
+	return problems
+}
+
+// validateExtends checks that the presets in extends are well-formed and not deprecated. Whether a preset exists is
+// only known to renovate, so that is left to the remote validator.
+func validateExtends(path string, value any) []Problem {
+	var problems []Problem
+
+	presets := []any{value}
+	if list, ok := value.([]any); ok {
+		presets = list
+	}
+
+	for i, item := range presets {
+		preset, ok := item.(string)
+		if !ok {
+			continue
+		}
+		presetPath := path
+		if _, isList := value.([]any); isList {
+			presetPath = fmt.Sprintf("%s[%d]", path, i)
+		}
+
+		if replacement, deprecated := deprecatedPresets[preset]; deprecated {
+			problems = append(problems, Problem{
+				Path:    presetPath,
+				Message: fmt.Sprintf("deprecated preset %s. Use %s instead.", preset, replacement),
+			})
+			continue
+		}
+
+		if !isValidPreset(preset) {
+			problems = append(problems, Problem{Path: presetPath, Message: fmt.Sprintf("invalid preset reference %q", preset)})
+		}
+	}
+
+	return problems
+}
+
+func isValidPreset(preset string) bool {
+	if preset == "" {
+		return false
+	}
+	if strings.Contains(preset, "{{") {
+		return true
+	}
+
+	for _, host := range presetHosts {
+		if name, ok := strings.CutPrefix(preset, host); ok {
+			return hostedPresetRegex.MatchString(name)
+		}
+	}
+
+	if strings.HasPrefix(preset, "http://") || strings.HasPrefix(preset, "https://") {
+		return true
+	}
+	return presetRegex.MatchString(preset) && preset != ":"
+}
+
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,91 @@
+package renovate_test
+
+import (
+	"testing"
+
+	"github.com/elhub/gh-dxp/pkg/renovate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected []string
+	}{
+		{
+			name: "valid config",
+			config: `{
+				"$schema": "https://docs.renovatebot.com/renovate-schema.json",
+				"extends": ["config:recommended", ":semanticCommits", "github>elhub/renovate-config//go#v1"],
+				"schedule": ["before 6am on monday"],
+				"prHourlyLimit": 2,
+				"gomod": {"enabled": true},
+				"packageRules": [{
+					"matchUpdateTypes": ["patch"],
+					"matchPackageNames": ["/^golang.org/"],
+					"automerge": true
+				}]
+			}`,
+		},
+		{
+			name:     "unknown option",
+			config:   `{"automerg": true}`,
+			expected: []string{"automerg: unknown configuration option"},
+		},
+		{
+			name:   "wrong type and invalid value",
+			config: `{"automerge": "yes", "rangeStrategy": "latest", "prHourlyLimit": 1.5}`,
+			expected: []string{
+				"automerge: must be of type boolean, got string",
+				`prHourlyLimit: must be of type integer, got number`,
+				`rangeStrategy: invalid value "latest", must be one of auto, pin, bump, replace, widen, update-lockfile, in-range-only`,
+			},
+		},
+		{
+			name:   "deprecated options in package rules",
+			config: `{"packageRules": [{"packagePatterns": ["^foo"], "enabled": false}, {"matchPaths": ["a/**"]}]}`,
+			expected: []string{
+				"packageRules[0].packagePatterns: deprecated configuration option. Use matchPackageNames instead.",
+				"packageRules[1].matchPaths: deprecated configuration option. Use matchFileNames instead.",
+			},
+		},
+		{
+			name:   "invalid and deprecated presets",
+			config: `{"extends": ["config:base", "github>elhub", "local>elhub/renovate-config:go", ""]}`,
+			expected: []string{
+				"extends[0]: deprecated preset config:base. Use config:recommended instead.",
+				`extends[1]: invalid preset reference "github>elhub"`,
+				`extends[3]: invalid preset reference ""`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := renovate.ParseConfig("renovate.json", []byte(tt.config))
+			require.NoError(t, err)
+
+			problems, err := renovate.ValidateConfig(config)
+
+			require.NoError(t, err)
+			actual := make([]string, 0, len(problems))
+			for _, problem := range problems {
+				actual = append(actual, problem.String())
+			}
+			assert.ElementsMatch(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestValidateFileSyntaxError(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeFile(t, "renovate.json", `{"extends": [}`)
+
+	err := renovate.ValidateFile("renovate.json")
+
+	var invalidErr *renovate.InvalidConfigError
+	require.ErrorAs(t, err, &invalidErr)
+	assert.Contains(t, err.Error(), "renovate.json is not a valid renovate config")
+}
@@ -0,0 +1,100 @@
+package repo
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/pkg/errors"
+)
+
+// validateOptions checks the filter and layout options before anything is searched or cloned.
+func validateOptions(opts *Options) error {
+	if opts.PushedAfter != "" {
+		if _, err := time.Parse(time.DateOnly, opts.PushedAfter); err != nil {
+			return fmt.Errorf("invalid date %q for --pushed-after, use the format YYYY-MM-DD", opts.PushedAfter)
+		}
+	}
+	if opts.Visibility != "" && !slices.Contains([]string{"public", "private", "internal"}, opts.Visibility) {
+		return fmt.Errorf("invalid visibility %q, use public, private or internal", opts.Visibility)
+	}
+	if opts.Team != "" && !strings.Contains(opts.Team, "/") {
+		return fmt.Errorf("invalid team %q, use the format <org>/<team>", opts.Team)
+	}
+	if opts.Layout != "" && !strings.Contains(opts.Layout, "{repo}") {
+		return fmt.Errorf("invalid layout %q, the layout must contain {repo}", opts.Layout)
+	}
+	for _, pattern := range opts.Exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid exclude pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// retrieveTeamRepositories returns the full names of the repositories the given team (<org>/<team>) has access to.
+func retrieveTeamRepositories(exe ghutil.Executor, team string) ([]string, error) {
+	org, slug, _ := strings.Cut(strings.TrimPrefix(team, "@"), "/")
+
+	res, err := exe.GH("api", "--paginate", "orgs/"+org+"/teams/"+slug+"/repos", "--jq", ".[].full_name")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve the repositories of team "+team)
+	}
+	return ghutil.ConvertTerminalOutputIntoList(strings.TrimSpace(res)), nil
+}
+
+// matchesFilters reports whether the repository matches the name pattern and all filters in opts. If teamRepos is
+// not nil, the repository must also be one of them. Like a search for a name, the pattern matches any repository
+// whose name contains it, ignoring case.
+func matchesFilters(repo repositoryInfo, pattern string, opts *Options, teamRepos []string) bool {
+	pushedAfter, _ := time.Parse(time.DateOnly, opts.PushedAfter)
+
+	switch {
+	case !strings.Contains(strings.ToLower(repo.Name), strings.ToLower(pattern)):
+		return false
+	case repo.IsArchived && !opts.IncludeArchived:
+		return false
+	case opts.Language != "" && !strings.EqualFold(repo.Language, opts.Language):
+		return false
+	case opts.Visibility != "" && repo.Visibility != opts.Visibility:
+		return false
+	case opts.PushedAfter != "" && repo.PushedAt.Before(pushedAfter):
+		return false
+	case teamRepos != nil && !slices.Contains(teamRepos, repo.FullName):
+		return false
+	case isExcluded(repo, opts.Exclude):
+		return false
+	}
+
+	for _, topic := range opts.Topics {
+		if !slices.Contains(repo.Topics, topic) {
+			return false
+		}
+	}
+	return true
+}
+
+// isExcluded reports whether a pattern matches the name or the full name (<org>/<repo>) of the repository.
+func isExcluded(repo repositoryInfo, exclude []string) bool {
+	for _, pattern := range exclude {
+		if ok, _ := path.Match(pattern, repo.Name); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, repo.FullName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// targetDirectory returns the directory to clone the repository into according to the layout.
+func targetDirectory(repo repositoryInfo, layout string) string {
+	if layout == "" {
+		layout = DefaultLayout
+	}
+	org, _, _ := strings.Cut(repo.FullName, "/")
+	return strings.NewReplacer("{org}", org, "{repo}", repo.Name).Replace(layout)
+}
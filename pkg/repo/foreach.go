@@ -0,0 +1,237 @@
+package repo
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/elhub/gh-dxp/pkg/config"
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/elhub/gh-dxp/pkg/pr"
+	"github.com/pkg/errors"
+)
+
+// ExecuteForeach runs the command in every repository cloned in the current directory that matches opts.Match. The
+// command runs in parallel, and its output is printed per repository once all runs are done. With a branch and a
+// commit message, the changes made by the command are committed on a new branch, and with opts.CreatePR a pull
+// request is created for them through pr create.
+func ExecuteForeach(exe ghutil.Executor, settings *config.Settings, command []string, opts *ForeachOptions) error {
+	if len(command) == 0 {
+		return errors.New("no command given, use gh dxp repo foreach -- <command>")
+	}
+	if opts.Branch != "" && opts.CommitMessage == "" {
+		return errors.New("a commit message is required to commit the changes to a branch, use --commitmessage")
+	}
+	if opts.CreatePR && opts.Branch == "" {
+		return errors.New("a branch is required to create pull requests, use --branch")
+	}
+
+	dirs, err := findClonedRepositories(".")
+	if err != nil {
+		return err
+	}
+	dirs = matchDirectories(dirs, opts.Match)
+	if len(dirs) == 0 {
+		logger.Info("No repositories found in the current directory")
+		return nil
+	}
+
+	results := ForeachAll(exe, dirs, command, opts)
+
+	if opts.CreatePR {
+		if err := createPullRequests(exe, settings, results, opts); err != nil {
+			return err
+		}
+	}
+
+	for _, result := range results {
+		header := fmt.Sprintf("==> %s (exit code %d)\n", result.Directory, result.ExitCode)
+		if _, err := os.Stdout.Write([]byte(header + result.Output + "\n")); err != nil {
+			return err
+		}
+	}
+	logger.Info(FormatForeachResults(results))
+
+	failed := 0
+	for _, result := range results {
+		if result.Failed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed in %d of %d repositories", failed, len(results))
+	}
+	return nil
+}
+
+// ForeachAll runs the command in the repositories in the given directories with a pool of workers and commits the
+// changes if a branch is given. The results are returned in the order of the directories.
+func ForeachAll(exe ghutil.Executor, dirs []string, command []string, opts *ForeachOptions) []ForeachResult {
+	results := make([]ForeachResult, len(dirs))
+	p := newProgress(len(dirs), "running in")
+
+	runWorkers(len(dirs), opts.Workers, func(i int) {
+		p.start(dirs[i])
+		results[i] = runInRepository(exe, dirs[i], command, opts)
+		if results[i].Failed {
+			p.finish(dirs[i], errors.New(results[i].Outcome))
+		} else {
+			p.finish(dirs[i], nil)
+		}
+	})
+	p.stop()
+
+	return results
+}
+
+func runInRepository(exe ghutil.Executor, dir string, command []string, opts *ForeachOptions) ForeachResult {
+	result := ForeachResult{Directory: dir}
+
+	// Committing to a branch would include changes that were there before the command ran
+	if opts.Branch != "" {
+		status, err := exe.Command("git", "-C", dir, "status", "--porcelain")
+		if err != nil || strings.TrimSpace(status) != "" {
+			result.Outcome = "skipped, uncommitted changes"
+			result.Failed = true
+			return result
+		}
+	}
+
+	// The executor runs commands in the current directory, so the command changes directory in a shell first
+	args := append([]string{"-c", `cd "$0" && exec "$@"`, dir}, command...)
+	output, err := exe.Command("sh", args...)
+	result.Output = output
+	if err != nil {
+		result.ExitCode = exitCode(err)
+		result.Outcome = "command failed"
+		result.Failed = true
+		return result
+	}
+	result.Outcome = "ok"
+
+	if opts.Branch == "" {
+		return result
+	}
+
+	status, err := exe.Command("git", "-C", dir, "status", "--porcelain")
+	if err != nil {
+		result.Outcome = "failed to determine the changes"
+		result.Failed = true
+		return result
+	}
+	if strings.TrimSpace(status) == "" {
+		result.Outcome = "no changes"
+		return result
+	}
+
+	for _, git := range [][]string{
+		{"checkout", "-b", opts.Branch},
+		{"add", "-A"},
+		{"commit", "-m", opts.CommitMessage},
+	} {
+		if _, err := exe.Command("git", append([]string{"-C", dir}, git...)...); err != nil {
+			result.Outcome = "git " + git[0] + " failed"
+			result.Failed = true
+			return result
+		}
+	}
+	result.Outcome = "committed to " + opts.Branch
+	result.Changed = true
+	return result
+}
+
+// createPullRequests creates a pull request in every repository the command changed. pr create works on the current
+// directory, so the repositories are handled one at a time, each with its own .devxp settings.
+func createPullRequests(exe ghutil.Executor, settings *config.Settings, results []ForeachResult, opts *ForeachOptions) error {
+	root, err := os.Getwd()
+	if err != nil {
+		return errors.Wrap(err, "failed to determine the current directory")
+	}
+
+	for i := range results {
+		if !results[i].Changed {
+			continue
+		}
+
+		logger.Infof("Creating pull request in %s", results[i].Directory)
+		if err := exe.Chdir(filepath.Join(root, results[i].Directory)); err != nil {
+			return errors.Wrap(err, "failed to change directory")
+		}
+
+		// MergeSettings changes the settings it merges into, so every repository starts from a copy
+		repoSettings := *settings
+		if localSettings, err := config.ReadRepoConfig(".devxp"); err == nil {
+			repoSettings = *config.MergeSettings(&repoSettings, localSettings)
+		}
+		err = pr.ExecuteCreate(exe, &repoSettings, &pr.CreateOptions{
+			NonInteractive: true,
+			Branch:         opts.Branch,
+			CommitMessage:  opts.CommitMessage,
+			Title:          opts.Title,
+			Body:           opts.Body,
+			Draft:          opts.Draft,
+			NoLint:         opts.NoLint,
+			NoUnit:         opts.NoUnit,
+		})
+		if err != nil {
+			results[i].Outcome = "committed to " + opts.Branch + ", pull request failed: " + err.Error()
+			results[i].Failed = true
+		} else {
+			results[i].Outcome = "pull request created from " + opts.Branch
+		}
+
+		if err := exe.Chdir(root); err != nil {
+			return errors.Wrap(err, "failed to change directory")
+		}
+	}
+	return nil
+}
+
+// matchDirectories returns the directories whose path or name matches one of the glob patterns, or all directories if
+// there are no patterns.
+func matchDirectories(dirs, patterns []string) []string {
+	if len(patterns) == 0 {
+		return dirs
+	}
+
+	var matched []string
+	for _, dir := range dirs {
+		for _, pattern := range patterns {
+			okPath, _ := path.Match(pattern, filepath.ToSlash(dir))
+			okName, _ := path.Match(pattern, filepath.Base(dir))
+			if okPath || okName {
+				matched = append(matched, dir)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// FormatForeachResults formats the results of a foreach run as a table.
+func FormatForeachResults(results []ForeachResult) string {
+	var report strings.Builder
+
+	w := tabwriter.NewWriter(&report, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REPOSITORY\tEXIT CODE\tRESULT")
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", result.Directory, strconv.Itoa(result.ExitCode), result.Outcome)
+	}
+	_ = w.Flush()
+
+	return strings.TrimSuffix(report.String(), "\n")
+}
@@ -0,0 +1,144 @@
+package repo_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elhub/gh-dxp/pkg/config"
+	"github.com/elhub/gh-dxp/pkg/repo"
+	"github.com/elhub/gh-dxp/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func shArgs(dir string, command ...string) []string {
+	return append([]string{"-c", `cd "$0" && exec "$@"`, dir}, command...)
+}
+
+func TestForeachAll(t *testing.T) {
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("Command", "sh", shArgs("repo1", "git", "status", "--short")).Return(" M README.md\n", nil)
+	mockExe.On("Command", "sh", shArgs("repo2", "git", "status", "--short")).Return("fatal: oops\n", errors.New("exit status 128"))
+
+	results := repo.ForeachAll(mockExe, []string{"repo1", "repo2"}, []string{"git", "status", "--short"}, &repo.ForeachOptions{})
+
+	require.Len(t, results, 2)
+	assert.Equal(t, repo.ForeachResult{Directory: "repo1", Output: " M README.md\n", Outcome: "ok"}, results[0])
+	assert.True(t, results[1].Failed)
+	assert.Equal(t, "command failed", results[1].Outcome)
+	assert.Equal(t, -1, results[1].ExitCode)
+	mockExe.AssertExpectations(t)
+
+	report := repo.FormatForeachResults(results)
+	assert.Contains(t, report, "REPOSITORY  EXIT CODE  RESULT")
+	assert.Contains(t, report, "repo2       -1         command failed")
+}
+
+func TestForeachAll_CommitToBranch(t *testing.T) {
+	command := []string{"touch", "NEW.md"}
+	opts := &repo.ForeachOptions{Branch: "add-file", CommitMessage: "chore: add file"}
+
+	mockExe := new(testutils.MockExecutor)
+	// repo1 is changed by the command
+	mockExe.On("Command", "git", []string{"-C", "repo1", "status", "--porcelain"}).Return("", nil).Once()
+	mockExe.On("Command", "sh", shArgs("repo1", command...)).Return("", nil)
+	mockExe.On("Command", "git", []string{"-C", "repo1", "status", "--porcelain"}).Return("?? NEW.md\n", nil).Once()
+	mockExe.On("Command", "git", []string{"-C", "repo1", "checkout", "-b", "add-file"}).Return("", nil)
+	mockExe.On("Command", "git", []string{"-C", "repo1", "add", "-A"}).Return("", nil)
+	mockExe.On("Command", "git", []string{"-C", "repo1", "commit", "-m", "chore: add file"}).Return("", nil)
+	// repo2 is not changed by the command
+	mockExe.On("Command", "git", []string{"-C", "repo2", "status", "--porcelain"}).Return("", nil).Twice()
+	mockExe.On("Command", "sh", shArgs("repo2", command...)).Return("", nil)
+	// repo3 has uncommitted changes before the command runs
+	mockExe.On("Command", "git", []string{"-C", "repo3", "status", "--porcelain"}).Return(" M README.md\n", nil).Once()
+
+	results := repo.ForeachAll(mockExe, []string{"repo1", "repo2", "repo3"}, command, opts)
+
+	assert.Equal(t, "committed to add-file", results[0].Outcome)
+	assert.True(t, results[0].Changed)
+	assert.Equal(t, "no changes", results[1].Outcome)
+	assert.False(t, results[1].Changed)
+	assert.Equal(t, "skipped, uncommitted changes", results[2].Outcome)
+	assert.True(t, results[2].Failed)
+	mockExe.AssertExpectations(t)
+	mockExe.AssertNotCalled(t, "Command", "sh", shArgs("repo3", command...))
+}
+
+func TestExecuteForeach(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.MkdirAll(filepath.Join("gh-dxp", ".git"), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join("myorg", "gh-other", ".git"), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join("docs", ".git"), 0o750))
+
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("Command", "sh", shArgs("gh-dxp", "make")).Return("", nil)
+	mockExe.On("Command", "sh", shArgs(filepath.Join("myorg", "gh-other"), "make")).Return("", nil)
+
+	err := repo.ExecuteForeach(mockExe, config.DefaultSettings(), []string{"make"}, &repo.ForeachOptions{Match: []string{"gh-*"}})
+
+	require.NoError(t, err)
+	mockExe.AssertExpectations(t)
+	mockExe.AssertNumberOfCalls(t, "Command", 2)
+}
+
+func TestExecuteForeach_PullRequestFailure(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+	require.NoError(t, os.MkdirAll(filepath.Join("repo1", ".git"), 0o750))
+
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("Command", "git", []string{"-C", "repo1", "status", "--porcelain"}).Return("", nil).Once()
+	mockExe.On("Command", "sh", shArgs("repo1", "make")).Return("", nil)
+	mockExe.On("Command", "git", []string{"-C", "repo1", "status", "--porcelain"}).Return(" M go.mod\n", nil).Once()
+	mockExe.On("Command", "git", mock.MatchedBy(func(args []string) bool { return args[1] == "repo1" })).Return("", nil)
+	mockExe.On("Chdir", filepath.Join(root, "repo1")).Return(nil, nil)
+	mockExe.On("Command", "git", []string{"branch", "--show-current"}).Return("", errors.New("not a git repository"))
+	mockExe.On("Chdir", root).Return(nil, nil)
+
+	err := repo.ExecuteForeach(mockExe, config.DefaultSettings(), []string{"make"}, &repo.ForeachOptions{
+		Branch:        "update",
+		CommitMessage: "chore: update",
+		CreatePR:      true,
+	})
+
+	require.EqualError(t, err, "failed in 1 of 1 repositories")
+	mockExe.AssertExpectations(t)
+}
+
+func TestExecuteForeach_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		command []string
+		options *repo.ForeachOptions
+		wantErr string
+	}{
+		{
+			name:    "No command",
+			options: &repo.ForeachOptions{},
+			wantErr: "no command given, use gh dxp repo foreach -- <command>",
+		},
+		{
+			name:    "Branch without commit message",
+			command: []string{"make"},
+			options: &repo.ForeachOptions{Branch: "update"},
+			wantErr: "a commit message is required to commit the changes to a branch, use --commitmessage",
+		},
+		{
+			name:    "Pull request without branch",
+			command: []string{"make"},
+			options: &repo.ForeachOptions{CreatePR: true},
+			wantErr: "a branch is required to create pull requests, use --branch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.ExecuteForeach(new(testutils.MockExecutor), config.DefaultSettings(), tt.command, tt.options)
+
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package repo
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+const repositoriesQuery = `query($owner: String!, $after: String) {
+  repositoryOwner(login: $owner) {
+    repositories(first: 100, after: $after, orderBy: {field: NAME, direction: ASC}) {
+      totalCount
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        name nameWithOwner url isArchived visibility pushedAt
+        primaryLanguage { name }
+        repositoryTopics(first: 100) { nodes { topic { name } } }
+      }
+    }
+  }
+}`
+
+// maxRateLimitRetries is the number of times a request is retried when the rate limit is exceeded.
+const maxRateLimitRetries = 5
+
+var retryAfterRegex = regexp.MustCompile(`(?im)^retry-after:\s*(\d+)`) //nolint: gochecknoglobals // Compiled once.
+
+type repositoryNode struct {
+	Name            string    `json:"name"`
+	NameWithOwner   string    `json:"nameWithOwner"`
+	URL             string    `json:"url"`
+	IsArchived      bool      `json:"isArchived"`
+	Visibility      string    `json:"visibility"`
+	PushedAt        time.Time `json:"pushedAt"`
+	PrimaryLanguage *struct {
+		Name string `json:"name"`
+	} `json:"primaryLanguage"`
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string `json:"name"`
+			} `json:"topic"`
+		} `json:"nodes"`
+	} `json:"repositoryTopics"`
+}
+
+type repositoriesResponse struct {
+	Data struct {
+		RepositoryOwner *struct {
+			Repositories struct {
+				TotalCount int `json:"totalCount"`
+				PageInfo   struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []repositoryNode `json:"nodes"`
+			} `json:"repositories"`
+		} `json:"repositoryOwner"`
+	} `json:"data"`
+}
+
+// rateLimitError is returned when a request is still rate limited after all retries.
+type rateLimitError struct {
+	msg string
+}
+
+func (e *rateLimitError) Error() string {
+	return e.msg
+}
+
+// retrieveUserOrgs lists all organizations of the current user.
+func retrieveUserOrgs(exe ghutil.Executor) ([]string, error) {
+	res, err := exe.GH("api", "--paginate", "user/orgs", "--jq", ".[].login")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve user organizations")
+	}
+	return ghutil.ConvertTerminalOutputIntoList(strings.TrimSpace(res)), nil
+}
+
+// listRepositories lists all repositories of the organization or user, page by page. If the rate limit is still
+// exceeded after retrying, the repositories listed so far are returned together with a warning that the list was
+// truncated.
+func listRepositories(exe ghutil.Executor, owner string, sleep func(time.Duration)) ([]repositoryInfo, error) {
+	var repositories []repositoryInfo
+	total := 0
+	after := ""
+
+	for {
+		args := []string{"api", "graphql", "-i", "-f", "query=" + repositoriesQuery, "-f", "owner=" + owner}
+		if after != "" {
+			args = append(args, "-f", "after="+after)
+		}
+
+		body, err := ghWithRateLimit(exe, sleep, args...)
+		var rateLimited *rateLimitError
+		if errors.As(err, &rateLimited) && len(repositories) > 0 {
+			logger.Warnf("Listing of the repositories in %s was truncated after %d of %d repositories: %s",
+				owner, len(repositories), total, err.Error())
+			return repositories, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list the repositories in "+owner)
+		}
+
+		var response repositoriesResponse
+		if err := json.Unmarshal([]byte(body), &response); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal the repositories in "+owner)
+		}
+		if response.Data.RepositoryOwner == nil {
+			return nil, fmt.Errorf("could not find the organization or user %s", owner)
+		}
+
+		page := response.Data.RepositoryOwner.Repositories
+		total = page.TotalCount
+		for _, node := range page.Nodes {
+			repositories = append(repositories, node.toRepositoryInfo())
+		}
+		if !page.PageInfo.HasNextPage {
+			break
+		}
+		after = page.PageInfo.EndCursor
+	}
+
+	if len(repositories) < total {
+		logger.Warnf("Only %d of the %d repositories in %s could be listed", len(repositories), total, owner)
+	}
+	return repositories, nil
+}
+
+func (n repositoryNode) toRepositoryInfo() repositoryInfo {
+	repo := repositoryInfo{
+		Name:       n.Name,
+		FullName:   n.NameWithOwner,
+		URL:        n.URL,
+		IsArchived: n.IsArchived,
+		Visibility: strings.ToLower(n.Visibility),
+		PushedAt:   n.PushedAt,
+	}
+	if n.PrimaryLanguage != nil {
+		repo.Language = n.PrimaryLanguage.Name
+	}
+	for _, topic := range n.RepositoryTopics.Nodes {
+		repo.Topics = append(repo.Topics, topic.Topic.Name)
+	}
+	return repo
+}
+
+// ghWithRateLimit runs a gh api -i command and returns the response body. The command is run through Command rather
+// than GH, since the headers of a failed request are written to stdout, which GH does not return on errors. When the rate limit is exceeded, the
+// request is retried after the time given by the Retry-After header, or with an increasing backoff. When the last
+// response says the rate limit is used up, it waits until the limit is reset before returning.
+func ghWithRateLimit(exe ghutil.Executor, sleep func(time.Duration), args ...string) (string, error) {
+	for attempt := 0; ; attempt++ {
+		out, err := exe.Command("gh", args...)
+		headers, body := splitResponse(out)
+		if err == nil {
+			waitForRateLimitReset(headers, sleep)
+			return body, nil
+		}
+		if !strings.Contains(strings.ToLower(out), "rate limit") {
+			return "", err
+		}
+		if attempt == maxRateLimitRetries {
+			return "", &rateLimitError{msg: fmt.Sprintf("rate limit still exceeded after %d retries", attempt)}
+		}
+
+		wait := time.Minute * time.Duration(powInt(2, attempt))
+		if match := retryAfterRegex.FindStringSubmatch(headers); match != nil {
+			seconds, _ := strconv.Atoi(match[1])
+			wait = time.Duration(seconds) * time.Second
+		}
+		logger.Warnf("Rate limit exceeded, retrying in %s", wait)
+		sleep(wait)
+	}
+}
+
+// splitResponse splits the output of gh api -i into the headers and the body.
+func splitResponse(out string) (string, string) {
+	out = strings.ReplaceAll(out, "\r\n", "\n")
+	if !strings.HasPrefix(out, "HTTP/") {
+		return "", out
+	}
+	headers, body, _ := strings.Cut(out, "\n\n")
+	return headers, body
+}
+
+// waitForRateLimitReset waits until the rate limit is reset if the response headers say that it is used up.
+func waitForRateLimitReset(headers string, sleep func(time.Duration)) {
+	remaining, reset := "", ""
+	for _, line := range strings.Split(headers, "\n") {
+		name, value, _ := strings.Cut(line, ":")
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "x-ratelimit-remaining":
+			remaining = strings.TrimSpace(value)
+		case "x-ratelimit-reset":
+			reset = strings.TrimSpace(value)
+		}
+	}
+	if remaining != "0" {
+		return
+	}
+
+	epoch, err := strconv.ParseInt(reset, 10, 64)
+	if err != nil {
+		return
+	}
+	wait := max(time.Until(time.Unix(epoch, 0)), time.Second)
+	logger.Warnf("Rate limit used up, waiting %s until it is reset", wait.Round(time.Second))
+	sleep(wait)
+}
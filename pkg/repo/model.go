@@ -1,6 +1,87 @@
 package repo
 
-// Options represents the options for the pr command.
+// DefaultStateFile is the file clone-all records its progress in, so an interrupted run can be resumed.
+const DefaultStateFile = ".dxp-clone-all.json"
+
+// DefaultLayout clones every repository into a directory with its name in the current directory.
+const DefaultLayout = "{repo}"
+
+// Options represents the options for the clone-all command.
 type Options struct {
-	DryRun bool
+	DryRun          bool
+	Workers         int
+	Resume          bool
+	StateFile       string
+	Orgs            []string
+	Topics          []string
+	Language        string
+	Visibility      string
+	Team            string
+	PushedAfter     string
+	Exclude         []string
+	IncludeArchived bool
+	Layout          string
+}
+
+// cloneState is the progress of a clone-all run, as stored in the state file.
+type cloneState struct {
+	Pattern   string            `json:"pattern"`
+	Completed []string          `json:"completed"`
+	Failed    map[string]string `json:"failed"`
+}
+
+// SyncOptions represents the options for the sync-all command.
+type SyncOptions struct {
+	Workers int
+}
+
+// SyncStatus is the outcome of syncing a single repository.
+type SyncStatus string
+
+// The possible outcomes of syncing a repository.
+const (
+	SyncUpToDate    SyncStatus = "up to date"
+	SyncUpdated     SyncStatus = "updated"
+	SyncAhead       SyncStatus = "ahead"
+	SyncDiverged    SyncStatus = "diverged"
+	SyncDirty       SyncStatus = "dirty"
+	SyncOtherBranch SyncStatus = "other branch"
+	SyncArchived    SyncStatus = "archived"
+	SyncDeleted     SyncStatus = "deleted upstream"
+	SyncFailed      SyncStatus = "failed"
+)
+
+// SyncResult is the result of syncing the repository cloned in Directory.
+type SyncResult struct {
+	Directory  string
+	Repository string
+	Branch     string
+	Status     SyncStatus
+	Details    string
+}
+
+// ForeachOptions represents the options for the foreach command.
+type ForeachOptions struct {
+	Workers int
+	Match   []string
+
+	// Branch, CommitMessage and CreatePR commit the changes made by the command and open a pull request for them.
+	Branch        string
+	CommitMessage string
+	CreatePR      bool
+	Title         string
+	Body          string
+	Draft         bool
+	NoLint        bool
+	NoUnit        bool
+}
+
+// ForeachResult is the result of running the command in the repository cloned in Directory.
+type ForeachResult struct {
+	Directory string
+	Output    string
+	ExitCode  int
+	Outcome   string
+	Failed    bool
+	Changed   bool
 }
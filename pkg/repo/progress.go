@@ -0,0 +1,99 @@
+// Package repo provides utilities for managing repositories in gh-dxp.
+package repo
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/elhub/gh-dxp/pkg/logger"
+	"golang.org/x/term"
+)
+
+// progress tracks the state of the repositories handled by a worker pool and renders it. On a terminal, a status
+// line and the repositories currently being processed are redrawn in place; otherwise every change is logged.
+type progress struct {
+	mu          sync.Mutex
+	out         io.Writer
+	interactive bool
+	verb        string
+	total       int
+	done        int
+	failed      int
+	active      []string
+	lines       int
+}
+
+func newProgress(total int, verb string) *progress {
+	return &progress{
+		out:         os.Stderr,
+		interactive: term.IsTerminal(int(os.Stderr.Fd())),
+		verb:        verb,
+		total:       total,
+	}
+}
+
+func (p *progress) start(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.active = append(p.active, name)
+	if !p.interactive {
+		logger.Infof("%s %s", strings.ToUpper(p.verb[:1])+p.verb[1:], name)
+	}
+	p.render()
+}
+
+func (p *progress) finish(name string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if i := slices.Index(p.active, name); i >= 0 {
+		p.active = slices.Delete(p.active, i, i+1)
+	}
+	if err != nil {
+		p.failed++
+		if !p.interactive {
+			logger.Warnf("Failed %s %s: %s", p.verb, name, err.Error())
+		}
+	} else {
+		p.done++
+	}
+	p.render()
+}
+
+// stop clears the rendered lines so the summary can be printed in their place.
+func (p *progress) stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.clear()
+}
+
+func (p *progress) render() {
+	if !p.interactive {
+		return
+	}
+
+	p.clear()
+
+	queued := p.total - p.done - p.failed - len(p.active)
+	var b strings.Builder
+	fmt.Fprintf(&b, "Queued: %d  %s: %d  Done: %d  Failed: %d\n", queued,
+		strings.ToUpper(p.verb[:1])+p.verb[1:], len(p.active), p.done, p.failed)
+	for _, name := range p.active {
+		fmt.Fprintf(&b, "  %s %s...\n", p.verb, name)
+	}
+	p.lines = 1 + len(p.active)
+	_, _ = io.WriteString(p.out, b.String())
+}
+
+func (p *progress) clear() {
+	if p.interactive && p.lines > 0 {
+		fmt.Fprintf(p.out, "\033[%dA\033[J", p.lines)
+		p.lines = 0
+	}
+}
@@ -5,7 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"os"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/elhub/gh-dxp/pkg/ghutil"
@@ -14,100 +18,246 @@ import (
 )
 
 type repositoryInfo struct {
-	Name     string `json:"name"`
-	FullName string `json:"fullName"`
-	URL      string `json:"url"`
+	Name       string
+	FullName   string
+	URL        string
+	IsArchived bool
+	Visibility string
+	Language   string
+	Topics     []string
+	PushedAt   time.Time
 }
 
-// ExecuteClone carries out a clone all on the given pattern.
+// defaultWorkers is the number of repositories cloned in parallel if no worker count is given.
+const defaultWorkers = 4
+
+// ExecuteClone carries out a clone all on the given pattern. Repositories are cloned in parallel by a pool of
+// workers, and the progress is recorded in a state file so an interrupted run can be resumed with opts.Resume.
 func ExecuteClone(exe ghutil.Executor, pattern string, sleepFunction func(time.Duration), opts *Options) error {
-	repositoryInfo, err := retrieveRepositories(pattern, exe)
+	if err := validateOptions(opts); err != nil {
+		return err
+	}
+
+	repositories, err := retrieveRepositories(pattern, exe, sleepFunction, opts)
 	if err != nil {
 		return err
 	}
 
-	for _, repo := range repositoryInfo {
+	stateFile := opts.StateFile
+	if stateFile == "" {
+		stateFile = DefaultStateFile
+	}
+	state := &cloneState{Pattern: pattern, Failed: map[string]string{}}
+	if opts.Resume {
+		state, err = readCloneState(stateFile, pattern)
+		if err != nil {
+			return err
+		}
+	}
+
+	var queue []repositoryInfo
+	targets := map[string]string{}
+	for _, repo := range repositories {
+		if slices.Contains(state.Completed, repo.FullName) {
+			logger.Debugf("Skipped cloning of repository %s as it was cloned in a previous run", repo.FullName)
+			continue
+		}
+
+		// Repositories with the same name in different organizations would end up in the same directory
+		dir := targetDirectory(repo, opts.Layout)
+		if other, ok := targets[dir]; ok {
+			logger.Warnf("Skipped cloning of repository %s as %s is cloned into the same directory %s, "+
+				"use --layout {org}/{repo} to clone both", repo.FullName, other, dir)
+			continue
+		}
+		targets[dir] = repo.FullName
+
 		// If directory exits, skip cloning
-		exists, err := ghutil.DirectoryExists(repo.Name)
+		exists, err := ghutil.DirectoryExists(dir)
 		if err != nil {
 			return errors.Wrap(err, "failed to check if directory exists")
 		}
 
 		switch {
-		case !exists && opts.DryRun:
-			logger.Infof("Dry run: Clone repository %s", repo.FullName)
-		case !exists:
-			logger.Infof("Cloning repository: %s", repo.FullName)
-
-			err := cloneRepoWithRetries(repo.FullName, sleepFunction, exe)
-			if err != nil {
-				logger.Warn(err.Error())
-			}
+		case exists:
+			logger.Infof("Skipped cloning of repository %s as directory %s already exists", repo.FullName, dir)
+		case opts.DryRun:
+			logger.Infof("Dry run: Clone repository %s into %s", repo.FullName, dir)
 		default:
-			logger.Infof("Skipped cloning of repository %s as directory already exists", repo.FullName)
+			queue = append(queue, repo)
 		}
 	}
 
-	return nil
+	if len(queue) == 0 {
+		return nil
+	}
+
+	return cloneAll(exe, queue, sleepFunction, opts, state, stateFile)
 }
 
-func retrieveUserOrgs(exe ghutil.Executor) ([]string, error) {
-	type Org struct {
-		Login string `json:"login"`
+// cloneAll clones the queued repositories with a pool of workers and prints a summary of the failures at the end.
+func cloneAll(
+	exe ghutil.Executor, queue []repositoryInfo, sleepFunction func(time.Duration), opts *Options,
+	state *cloneState, stateFile string,
+) error {
+	var mu sync.Mutex
+	p := newProgress(len(queue), "cloning")
+
+	runWorkers(len(queue), opts.Workers, func(i int) {
+		repo := queue[i]
+		p.start(repo.FullName)
+		err := cloneRepoWithRetries(repo, targetDirectory(repo, opts.Layout), sleepFunction, exe)
+		p.finish(repo.FullName, err)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			state.Failed[repo.FullName] = err.Error()
+		} else {
+			delete(state.Failed, repo.FullName)
+			state.Completed = append(state.Completed, repo.FullName)
+		}
+		if err := writeCloneState(stateFile, state); err != nil {
+			logger.Warn(err.Error())
+		}
+	})
+	p.stop()
+
+	if len(state.Failed) == 0 {
+		logger.Infof("Cloned %d repositories", len(queue))
+		if err := os.Remove(stateFile); err != nil && !os.IsNotExist(err) {
+			logger.Warn("Failed to remove state file: " + err.Error())
+		}
+		return nil
 	}
 
-	res, err := exe.GH("api", "user/orgs")
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to retrieve user organizations")
+	failed := make([]string, 0, len(state.Failed))
+	for name := range state.Failed {
+		failed = append(failed, name)
 	}
+	sort.Strings(failed)
 
-	var orgs []Org
-	err = json.Unmarshal([]byte(res), &orgs)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal user organizations")
+	logger.Warnf("Failed to clone %d of %d repositories:", len(failed), len(queue))
+	for _, name := range failed {
+		logger.Warnf("  %s: %s", name, state.Failed[name])
 	}
+	logger.Infof("Run the command again with --resume to retry the failed repositories")
 
-	var orgLogins []string
-	for _, org := range orgs {
-		orgLogins = append(orgLogins, org.Login)
+	return fmt.Errorf("failed to clone %d repositories", len(failed))
+}
+
+// runWorkers calls fn for the indexes 0 to count-1 from a pool of workers and waits for all calls to return. If
+// workers is not positive, defaultWorkers is used.
+func runWorkers(count, workers int, fn func(i int)) {
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	var wg sync.WaitGroup
+	jobs := make(chan int)
+	for range min(workers, count) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
 	}
-	return orgLogins, nil
+
+	for i := range count {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
 }
 
-func retrieveRepositories(pattern string, exe ghutil.Executor) ([]repositoryInfo, error) {
-	orgs, err := retrieveUserOrgs(exe)
+// readCloneState reads the state of a previous run. A missing state file means there is nothing to resume.
+func readCloneState(stateFile, pattern string) (*cloneState, error) {
+	state := &cloneState{Pattern: pattern, Failed: map[string]string{}}
+
+	data, err := os.ReadFile(stateFile)
+	if os.IsNotExist(err) {
+		logger.Info("No previous run found to resume, cloning all repositories")
+		return state, nil
+	}
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to retrieve user organizations")
+		return nil, errors.Wrap(err, "failed to read state file")
 	}
-	// Convert to comma separated string
-	orgsStr := strings.Join(orgs, ",")
-	logger.Infof("Searching for repositories in the following organizations: %s", orgsStr)
 
-	var res string
-	if len(pattern) == 0 {
-		res, err = exe.GH("search", "repos", "--archived=false", "--json", "name,fullName,url", "--limit=1000", "--owner", orgsStr)
-	} else {
-		res, err = exe.GH("search", "repos", pattern, "--match", "name", "--archived=false", "--json", "name,fullName,url", "--limit=1000", "--owner", orgsStr)
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, errors.Wrap(err, "failed to parse state file")
 	}
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to search repositories")
+	if state.Pattern != pattern {
+		return nil, fmt.Errorf("the previous run used the pattern %q, remove %s to start a new run", state.Pattern, stateFile)
+	}
+	if state.Failed == nil {
+		state.Failed = map[string]string{}
 	}
 
-	var searchResults []repositoryInfo
-	err = json.Unmarshal([]byte(res), &searchResults)
+	logger.Infof("Resuming previous run: %d repositories already cloned", len(state.Completed))
+	return state, nil
+}
+
+func writeCloneState(stateFile string, state *cloneState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal search results for repositories")
+		return errors.Wrap(err, "failed to serialize state")
+	}
+	if err := os.WriteFile(stateFile, data, 0o600); err != nil {
+		return errors.Wrap(err, "failed to write state file")
+	}
+	return nil
+}
+
+func retrieveRepositories(pattern string, exe ghutil.Executor, sleep func(time.Duration), opts *Options) ([]repositoryInfo, error) {
+	orgs := opts.Orgs
+	if len(orgs) == 0 {
+		var err error
+		orgs, err = retrieveUserOrgs(exe)
+		if err != nil {
+			return nil, err
+		}
+	}
+	logger.Infof("Listing the repositories in the following organizations: %s", strings.Join(orgs, ","))
+
+	var teamRepos []string
+	if opts.Team != "" {
+		var err error
+		teamRepos, err = retrieveTeamRepositories(exe, opts.Team)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var matching []repositoryInfo
+	for _, org := range orgs {
+		repositories, err := listRepositories(exe, org, sleep)
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range repositories {
+			if matchesFilters(repo, pattern, opts, teamRepos) {
+				matching = append(matching, repo)
+			}
+		}
 	}
-	logger.Infof("Found %d repositories matching the pattern %s", len(searchResults), pattern)
+	logger.Infof("Found %d repositories matching the pattern %s", len(matching), pattern)
 
-	return searchResults, nil
+	return matching, nil
 }
 
-func cloneRepoWithRetries(reponame string, sleep func(time.Duration), exe ghutil.Executor) error {
+func cloneRepoWithRetries(repo repositoryInfo, dir string, sleep func(time.Duration), exe ghutil.Executor) error {
 	maxAttempts := 5
+	reponame := repo.FullName
+
+	args := []string{"repo", "clone", reponame}
+	if dir != repo.Name {
+		args = append(args, dir)
+	}
 
 	for i := 0; i <= maxAttempts; i++ {
-		_, err := exe.GH("repo", "clone", reponame)
+		_, err := exe.GH(args...)
 		if err != nil {
 			if i != maxAttempts {
 				sleepDuration := powInt(2, i)
@@ -2,11 +2,17 @@ package repo_test
 
 import (
 	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/elhub/gh-dxp/pkg/repo"
 	"github.com/elhub/gh-dxp/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -40,27 +46,19 @@ func TestRun_ExecuteClone(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
 			mockExe := new(testutils.MockExecutor)
-			mockExe.On("GH", []string{"api", "user/orgs"}).Return(`[{"login": "myorg","id": 3679327}]`, nil)
-			if tt.pattern == "" {
-				mockExe.On(
-					"GH",
-					[]string{"search", "repos", "--archived=false", "--json", "name,fullName,url", "--limit=1000", "--owner", "myorg"},
-				).Return(`[{"name": "repo1", "fullName": "myorg/repo1", "url": "https://github.com/myorg/repo1"}, {"name": "repo2",
-						"fullName": "myorg/repo2", "url": "https://github.com/myorg/repo2"}]`, nil)
-			} else {
-				mockExe.On(
-					"GH",
-					[]string{"search", "repos", tt.pattern, "--match", "name", "--archived=false", "--json", "name,fullName,url", "--limit=1000", "--owner",
-						"myorg"},
-				).Return(`[{"name": "repo1", "fullName": "myorg/repo1", "url": "https://github.com/myorg/repo1"}, {"name": "repo2",`+
-					`"fullName": "myorg/repo2", "url": "https://github.com/myorg/repo2"}]`, nil)
-			}
+			mockExe.On("GH", []string{"api", "--paginate", "user/orgs", "--jq", ".[].login"}).Return("myorg\n", nil)
+			mockListing(mockExe, "myorg", repoNode("myorg/repo1", ""), repoNode("myorg/repo2", ""),
+				repoNode("myorg/other", ""))
 			if !tt.options.DryRun { // If not dry run, we expect the repos to be cloned
 				// This is intended to test the retry logic
 				mockExe.On("GH", []string{"repo", "clone", "myorg/repo1"}).Return("", errors.New("Mocked error")).Once()
 				mockExe.On("GH", []string{"repo", "clone", "myorg/repo1"}).Return("", nil)
 				mockExe.On("GH", []string{"repo", "clone", "myorg/repo2"}).Return("", nil)
+				if tt.pattern == "" {
+					mockExe.On("GH", []string{"repo", "clone", "myorg/other"}).Return("", nil)
+				}
 			}
 
 			err := repo.ExecuteClone(mockExe, tt.pattern, mockSleep, tt.options)
@@ -70,4 +68,250 @@ func TestRun_ExecuteClone(t *testing.T) {
 	}
 }
 
+// repoNode returns a repository as listed by the GraphQL API, with the extra fields added to the JSON object.
+func repoNode(fullName, extra string) string {
+	_, name, _ := strings.Cut(fullName, "/")
+	node := fmt.Sprintf(`{"name": %q, "nameWithOwner": %q, "url": "https://github.com/%s", "isArchived": false, `+
+		`"visibility": "PRIVATE", "pushedAt": "2025-01-01T00:00:00Z"`, name, fullName, fullName)
+	if extra != "" {
+		node += ", " + extra
+	}
+	return node + "}"
+}
+
+// repoPage returns a page of the repository listing with the headers of gh api -i.
+func repoPage(total int, endCursor string, nodes ...string) string {
+	return fmt.Sprintf("HTTP/2.0 200 OK\r\nX-Ratelimit-Remaining: 4999\r\n\r\n"+
+		`{"data": {"repositoryOwner": {"repositories": {"totalCount": %d, `+
+		`"pageInfo": {"hasNextPage": %t, "endCursor": %q}, "nodes": [%s]}}}}`,
+		total, endCursor != "", endCursor, strings.Join(nodes, ","))
+}
+
+// listingArgs matches the arguments of the GraphQL request for the page after the cursor.
+func listingArgs(owner, after string) any {
+	return mock.MatchedBy(func(args []string) bool {
+		hasAfter := slices.ContainsFunc(args, func(arg string) bool { return strings.HasPrefix(arg, "after=") })
+		return len(args) > 1 && args[1] == "graphql" && slices.Contains(args, "owner="+owner) &&
+			(after == "" && !hasAfter || slices.Contains(args, "after="+after))
+	})
+}
+
+func mockListing(mockExe *testutils.MockExecutor, owner string, nodes ...string) {
+	mockExe.On("Command", "gh", listingArgs(owner, "")).Return(repoPage(len(nodes), "", nodes...), nil)
+}
+
+func mockSearch(mockExe *testutils.MockExecutor, names ...string) {
+	nodes := make([]string, 0, len(names))
+	for _, name := range names {
+		nodes = append(nodes, repoNode("myorg/"+name, ""))
+	}
+	mockExe.On("GH", []string{"api", "--paginate", "user/orgs", "--jq", ".[].login"}).Return("myorg\n", nil)
+	mockListing(mockExe, "myorg", nodes...)
+}
+
+func TestExecuteClone_FailuresAndResume(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	mockExe := new(testutils.MockExecutor)
+	mockSearch(mockExe, "repo1", "repo2", "repo3")
+	mockExe.On("GH", []string{"repo", "clone", "myorg/repo1"}).Return("", nil).Once()
+	mockExe.On("GH", []string{"repo", "clone", "myorg/repo2"}).Return("", errors.New("Mocked error")).Times(6)
+	mockExe.On("GH", []string{"repo", "clone", "myorg/repo3"}).Return("", nil).Once()
+
+	err := repo.ExecuteClone(mockExe, "", mockSleep, &repo.Options{Workers: 2})
+
+	require.EqualError(t, err, "failed to clone 1 repositories")
+	data, err := os.ReadFile(repo.DefaultStateFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "myorg/repo2")
+
+	// The resumed run only clones the repository that failed.
+	mockExe.On("GH", []string{"repo", "clone", "myorg/repo2"}).Return("", nil).Once()
+
+	err = repo.ExecuteClone(mockExe, "", mockSleep, &repo.Options{Resume: true})
+
+	require.NoError(t, err)
+	mockExe.AssertExpectations(t)
+	mockExe.AssertNumberOfCalls(t, "GH", 1+6+1+1+1+1)
+	mockExe.AssertNumberOfCalls(t, "Command", 2)
+	_, err = os.Stat(repo.DefaultStateFile)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestExecuteClone_ResumeWithDifferentPattern(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile("state.json", []byte(`{"pattern": "other", "completed": []}`), 0o600))
+
+	mockExe := new(testutils.MockExecutor)
+	mockSearch(mockExe, "repo1")
+
+	err := repo.ExecuteClone(mockExe, "", mockSleep, &repo.Options{Resume: true, StateFile: "state.json"})
+
+	require.ErrorContains(t, err, `the previous run used the pattern "other"`)
+}
+
 func mockSleep(_ time.Duration) {}
+
+func TestExecuteClone_Filters(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	topics := `"repositoryTopics": {"nodes": [{"topic": {"name": "go"}}, {"topic": {"name": "cli"}}]}`
+	golang := `"primaryLanguage": {"name": "Go"}`
+
+	mockExe := new(testutils.MockExecutor)
+	mockListing(mockExe, "org1",
+		repoNode("org1/app", golang+", "+topics),
+		repoNode("org1/app-old", golang+", "+topics),
+		repoNode("org1/app-archived", golang+", "+topics+`, "isArchived": true`),
+		repoNode("org1/app-java", `"primaryLanguage": {"name": "Java"}, `+topics),
+		repoNode("org1/app-notopic", golang),
+		repoNode("org1/app-public", golang+", "+topics+`, "visibility": "PUBLIC"`),
+		repoNode("org1/app-notteam", golang+", "+topics),
+		repoNode("org1/other", golang+", "+topics),
+	)
+	mockListing(mockExe, "org2",
+		repoNode("org2/App", golang+", "+topics),
+		repoNode("org2/app-stale", golang+", "+topics+`, "pushedAt": "2023-06-01T00:00:00Z"`),
+	)
+	mockExe.On("GH", []string{"api", "--paginate", "orgs/org1/teams/devxp/repos", "--jq", ".[].full_name"}).
+		Return("org1/app\norg2/App\norg1/app-old\norg1/app-archived\norg1/app-java\norg1/app-notopic\n"+
+			"org1/app-public\norg2/app-stale\norg1/other\n", nil)
+	mockExe.On("GH", []string{"repo", "clone", "org1/app", "org1/app"}).Return("", nil)
+	mockExe.On("GH", []string{"repo", "clone", "org1/app-archived", "org1/app-archived"}).Return("", nil)
+	mockExe.On("GH", []string{"repo", "clone", "org2/App", "org2/App"}).Return("", nil)
+
+	err := repo.ExecuteClone(mockExe, "app", mockSleep, &repo.Options{
+		Orgs:            []string{"org1", "org2"},
+		Topics:          []string{"go", "cli"},
+		Language:        "go",
+		Visibility:      "private",
+		Team:            "@org1/devxp",
+		PushedAfter:     "2024-01-01",
+		Exclude:         []string{"*-old"},
+		IncludeArchived: true,
+		Layout:          "{org}/{repo}",
+	})
+
+	require.NoError(t, err)
+	mockExe.AssertExpectations(t)
+	mockExe.AssertNumberOfCalls(t, "GH", 4)
+	mockExe.AssertNumberOfCalls(t, "Command", 2)
+}
+
+func TestExecuteClone_SameNameInFlatLayout(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	mockExe := new(testutils.MockExecutor)
+	mockListing(mockExe, "org1", repoNode("org1/app", ""))
+	mockListing(mockExe, "org2", repoNode("org2/app", ""))
+	mockExe.On("GH", []string{"repo", "clone", "org1/app"}).Return("", nil)
+
+	err := repo.ExecuteClone(mockExe, "", mockSleep, &repo.Options{Orgs: []string{"org1", "org2"}})
+
+	require.NoError(t, err)
+	mockExe.AssertExpectations(t)
+	mockExe.AssertNumberOfCalls(t, "GH", 1)
+	mockExe.AssertNumberOfCalls(t, "Command", 2)
+}
+
+func TestExecuteClone_Pagination(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	var waits []time.Duration
+	sleep := func(d time.Duration) { waits = append(waits, d) }
+	reset := time.Now().Add(time.Hour).Unix()
+
+	mockExe := new(testutils.MockExecutor)
+	// The first page uses up the rate limit, so the listing waits for the reset
+	firstPage := strings.Replace(repoPage(3, "cursor1", repoNode("myorg/repo1", "")), "X-Ratelimit-Remaining: 4999",
+		fmt.Sprintf("X-Ratelimit-Remaining: 0\r\nX-Ratelimit-Reset: %d", reset), 1)
+	mockExe.On("Command", "gh", listingArgs("myorg", "")).Return(firstPage, nil)
+	// The second page hits a secondary rate limit once
+	mockExe.On("Command", "gh", listingArgs("myorg", "cursor1")).
+		Return("HTTP/2.0 403 Forbidden\r\nRetry-After: 30\r\n\r\n{}gh: You have exceeded a secondary rate limit.",
+			errors.New("exit status 1")).Once()
+	mockExe.On("Command", "gh", listingArgs("myorg", "cursor1")).Return(repoPage(3, "cursor2", repoNode("myorg/repo2", "")), nil).Once()
+	mockExe.On("Command", "gh", listingArgs("myorg", "cursor2")).Return(repoPage(3, "", repoNode("myorg/repo3", "")), nil)
+
+	err := repo.ExecuteClone(mockExe, "", sleep, &repo.Options{Orgs: []string{"myorg"}, DryRun: true})
+
+	require.NoError(t, err)
+	mockExe.AssertExpectations(t)
+	require.Len(t, waits, 2)
+	assert.Greater(t, waits[0], 59*time.Minute)
+	assert.Equal(t, 30*time.Second, waits[1])
+}
+
+func TestExecuteClone_TruncatedByRateLimit(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	var waits []time.Duration
+	sleep := func(d time.Duration) { waits = append(waits, d) }
+
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("Command", "gh", listingArgs("myorg", "")).Return(repoPage(2, "cursor1", repoNode("myorg/repo1", "")), nil)
+	mockExe.On("Command", "gh", listingArgs("myorg", "cursor1")).
+		Return("gh: API rate limit exceeded for user ID 1. (HTTP 403)", errors.New("exit status 1"))
+	mockExe.On("GH", []string{"repo", "clone", "myorg/repo1"}).Return("", nil)
+
+	err := repo.ExecuteClone(mockExe, "", sleep, &repo.Options{Orgs: []string{"myorg"}})
+
+	require.NoError(t, err)
+	mockExe.AssertExpectations(t)
+	// The request is retried with an increasing backoff before giving up
+	assert.Equal(t, []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 16 * time.Minute}, waits)
+}
+
+func TestExecuteClone_ListingFails(t *testing.T) {
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("Command", "gh", listingArgs("unknown", "")).Return(`{"data": {"repositoryOwner": null}}`, nil)
+
+	err := repo.ExecuteClone(mockExe, "", mockSleep, &repo.Options{Orgs: []string{"unknown"}})
+
+	require.EqualError(t, err, "could not find the organization or user unknown")
+}
+
+func TestExecuteClone_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *repo.Options
+		wantErr string
+	}{
+		{
+			name:    "Invalid date",
+			options: &repo.Options{PushedAfter: "last week"},
+			wantErr: `invalid date "last week" for --pushed-after, use the format YYYY-MM-DD`,
+		},
+		{
+			name:    "Invalid visibility",
+			options: &repo.Options{Visibility: "secret"},
+			wantErr: `invalid visibility "secret", use public, private or internal`,
+		},
+		{
+			name:    "Team without organization",
+			options: &repo.Options{Team: "devxp"},
+			wantErr: `invalid team "devxp", use the format <org>/<team>`,
+		},
+		{
+			name:    "Layout without repository",
+			options: &repo.Options{Layout: "{org}"},
+			wantErr: `invalid layout "{org}", the layout must contain {repo}`,
+		},
+		{
+			name:    "Invalid exclude pattern",
+			options: &repo.Options{Exclude: []string{"[a-"}},
+			wantErr: `invalid exclude pattern "[a-"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExe := new(testutils.MockExecutor)
+
+			err := repo.ExecuteClone(mockExe, "", mockSleep, tt.options)
+
+			require.EqualError(t, err, tt.wantErr)
+			mockExe.AssertNotCalled(t, "GH", mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package repo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+// ExecuteSync updates every repository cloned in the current directory and prints a summary table. The default
+// branch is only fast-forwarded when it is checked out and the working tree is clean; everything else is reported.
+func ExecuteSync(exe ghutil.Executor, opts *SyncOptions) error {
+	dirs, err := findClonedRepositories(".")
+	if err != nil {
+		return err
+	}
+	if len(dirs) == 0 {
+		logger.Info("No repositories found in the current directory")
+		return nil
+	}
+
+	results := SyncAll(exe, dirs, opts)
+	logger.Info(FormatSyncResults(results))
+
+	failed := 0
+	for _, result := range results {
+		if result.Status == SyncFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to sync %d repositories", failed)
+	}
+	return nil
+}
+
+// SyncAll syncs the repositories in the given directories with a pool of workers. The results are returned in the
+// order of the directories.
+func SyncAll(exe ghutil.Executor, dirs []string, opts *SyncOptions) []SyncResult {
+	results := make([]SyncResult, len(dirs))
+	p := newProgress(len(dirs), "syncing")
+
+	runWorkers(len(dirs), opts.Workers, func(i int) {
+		p.start(dirs[i])
+		results[i] = syncRepository(exe, dirs[i])
+		if results[i].Status == SyncFailed {
+			p.finish(dirs[i], errors.New(results[i].Details))
+		} else {
+			p.finish(dirs[i], nil)
+		}
+	})
+	p.stop()
+
+	return results
+}
+
+// findClonedRepositories returns the subdirectories of root that are git repositories. Directories that are not
+// repositories themselves are searched one level deeper, for repositories cloned with the {org}/{repo} layout.
+func findClonedRepositories(root string) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read directory")
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if isGitRepository(filepath.Join(root, entry.Name())) {
+			dirs = append(dirs, entry.Name())
+			continue
+		}
+
+		children, err := os.ReadDir(filepath.Join(root, entry.Name()))
+		if err != nil {
+			continue
+		}
+		for _, child := range children {
+			dir := filepath.Join(entry.Name(), child.Name())
+			if child.IsDir() && isGitRepository(filepath.Join(root, dir)) {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	return dirs, nil
+}
+
+func isGitRepository(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+func syncRepository(exe ghutil.Executor, dir string) SyncResult {
+	result := SyncResult{Directory: dir, Repository: dir}
+	fail := func(msg string) SyncResult {
+		result.Status = SyncFailed
+		result.Details = msg
+		return result
+	}
+
+	url, err := exe.Command("git", "-C", dir, "remote", "get-url", "origin")
+	if err != nil {
+		return fail("no origin remote")
+	}
+	fullName, ok := repositoryFromURL(strings.TrimSpace(url))
+	if !ok {
+		return fail("origin is not a GitHub repository")
+	}
+	result.Repository = fullName
+
+	out, err := exe.GH("repo", "view", fullName, "--json", "isArchived,defaultBranchRef",
+		"--jq", `[.isArchived, .defaultBranchRef.name] | @tsv`)
+	if err != nil {
+		if strings.Contains(out, "Could not resolve to a Repository") {
+			result.Status = SyncDeleted
+			result.Details = "the repository no longer exists on GitHub"
+			return result
+		}
+		return fail("failed to retrieve repository information")
+	}
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return fail("failed to retrieve the default branch")
+	}
+	archived, defaultBranch := fields[0] == "true", fields[1]
+
+	if _, err := exe.Command("git", "-C", dir, "fetch", "--prune", "origin"); err != nil {
+		return fail("fetch failed")
+	}
+
+	result.Status, result.Details = localStatus(exe, dir, defaultBranch, &result.Branch)
+	if archived && result.Status != SyncFailed {
+		result.Details = strings.TrimSuffix("archived upstream, "+string(result.Status)+", "+result.Details, ", ")
+		result.Status = SyncArchived
+	}
+	return result
+}
+
+// localStatus compares the fetched repository in dir with its default branch on origin, and fast-forwards the
+// default branch when that is safe.
+func localStatus(exe ghutil.Executor, dir, defaultBranch string, branch *string) (SyncStatus, string) {
+	out, err := exe.Command("git", "-C", dir, "branch", "--show-current")
+	if err != nil {
+		return SyncFailed, "failed to determine the current branch"
+	}
+	*branch = strings.TrimSpace(out)
+
+	if *branch != defaultBranch {
+		return SyncOtherBranch, "not on " + defaultBranch + ", fetched only"
+	}
+
+	out, err = exe.Command("git", "-C", dir, "status", "--porcelain")
+	if err != nil {
+		return SyncFailed, "failed to determine the working tree status"
+	}
+	if changes := ghutil.ConvertTerminalOutputIntoList(strings.TrimSpace(out)); len(changes) > 0 {
+		return SyncDirty, strconv.Itoa(len(changes)) + " uncommitted changes, fetched only"
+	}
+
+	out, err = exe.Command("git", "-C", dir, "rev-list", "--left-right", "--count", "HEAD...origin/"+defaultBranch)
+	if err != nil {
+		return SyncFailed, "failed to compare with origin/" + defaultBranch
+	}
+	counts := strings.Fields(out)
+	if len(counts) != 2 {
+		return SyncFailed, "failed to compare with origin/" + defaultBranch
+	}
+	ahead, _ := strconv.Atoi(counts[0])
+	behind, _ := strconv.Atoi(counts[1])
+
+	switch {
+	case ahead > 0 && behind > 0:
+		return SyncDiverged, fmt.Sprintf("%d ahead, %d behind origin/%s", ahead, behind, defaultBranch)
+	case ahead > 0:
+		return SyncAhead, fmt.Sprintf("%d commits not pushed", ahead)
+	case behind > 0:
+		if _, err := exe.Command("git", "-C", dir, "merge", "--ff-only", "origin/"+defaultBranch); err != nil {
+			return SyncFailed, "fast-forward failed"
+		}
+		return SyncUpdated, fmt.Sprintf("fast-forwarded %d commits", behind)
+	default:
+		return SyncUpToDate, ""
+	}
+}
+
+// repositoryFromURL returns the owner/name of a GitHub remote URL.
+func repositoryFromURL(url string) (string, bool) {
+	for _, prefix := range []string{"https://github.com/", "git@github.com:", "ssh://git@github.com/"} {
+		if name, ok := strings.CutPrefix(url, prefix); ok {
+			name = strings.TrimSuffix(strings.TrimSuffix(name, "/"), ".git")
+			return name, strings.Count(name, "/") == 1
+		}
+	}
+	return "", false
+}
+
+// FormatSyncResults formats the results of a sync as a table, followed by the number of repositories per status.
+func FormatSyncResults(results []SyncResult) string {
+	var report strings.Builder
+
+	w := tabwriter.NewWriter(&report, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REPOSITORY\tBRANCH\tSTATUS\tDETAILS")
+	counts := map[SyncStatus]int{}
+	for _, result := range results {
+		counts[result.Status]++
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.Repository, result.Branch, result.Status, result.Details)
+	}
+	_ = w.Flush()
+
+	var summary []string
+	for _, status := range []SyncStatus{
+		SyncUpToDate, SyncUpdated, SyncAhead, SyncDiverged, SyncDirty, SyncOtherBranch, SyncArchived, SyncDeleted,
+		SyncFailed,
+	} {
+		if counts[status] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+	fmt.Fprintf(&report, "\nSynced %d repositories: %s", len(results), strings.Join(summary, ", "))
+
+	return report.String()
+}
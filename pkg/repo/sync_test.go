@@ -0,0 +1,115 @@
+package repo_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elhub/gh-dxp/pkg/repo"
+	"github.com/elhub/gh-dxp/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type syncMock struct {
+	repository string
+	archived   string
+	viewErr    bool
+	branch     string
+	status     string
+	counts     string
+}
+
+func mockSyncRepository(mockExe *testutils.MockExecutor, dir string, m syncMock) {
+	if m.repository == "" {
+		m.repository = "myorg/" + dir
+	}
+	git := func(out string, args ...string) {
+		mockExe.On("Command", "git", append([]string{"-C", dir}, args...)).Return(out, nil)
+	}
+
+	git("https://github.com/"+m.repository+".git\n", "remote", "get-url", "origin")
+	view := mockExe.On("GH", []string{"repo", "view", m.repository, "--json", "isArchived,defaultBranchRef",
+		"--jq", `[.isArchived, .defaultBranchRef.name] | @tsv`})
+	if m.viewErr {
+		view.Return("GraphQL: Could not resolve to a Repository with the name '"+m.repository+"'.", errors.New("exit status 1"))
+		return
+	}
+	view.Return(m.archived+"\tmain\n", nil)
+	git("", "fetch", "--prune", "origin")
+	git(m.branch+"\n", "branch", "--show-current")
+	if m.branch != "main" {
+		return
+	}
+	git(m.status, "status", "--porcelain")
+	if m.status != "" {
+		return
+	}
+	git(m.counts, "rev-list", "--left-right", "--count", "HEAD...origin/main")
+}
+
+func TestSyncAll(t *testing.T) {
+	mockExe := new(testutils.MockExecutor)
+	mockSyncRepository(mockExe, "current", syncMock{archived: "false", branch: "main", counts: "0\t0\n"})
+	mockSyncRepository(mockExe, "behind", syncMock{archived: "false", branch: "main", counts: "0\t3\n"})
+	mockExe.On("Command", "git", []string{"-C", "behind", "merge", "--ff-only", "origin/main"}).Return("", nil)
+	mockSyncRepository(mockExe, "diverged", syncMock{archived: "false", branch: "main", counts: "1\t2\n"})
+	mockSyncRepository(mockExe, "dirty", syncMock{archived: "false", branch: "main", status: " M README.md\n"})
+	mockSyncRepository(mockExe, "feature", syncMock{archived: "false", branch: "feature"})
+	mockSyncRepository(mockExe, "old", syncMock{archived: "true", branch: "main", counts: "0\t0\n"})
+	mockSyncRepository(mockExe, "gone", syncMock{viewErr: true})
+	mockExe.On("Command", "git", []string{"-C", "local", "remote", "get-url", "origin"}).
+		Return("", errors.New("error: No such remote 'origin'"))
+
+	dirs := []string{"current", "behind", "diverged", "dirty", "feature", "old", "gone", "local"}
+	results := repo.SyncAll(mockExe, dirs, &repo.SyncOptions{Workers: 3})
+
+	statuses := make([]repo.SyncStatus, len(results))
+	for i, result := range results {
+		statuses[i] = result.Status
+	}
+	assert.Equal(t, []repo.SyncStatus{
+		repo.SyncUpToDate, repo.SyncUpdated, repo.SyncDiverged, repo.SyncDirty, repo.SyncOtherBranch,
+		repo.SyncArchived, repo.SyncDeleted, repo.SyncFailed,
+	}, statuses)
+	assert.Equal(t, "fast-forwarded 3 commits", results[1].Details)
+	assert.Equal(t, "1 ahead, 2 behind origin/main", results[2].Details)
+	assert.Equal(t, "archived upstream, up to date", results[5].Details)
+	mockExe.AssertExpectations(t)
+
+	report := repo.FormatSyncResults(results)
+	assert.Contains(t, report, "myorg/behind")
+	assert.Contains(t, report, "Synced 8 repositories: 1 up to date, 1 updated, 1 diverged, 1 dirty, 1 other branch, "+
+		"1 archived, 1 deleted upstream, 1 failed")
+}
+
+func TestExecuteSync(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.MkdirAll(filepath.Join("repo1", ".git"), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join("otherorg", "repo2", ".git"), 0o750))
+	require.NoError(t, os.MkdirAll("notes", 0o750))
+
+	mockExe := new(testutils.MockExecutor)
+	mockSyncRepository(mockExe, "repo1", syncMock{archived: "false", branch: "main", counts: "0\t0\n"})
+	mockSyncRepository(mockExe, filepath.Join("otherorg", "repo2"),
+		syncMock{repository: "otherorg/repo2", archived: "false", branch: "main", counts: "0\t0\n"})
+
+	err := repo.ExecuteSync(mockExe, &repo.SyncOptions{})
+
+	require.NoError(t, err)
+	mockExe.AssertExpectations(t)
+}
+
+func TestExecuteSync_Failure(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.MkdirAll(filepath.Join("repo1", ".git"), 0o750))
+
+	mockExe := new(testutils.MockExecutor)
+	mockExe.On("Command", "git", []string{"-C", "repo1", "remote", "get-url", "origin"}).
+		Return("git@gitlab.com:myorg/repo1.git\n", nil)
+
+	err := repo.ExecuteSync(mockExe, &repo.SyncOptions{})
+
+	require.EqualError(t, err, "failed to sync 1 repositories")
+}
@@ -0,0 +1,93 @@
+// Package secrets provides scanning of outgoing changes for committed credentials.
+package secrets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+)
+
+// AllowlistFile is the file, relative to the repository root, that lists ignored findings.
+const AllowlistFile = ".dxpsecretsignore"
+
+var fingerprintPattern = regexp.MustCompile(`^[a-z0-9-]+:[0-9a-f]{16}$`)
+
+// Allowlist contains the findings and paths that should be ignored by the secret scanner.
+type Allowlist struct {
+	Fingerprints map[string]bool
+	Paths        []string
+}
+
+// Fingerprint returns a stable identifier for a finding. It is based on the rule and the secret value only, so that it
+// survives the secret being moved to another line or file.
+func (f Finding) Fingerprint() string {
+	sum := sha256.Sum256([]byte(f.Secret))
+	return f.RuleID + ":" + hex.EncodeToString(sum[:])[:16]
+}
+
+// Redacted returns the secret with all but the first four characters masked.
+func (f Finding) Redacted() string {
+	const visible = 4
+	if len(f.Secret) <= visible {
+		return strings.Repeat("*", len(f.Secret))
+	}
+	return f.Secret[:visible] + strings.Repeat("*", min(len(f.Secret)-visible, 16))
+}
+
+// ReadAllowlist reads the allowlist file. Each line is either a finding fingerprint (as printed by the scanner) or a
+// glob for paths that should not be scanned. Empty lines and text after # are ignored. A missing file yields an empty
+// allowlist.
+func ReadAllowlist(path string) (*Allowlist, error) {
+	allowlist := &Allowlist{Fingerprints: map[string]bool{}}
+
+	if !ghutil.FileExists(path) {
+		return allowlist, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if idx := strings.Index(line, "#"); idx != -1 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "":
+			continue
+		case fingerprintPattern.MatchString(line):
+			allowlist.Fingerprints[line] = true
+		default:
+			allowlist.Paths = append(allowlist.Paths, line)
+		}
+	}
+
+	return allowlist, nil
+}
+
+// Allows returns true if the finding is covered by the allowlist.
+func (a *Allowlist) Allows(f Finding) bool {
+	if a.Fingerprints[f.Fingerprint()] {
+		return true
+	}
+	return ghutil.CheckFilesMatchGlobs([]string{f.File}, a.Paths)
+}
+
+// AddToAllowlist appends the fingerprint of a finding to the allowlist file, creating the file if needed.
+func AddToAllowlist(path string, f Finding) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(f.Fingerprint() + " # " + f.RuleID + " in " + f.File + "\n")
+	return err
+}
@@ -0,0 +1,30 @@
+// Package secrets provides scanning of outgoing changes for committed credentials.
+package secrets
+
+import "regexp"
+
+// Options represents the options for the secrets scan command.
+type Options struct {
+	NonInteractive bool
+
+	// BaseBranch is the branch on origin that the changes are compared to. The default branch is used if it is empty.
+	BaseBranch string
+}
+
+// Rule describes a pattern for a type of secret. If the pattern has a capture group, the first group is treated as the
+// secret value. If MinEntropy is set, values with a lower Shannon entropy are ignored as likely placeholders.
+type Rule struct {
+	ID          string
+	Description string
+	Pattern     *regexp.Regexp
+	MinEntropy  float64
+}
+
+// Finding represents a potential secret found in the diff.
+type Finding struct {
+	File        string
+	Line        int
+	RuleID      string
+	Description string
+	Secret      string
+}
@@ -0,0 +1,116 @@
+// Package secrets provides scanning of outgoing changes for committed credentials.
+package secrets
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// genericSecretEntropy is the minimum entropy for values assigned to secret-like keys. It filters out placeholders such
+// as "changeme" or "${PASSWORD}" while catching random tokens.
+const genericSecretEntropy = 3.5
+
+// Rules is the ruleset used to detect secrets.
+var Rules = []Rule{ //nolint: gochecknoglobals // Ruleset shared by all scans.
+	{
+		ID:          "aws-access-key",
+		Description: "AWS access key ID",
+		Pattern:     regexp.MustCompile(`\b((?:AKIA|ASIA)[0-9A-Z]{16})\b`),
+	},
+	{
+		ID:          "github-token",
+		Description: "GitHub token",
+		Pattern:     regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,255})\b`),
+	},
+	{
+		ID:          "github-fine-grained-token",
+		Description: "GitHub fine-grained personal access token",
+		Pattern:     regexp.MustCompile(`\b(github_pat_[A-Za-z0-9_]{82})\b`),
+	},
+	{
+		ID:          "slack-token",
+		Description: "Slack token",
+		Pattern:     regexp.MustCompile(`\b(xox[baprs]-[A-Za-z0-9-]{10,})\b`),
+	},
+	{
+		ID:          "google-api-key",
+		Description: "Google API key",
+		Pattern:     regexp.MustCompile(`\b(AIza[0-9A-Za-z_-]{35})\b`),
+	},
+	{
+		ID:          "stripe-secret-key",
+		Description: "Stripe secret key",
+		Pattern:     regexp.MustCompile(`\b((?:sk|rk)_live_[0-9A-Za-z]{24,})\b`),
+	},
+	{
+		ID:          "private-key",
+		Description: "Private key",
+		Pattern:     regexp.MustCompile(`(-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----)`),
+	},
+	{
+		ID:          "jwt",
+		Description: "JSON Web Token",
+		Pattern:     regexp.MustCompile(`\b(eyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,})\b`),
+	},
+	{
+		ID:          "generic-secret",
+		Description: "High-entropy value assigned to a secret-like key",
+		Pattern: regexp.MustCompile(
+			`(?i)(?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key|client[_-]?secret)["']?\s*[:=]\s*["']?([^\s"',;]{8,})`,
+		),
+		MinEntropy: genericSecretEntropy,
+	},
+}
+
+// ScanLine returns the rules matched by a single line, together with the matched secret values. A secret is only
+// reported once, by the first (most specific) rule that matches it.
+func ScanLine(line string) []Finding {
+	findings := []Finding{}
+	for _, rule := range Rules {
+		for _, match := range rule.Pattern.FindAllStringSubmatch(line, -1) {
+			secret := match[0]
+			if len(match) > 1 {
+				secret = match[1]
+			}
+			if rule.MinEntropy > 0 && Entropy(secret) < rule.MinEntropy {
+				continue
+			}
+			if alreadyFound(findings, secret) {
+				continue
+			}
+			findings = append(findings, Finding{RuleID: rule.ID, Description: rule.Description, Secret: secret})
+		}
+	}
+	return findings
+}
+
+func alreadyFound(findings []Finding, secret string) bool {
+	for _, f := range findings {
+		if strings.Contains(secret, f.Secret) || strings.Contains(f.Secret, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// Entropy returns the Shannon entropy (in bits per character) of a string.
+func Entropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+
+	counts := map[rune]int{}
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
@@ -0,0 +1,172 @@
+// Package secrets provides scanning of outgoing changes for committed credentials.
+package secrets
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/elhub/gh-dxp/pkg/ghutil"
+	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+const (
+	choiceAbort     = "Abort"
+	choiceIgnore    = "Ignore once"
+	choiceAllowlist = "Add to allowlist"
+)
+
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// FoundSecretsError signifies that the scan found secrets that were not allowlisted or ignored.
+type FoundSecretsError struct {
+	Findings []Finding
+}
+
+// Signifies that the scan found secrets that were not allowlisted or ignored.
+func (e *FoundSecretsError) Error() string {
+	return fmt.Sprintf("found %d potential secret(s) in the outgoing changes", len(e.Findings))
+}
+
+// Run scans the outgoing changes for secrets and lets the user decide how to handle each finding. In non-interactive
+// mode, any finding that is not covered by the allowlist results in an error.
+func Run(exe ghutil.Executor, opts *Options) error {
+	s := ghutil.StartSpinner("Scanning outgoing changes for secrets...", "Scanned outgoing changes for secrets.")
+	findings, err := Scan(exe, opts.BaseBranch)
+	if err != nil {
+		ghutil.RemoveFinalMsg(s)
+		s.Stop()
+		return err
+	}
+	s.Stop()
+
+	allowlist, err := ReadAllowlist(AllowlistFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to read "+AllowlistFile)
+	}
+
+	remaining := []Finding{}
+	for _, f := range findings {
+		if allowlist.Allows(f) {
+			logger.Debugf("Ignoring allowlisted finding %s in %s:%d", f.RuleID, f.File, f.Line)
+			continue
+		}
+		remaining = append(remaining, f)
+	}
+
+	if len(remaining) == 0 {
+		logger.Info("No secrets found in the outgoing changes")
+		return nil
+	}
+
+	for _, f := range remaining {
+		logger.Warn(formatFinding(f))
+	}
+
+	if opts.NonInteractive {
+		return &FoundSecretsError{Findings: remaining}
+	}
+
+	return handleFindings(remaining)
+}
+
+// Scan returns the potential secrets added between the merge base with baseBranch on origin, or the default branch if
+// it is empty, and the working tree. This covers committed changes as well as staged and unstaged changes to tracked
+// files, i.e., everything that would be pushed by pr create.
+func Scan(exe ghutil.Executor, baseBranch string) ([]Finding, error) {
+	base, err := mergeBase(exe, baseBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := exe.Command("git", "diff", "--unified=0", "--no-color", "--no-ext-diff", base)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get diff")
+	}
+
+	return ScanDiff(diff), nil
+}
+
+// ScanDiff returns the potential secrets in the added lines of a unified diff.
+func ScanDiff(diff string) []Finding {
+	findings := []Finding{}
+	file := ""
+	lineNumber := 0
+
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+		case strings.HasPrefix(line, "@@"):
+			if m := hunkHeader.FindStringSubmatch(line); m != nil {
+				lineNumber, _ = strconv.Atoi(m[1])
+			}
+		case strings.HasPrefix(line, "+") && file != "/dev/null":
+			for _, f := range ScanLine(line[1:]) {
+				f.File = file
+				f.Line = lineNumber
+				findings = append(findings, f)
+			}
+			lineNumber++
+		}
+	}
+
+	return findings
+}
+
+func mergeBase(exe ghutil.Executor, baseBranch string) (string, error) {
+	if baseBranch != "" {
+		base, err := exe.Command("git", "merge-base", "HEAD", "origin/"+baseBranch)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to find merge base with origin/"+baseBranch)
+		}
+		return strings.TrimSpace(base), nil
+	}
+
+	headRef, err := exe.Command("git", "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	if err != nil {
+		// origin/HEAD is not set in all clones, so try to set it before giving up
+		if _, errSet := exe.Command("git", "remote", "set-head", "origin", "--auto"); errSet != nil {
+			return "", errors.Wrap(err, "failed to determine default branch")
+		}
+		headRef, err = exe.Command("git", "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+		if err != nil {
+			return "", errors.Wrap(err, "failed to determine default branch")
+		}
+	}
+
+	base, err := exe.Command("git", "merge-base", "HEAD", strings.TrimSpace(headRef))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to find merge base with "+strings.TrimSpace(headRef))
+	}
+	return strings.TrimSpace(base), nil
+}
+
+func handleFindings(findings []Finding) error {
+	for _, f := range findings {
+		choice, err := ghutil.AskForMultipleChoice(formatFinding(f)+"\nHow do you want to handle this?",
+			[]string{choiceAbort, choiceIgnore, choiceAllowlist})
+		if err != nil {
+			return err
+		}
+
+		switch choice {
+		case choiceIgnore:
+			logger.Infof("Ignoring %s in %s:%d for this run", f.RuleID, f.File, f.Line)
+		case choiceAllowlist:
+			if err := AddToAllowlist(AllowlistFile, f); err != nil {
+				return errors.Wrap(err, "failed to update "+AllowlistFile)
+			}
+			logger.Infof("Added %s to %s. Remember to commit it.", f.Fingerprint(), AllowlistFile)
+		default:
+			return errors.New("User aborted workflow due to potential secrets")
+		}
+	}
+	return nil
+}
+
+func formatFinding(f Finding) string {
+	return fmt.Sprintf("%s:%d: %s (%s) %s [%s]", f.File, f.Line, f.Description, f.RuleID, f.Redacted(), f.Fingerprint())
+}
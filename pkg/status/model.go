@@ -1,5 +1,7 @@
 package status
 
+import "github.com/elhub/gh-dxp/pkg/output"
+
 // Options represents the options available for the status command.
 type Options struct {
 	All      bool
@@ -7,4 +9,47 @@ type Options struct {
 	Pr       bool
 	Branches bool
 	Issue    bool
+
+	// Output prints the status as JSON instead of a text report.
+	Output output.Options
+}
+
+// Report represents the status of the current repository, as printed with --json.
+type Report struct {
+	Repository   string             `json:"repository"`
+	PullRequests *PullRequestStatus `json:"pullRequests"`
+	Branches     []string           `json:"branches"`
+	Issues       *IssueStatus       `json:"issues"`
+}
+
+// PullRequestStatus represents the pull requests relevant to the user, as returned by gh pr status.
+type PullRequestStatus struct {
+	CurrentBranch *PullRequest  `json:"currentBranch"`
+	CreatedBy     []PullRequest `json:"createdBy"`
+	NeedsReview   []PullRequest `json:"needsReview"`
+}
+
+// PullRequest represents a pull request in the status report.
+type PullRequest struct {
+	Number         int    `json:"number"`
+	Title          string `json:"title"`
+	URL            string `json:"url"`
+	State          string `json:"state"`
+	HeadRefName    string `json:"headRefName"`
+	ReviewDecision string `json:"reviewDecision"`
+}
+
+// IssueStatus represents the issues relevant to the user, as returned by gh issue status.
+type IssueStatus struct {
+	Assigned  []Issue `json:"assigned"`
+	Mentioned []Issue `json:"mentioned"`
+	Authored  []Issue `json:"authored"`
+}
+
+// Issue represents an issue in the status report.
+type Issue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	State  string `json:"state"`
 }
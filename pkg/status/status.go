@@ -2,18 +2,40 @@
 package status
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"slices"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/elhub/gh-dxp/pkg/ghutil"
 	"github.com/elhub/gh-dxp/pkg/logger"
+	"github.com/elhub/gh-dxp/pkg/output"
+	"github.com/pkg/errors"
+)
+
+const (
+	prStatusFields    = "number,title,url,state,headRefName,reviewDecision"
+	issueStatusFields = "number,title,url,state"
 )
 
 // Execute retrieves the status of the current repository.
 func Execute(exe ghutil.Executor, opts *Options) error {
 	var statusReport strings.Builder
 
+	if err := opts.Output.Validate(Fields()); err != nil {
+		return err
+	}
+
+	if opts.Output.Enabled() {
+		report, err := buildJSONReport(exe, opts.Output.Fields)
+		if err != nil {
+			return err
+		}
+		return output.Write(os.Stdout, report, &opts.Output)
+	}
+
 	if optsIsEmpty(opts) {
 		if err := promptForOptions(opts); err != nil {
 			return err
@@ -114,6 +136,59 @@ func appendIssues(exe ghutil.Executor, statusReport *strings.Builder) error {
 	return nil
 }
 
+// Fields returns the fields available for the JSON output of status.
+func Fields() []string {
+	return output.Fields(Report{})
+}
+
+// buildJSONReport retrieves the sections of the status report that are selected by the fields.
+func buildJSONReport(exe ghutil.Executor, fields []string) (*Report, error) {
+	report := &Report{}
+
+	if slices.Contains(fields, "repository") {
+		repo, err := exe.Command("git", "remote", "get-url", "origin")
+		if err != nil {
+			return nil, err
+		}
+		report.Repository = strings.TrimSpace(repo)
+	}
+
+	if slices.Contains(fields, "pullRequests") {
+		report.PullRequests = &PullRequestStatus{}
+		if err := ghJSON(exe, report.PullRequests, "pr", "status", "--json", prStatusFields); err != nil {
+			return nil, err
+		}
+	}
+
+	if slices.Contains(fields, "branches") {
+		branches, err := exe.Command("git", "branch", "-a", "--format=%(refname:short)")
+		if err != nil {
+			return nil, err
+		}
+		report.Branches = strings.Fields(branches)
+	}
+
+	if slices.Contains(fields, "issues") {
+		report.Issues = &IssueStatus{}
+		if err := ghJSON(exe, report.Issues, "issue", "status", "--json", issueStatusFields); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+func ghJSON(exe ghutil.Executor, v any, args ...string) error {
+	res, err := exe.GH(args...)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(res), v); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal the output of gh %s %s", args[0], args[1])
+	}
+	return nil
+}
+
 func optsIsEmpty(opts *Options) bool {
 	return !opts.All && !opts.Repo && !opts.Pr && !opts.Branches && !opts.Issue
 }
@@ -1,11 +1,14 @@
 package status_test
 
 import (
+	"slices"
 	"testing"
 
+	"github.com/elhub/gh-dxp/pkg/output"
 	"github.com/elhub/gh-dxp/pkg/status"
 	"github.com/elhub/gh-dxp/pkg/testutils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -80,3 +83,67 @@ func TestStatusAll(t *testing.T) {
 		})
 	}
 }
+
+func TestStatusJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		fields      []string
+		expectedErr string
+	}{
+		{
+			name:   "Selected sections only",
+			fields: []string{"branches", "pullRequests"},
+		},
+		{
+			name:   "All sections",
+			fields: []string{"repository", "pullRequests", "branches", "issues"},
+		},
+		{
+			name:        "Unknown field",
+			fields:      []string{"labels"},
+			expectedErr: "Unknown JSON field: \"labels\"\nAvailable fields:\n  branches\n  issues\n  pullRequests\n  repository",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExec := new(testutils.MockExecutor)
+			mockExec.On("Command", "git", []string{"remote", "get-url", "origin"}).
+				Return("git@github.com:elhub/demo.git\n", nil)
+			mockExec.On("GH", []string{"pr", "status", "--json", "number,title,url,state,headRefName,reviewDecision"}).
+				Return(`{"currentBranch":null,"createdBy":[{"number":1,"title":"Add feature"}],"needsReview":[]}`, nil)
+			mockExec.On("Command", "git", []string{"branch", "-a", "--format=%(refname:short)"}).
+				Return("main\nfeature\norigin/main\n", nil)
+			mockExec.On("GH", []string{"issue", "status", "--json", "number,title,url,state"}).
+				Return(`{"assigned":[],"mentioned":[],"authored":[]}`, nil)
+
+			err := status.Execute(mockExec, &status.Options{
+				Output: output.Options{Fields: tt.fields},
+			})
+
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedErr, err.Error())
+				mockExec.AssertNotCalled(t, "GH", mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			for _, call := range []struct {
+				field  string
+				method string
+				args   []any
+			}{
+				{"repository", "Command", []any{"git", []string{"remote", "get-url", "origin"}}},
+				{"pullRequests", "GH", []any{[]string{"pr", "status", "--json", "number,title,url,state,headRefName,reviewDecision"}}},
+				{"branches", "Command", []any{"git", []string{"branch", "-a", "--format=%(refname:short)"}}},
+				{"issues", "GH", []any{[]string{"issue", "status", "--json", "number,title,url,state"}}},
+			} {
+				if slices.Contains(tt.fields, call.field) {
+					mockExec.AssertCalled(t, call.method, call.args...)
+				} else {
+					mockExec.AssertNotCalled(t, call.method, call.args...)
+				}
+			}
+		})
+	}
+}